@@ -2,15 +2,80 @@
 // @generated from file api/user/v1/user.proto (package user.v1, syntax proto3)
 /* eslint-disable */
 
-import type { GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv2";
-import { fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv2";
+import type { GenEnum, GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv2";
+import { enumDesc, fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv2";
+import { file_buf_validate_validate } from "../../../buf/validate/validate_pb";
+import { file_google_protobuf_field_mask, file_google_protobuf_timestamp } from "@bufbuild/protobuf/wkt";
 import type { Message } from "@bufbuild/protobuf";
+import type { FieldMask, Timestamp } from "@bufbuild/protobuf/wkt";
 
 /**
  * Describes the file api/user/v1/user.proto.
  */
 export const file_api_user_v1_user: GenFile = /*@__PURE__*/
-  fileDesc("ChZhcGkvdXNlci92MS91c2VyLnByb3RvEgd1c2VyLnYxIiwKDVNpZ25JblJlcXVlc3QSDAoEY29kZRgBIAEoCRINCgVzdGF0ZRgCIAEoCSItCg5TaWduSW5SZXNwb25zZRINCgVzdGF0ZRgBIAEoCRIMCgRkYXRhGAIgASgJMkoKC1VzZXJTZXJ2aWNlEjsKBlNpZ25JbhIWLnVzZXIudjEuU2lnbkluUmVxdWVzdBoXLnVzZXIudjEuU2lnbkluUmVzcG9uc2UiAEJ8Cgtjb20udXNlci52MUIJVXNlclByb3RvUAFaJWNvbm5lY3QtZ28tZXhhbXBsZS9hcGkvdXNlci92MTt1c2VydjGiAgNVWFiqAgdVc2VyLlYxygIHVXNlclxWMeICE1VzZXJcVjFcR1BCTWV0YWRhdGHqAghVc2VyOjpWMWIGcHJvdG8z");
+  fileDesc("ChZhcGkvdXNlci92MS91c2VyLnByb3RvEgd1c2VyLnYxGhtidWYvdmFsaWRhdGUvdmFsaWRhdGUucHJvdG8aIGdvb2dsZS9wcm90b2J1Zi9maWVsZF9tYXNrLnByb3RvGh9nb29nbGUvcHJvdG9idWYvdGltZXN0YW1wLnByb3RvIvoBCgRVc2VyEhMKAmlkGAEgASgDQge6SAQiAiAAEhAKCHVzZXJuYW1lGAIgASgJEhkKCG5pY2tuYW1lGAMgASgJQge6SARyAhhAEhkKBWVtYWlsGAQgASgJQgq6SAdyAmAB2AEBEhsKBmF2YXRhchgFIAEoCUILukgIcgOIAQHYAQESLwoLY3JlYXRlX3RpbWUYBiABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wEi8KC3VwZGF0ZV90aW1lGAcgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIWCg5lbWFpbF92ZXJpZmllZBgIIAEoCCI+Cg1TaWduSW5SZXF1ZXN0EhUKBGNvZGUYASABKAlCB7pIBHICEAESFgoFc3RhdGUYAiABKAlCB7pIBHICEAEiLQoOU2lnbkluUmVzcG9uc2USDQoFc3RhdGUYASABKAkSDAoEZGF0YRgCIAEoCSJMCg9SZWdpc3RlclJlcXVlc3QSGwoIdXNlcm5hbWUYASABKAlCCbpIBnIEGEAQARIcCghwYXNzd29yZBgCIAEoCUIKukgHcgUYgAEQCCIwChBSZWdpc3RlclJlc3BvbnNlEgoKAmlkGAEgASgDEhAKCHVzZXJuYW1lGAIgASgJIk0KFVBhc3N3b3JkU2lnbkluUmVxdWVzdBIZCgh1c2VybmFtZRgBIAEoCUIHukgEcgIQARIZCghwYXNzd29yZBgCIAEoCUIHukgEcgIQASKoAQoWUGFzc3dvcmRTaWduSW5SZXNwb25zZRIKCgJpZBgBIAEoAxIQCgh1c2VybmFtZRgCIAEoCRISCgpzZXNzaW9uX2lkGAMgASgJEhUKDXJlZnJlc2hfdG9rZW4YBCABKAkSEgoKZXhwaXJlc19pbhgFIAEoAxIUCgxtZmFfcmVxdWlyZWQYBiABKAgSGwoTbWZhX2NoYWxsZW5nZV90b2tlbhgHIAEoCSKOAQoQVmVyaWZ5TUZBUmVxdWVzdBIgCg9jaGFsbGVuZ2VfdG9rZW4YASABKAlCB7pIBHICEAESIQoEY29kZRgCIAEoCUIRukgOcgwyCl5bMC05XXs2fSRIABIgCg1yZWNvdmVyeV9jb2RlGAMgASgJQge6SARyAhABSABCEwoKY3JlZGVudGlhbBIFukgCCAEicAoRVmVyaWZ5TUZBUmVzcG9uc2USCgoCaWQYASABKAMSEAoIdXNlcm5hbWUYAiABKAkSEgoKc2Vzc2lvbl9pZBgDIAEoCRIVCg1yZWZyZXNoX3Rva2VuGAQgASgJEhIKCmV4cGlyZXNfaW4YBSABKAMiEwoRRW5yb2xsVE9UUFJlcXVlc3QiOQoSRW5yb2xsVE9UUFJlc3BvbnNlEg4KBnNlY3JldBgBIAEoCRITCgtvdHBhdXRoX3VyaRgCIAEoCSI1ChJDb25maXJtVE9UUFJlcXVlc3QSHwoEY29kZRgBIAEoCUIRukgOcgwyCl5bMC05XXs2fSQiLQoTQ29uZmlybVRPVFBSZXNwb25zZRIWCg5yZWNvdmVyeV9jb2RlcxgBIAMoCSJBCh5SZWdlbmVyYXRlUmVjb3ZlcnlDb2Rlc1JlcXVlc3QSHwoEY29kZRgBIAEoCUIRukgOcgwyCl5bMC05XXs2fSQiOQofUmVnZW5lcmF0ZVJlY292ZXJ5Q29kZXNSZXNwb25zZRIWCg5yZWNvdmVyeV9jb2RlcxgBIAMoCSI1ChtSZXF1ZXN0UGFzc3dvcmRSZXNldFJlcXVlc3QSFgoFZW1haWwYASABKAlCB7pIBHICYAEiHgocUmVxdWVzdFBhc3N3b3JkUmVzZXRSZXNwb25zZSJQChRSZXNldFBhc3N3b3JkUmVxdWVzdBIWCgV0b2tlbhgBIAEoCUIHukgEcgIQARIgCgxuZXdfcGFzc3dvcmQYAiABKAlCCrpIB3IFEAgYgAEiFwoVUmVzZXRQYXNzd29yZFJlc3BvbnNlIh4KHFNlbmRWZXJpZmljYXRpb25FbWFpbFJlcXVlc3QiHwodU2VuZFZlcmlmaWNhdGlvbkVtYWlsUmVzcG9uc2UiLAoSVmVyaWZ5RW1haWxSZXF1ZXN0EhYKBXRva2VuGAEgASgJQge6SARyAhABIhUKE1ZlcmlmeUVtYWlsUmVzcG9uc2UiNQoTUmVmcmVzaFRva2VuUmVxdWVzdBIeCg1yZWZyZXNoX3Rva2VuGAEgASgJQge6SARyAhABIlUKFFJlZnJlc2hUb2tlblJlc3BvbnNlEhIKCnNlc3Npb25faWQYASABKAkSFQoNcmVmcmVzaF90b2tlbhgCIAEoCRISCgpleHBpcmVzX2luGAMgASgDIjgKDlNpZ25PdXRSZXF1ZXN0EhIKCnNlc3Npb25faWQYASABKAkSEgoKZXZlcnl3aGVyZRgCIAEoCCIRCg9TaWduT3V0UmVzcG9uc2UiJQoOR2V0VXNlclJlcXVlc3QSEwoCaWQYASABKANCB7pIBCICIAAiLgoPR2V0VXNlclJlc3BvbnNlEhsKBHVzZXIYASABKAsyDS51c2VyLnYxLlVzZXIingIKEVVwZGF0ZVVzZXJSZXF1ZXN0EiMKBHVzZXIYASABKAsyDS51c2VyLnYxLlVzZXJCBrpIA8gBARLjAQoLdXBkYXRlX21hc2sYAiABKAsyGi5nb29nbGUucHJvdG9idWYuRmllbGRNYXNrQrEBukitAcgBAboBpgEKEXVwZGF0ZV9tYXNrLnBhdGhzEkB1cGRhdGVfbWFzayBtdXN0IGNvbnRhaW4gYXQgbGVhc3Qgb25lIG9mIG5pY2tuYW1lLCBlbWFpbCwgYXZhdGFyGk9zaXplKHRoaXMucGF0aHMpID4gMCAmJiB0aGlzLnBhdGhzLmFsbChwLCBwIGluIFsnbmlja25hbWUnLCAnZW1haWwnLCAnYXZhdGFyJ10pIjEKElVwZGF0ZVVzZXJSZXNwb25zZRIbCgR1c2VyGAEgASgLMg0udXNlci52MS5Vc2VyIigKEURlbGV0ZVVzZXJSZXF1ZXN0EhMKAmlkGAEgASgDQge6SAQiAiAAIhQKEkRlbGV0ZVVzZXJSZXNwb25zZSIoChFVbmxvY2tVc2VyUmVxdWVzdBITCgJpZBgBIAEoA0IHukgEIgIgACIUChJVbmxvY2tVc2VyUmVzcG9uc2UiQgoQTGlzdFVzZXJzUmVxdWVzdBIaCglwYWdlX3NpemUYASABKAVCB7pIBBoCKAASEgoKcGFnZV90b2tlbhgCIAEoCSJKChFMaXN0VXNlcnNSZXNwb25zZRIcCgV1c2VycxgBIAMoCzINLnVzZXIudjEuVXNlchIXCg9uZXh0X3BhZ2VfdG9rZW4YAiABKAkiXAoSU2VhcmNoVXNlcnNSZXF1ZXN0EhYKBXF1ZXJ5GAEgASgJQge6SARyAhABEhoKCXBhZ2Vfc2l6ZRgCIAEoBUIHukgEGgIoABISCgpwYWdlX3Rva2VuGAMgASgJIuICChNTZWFyY2hVc2Vyc1Jlc3BvbnNlEi4KBGhpdHMYASADKAsyIC51c2VyLnYxLlNlYXJjaFVzZXJzUmVzcG9uc2UuSGl0Eg0KBXRvdGFsGAIgASgDEhcKD25leHRfcGFnZV90b2tlbhgDIAEoCRrSAQoDSGl0EhsKBHVzZXIYASABKAsyDS51c2VyLnYxLlVzZXISDQoFc2NvcmUYAiABKAISRAoKaGlnaGxpZ2h0cxgDIAMoCzIwLnVzZXIudjEuU2VhcmNoVXNlcnNSZXNwb25zZS5IaXQuSGlnaGxpZ2h0c0VudHJ5GlkKD0hpZ2hsaWdodHNFbnRyeRILCgNrZXkYASABKAkSNQoFdmFsdWUYAiABKAsyJi51c2VyLnYxLlNlYXJjaFVzZXJzUmVzcG9uc2UuSGlnaGxpZ2h0OgI4ARoeCglIaWdobGlnaHQSEQoJZnJhZ21lbnRzGAEgAygJKqAGCgtFcnJvclJlYXNvbhIcChhFUlJPUl9SRUFTT05fVU5TUEVDSUZJRUQQABIhCh1FUlJPUl9SRUFTT05fSU5WQUxJRF9BUkdVTUVOVBABEiQKIEVSUk9SX1JFQVNPTl9VU0VSX0FMUkVBRFlfRVhJU1RTEAISHwobRVJST1JfUkVBU09OX1VTRVJfTk9UX0ZPVU5EEAMSJAogRVJST1JfUkVBU09OX0lOVkFMSURfQ1JFREVOVElBTFMQBBIjCh9FUlJPUl9SRUFTT05fSU5WQUxJRF9QQUdFX1RPS0VOEAUSIgoeRVJST1JfUkVBU09OX1NFU1NJT05fTk9UX0ZPVU5EEAYSJgoiRVJST1JfUkVBU09OX0lOVkFMSURfUkVGUkVTSF9UT0tFThAHEiUKIUVSUk9SX1JFQVNPTl9SRUZSRVNIX1RPS0VOX1JFVVNFRBAIEiMKH0VSUk9SX1JFQVNPTl9FTVBUWV9TRUFSQ0hfUVVFUlkQCRIiCh5FUlJPUl9SRUFTT05fUEVSTUlTU0lPTl9ERU5JRUQQChIdChlFUlJPUl9SRUFTT05fUkFURV9MSU1JVEVEEAsSHwobRVJST1JfUkVBU09OX0FDQ09VTlRfTE9DS0VEEAwSIAocRVJST1JfUkVBU09OX01GQV9VTkFWQUlMQUJMRRANEiEKHUVSUk9SX1JFQVNPTl9NRkFfTk9UX0VOUk9MTEVEEA4SJAogRVJST1JfUkVBU09OX01GQV9BTFJFQURZX0VOQUJMRUQQDxIhCh1FUlJPUl9SRUFTT05fSU5WQUxJRF9NRkFfQ09ERRAQEiYKIkVSUk9SX1JFQVNPTl9JTlZBTElEX01GQV9DSEFMTEVOR0UQERIeChpFUlJPUl9SRUFTT05fSU5WQUxJRF9UT0tFThASEh4KGkVSUk9SX1JFQVNPTl9FTUFJTF9OT1RfU0VUEBMSJwojRVJST1JfUkVBU09OX0VNQUlMX0FMUkVBRFlfVkVSSUZJRUQQFBIjCh9FUlJPUl9SRUFTT05fU0VBUkNIX1VOQVZBSUxBQkxFEBUy2AsKC1VzZXJTZXJ2aWNlEjsKBlNpZ25JbhIWLnVzZXIudjEuU2lnbkluUmVxdWVzdBoXLnVzZXIudjEuU2lnbkluUmVzcG9uc2UiABJBCghSZWdpc3RlchIYLnVzZXIudjEuUmVnaXN0ZXJSZXF1ZXN0GhkudXNlci52MS5SZWdpc3RlclJlc3BvbnNlIgASUwoOUGFzc3dvcmRTaWduSW4SHi51c2VyLnYxLlBhc3N3b3JkU2lnbkluUmVxdWVzdBofLnVzZXIudjEuUGFzc3dvcmRTaWduSW5SZXNwb25zZSIAEk0KDFJlZnJlc2hUb2tlbhIcLnVzZXIudjEuUmVmcmVzaFRva2VuUmVxdWVzdBodLnVzZXIudjEuUmVmcmVzaFRva2VuUmVzcG9uc2UiABI+CgdTaWduT3V0EhcudXNlci52MS5TaWduT3V0UmVxdWVzdBoYLnVzZXIudjEuU2lnbk91dFJlc3BvbnNlIgASPgoHR2V0VXNlchIXLnVzZXIudjEuR2V0VXNlclJlcXVlc3QaGC51c2VyLnYxLkdldFVzZXJSZXNwb25zZSIAEkcKClVwZGF0ZVVzZXISGi51c2VyLnYxLlVwZGF0ZVVzZXJSZXF1ZXN0GhsudXNlci52MS5VcGRhdGVVc2VyUmVzcG9uc2UiABJHCgpEZWxldGVVc2VyEhoudXNlci52MS5EZWxldGVVc2VyUmVxdWVzdBobLnVzZXIudjEuRGVsZXRlVXNlclJlc3BvbnNlIgASRAoJTGlzdFVzZXJzEhkudXNlci52MS5MaXN0VXNlcnNSZXF1ZXN0GhoudXNlci52MS5MaXN0VXNlcnNSZXNwb25zZSIAEkoKC1NlYXJjaFVzZXJzEhsudXNlci52MS5TZWFyY2hVc2Vyc1JlcXVlc3QaHC51c2VyLnYxLlNlYXJjaFVzZXJzUmVzcG9uc2UiABJHCgpVbmxvY2tVc2VyEhoudXNlci52MS5VbmxvY2tVc2VyUmVxdWVzdBobLnVzZXIudjEuVW5sb2NrVXNlclJlc3BvbnNlIgASRAoJVmVyaWZ5TUZBEhkudXNlci52MS5WZXJpZnlNRkFSZXF1ZXN0GhoudXNlci52MS5WZXJpZnlNRkFSZXNwb25zZSIAEkcKCkVucm9sbFRPVFASGi51c2VyLnYxLkVucm9sbFRPVFBSZXF1ZXN0GhsudXNlci52MS5FbnJvbGxUT1RQUmVzcG9uc2UiABJKCgtDb25maXJtVE9UUBIbLnVzZXIudjEuQ29uZmlybVRPVFBSZXF1ZXN0GhwudXNlci52MS5Db25maXJtVE9UUFJlc3BvbnNlIgASbgoXUmVnZW5lcmF0ZVJlY292ZXJ5Q29kZXMSJy51c2VyLnYxLlJlZ2VuZXJhdGVSZWNvdmVyeUNvZGVzUmVxdWVzdBooLnVzZXIudjEuUmVnZW5lcmF0ZVJlY292ZXJ5Q29kZXNSZXNwb25zZSIAEmUKFFJlcXVlc3RQYXNzd29yZFJlc2V0EiQudXNlci52MS5SZXF1ZXN0UGFzc3dvcmRSZXNldFJlcXVlc3QaJS51c2VyLnYxLlJlcXVlc3RQYXNzd29yZFJlc2V0UmVzcG9uc2UiABJQCg1SZXNldFBhc3N3b3JkEh0udXNlci52MS5SZXNldFBhc3N3b3JkUmVxdWVzdBoeLnVzZXIudjEuUmVzZXRQYXNzd29yZFJlc3BvbnNlIgASaAoVU2VuZFZlcmlmaWNhdGlvbkVtYWlsEiUudXNlci52MS5TZW5kVmVyaWZpY2F0aW9uRW1haWxSZXF1ZXN0GiYudXNlci52MS5TZW5kVmVyaWZpY2F0aW9uRW1haWxSZXNwb25zZSIAEkoKC1ZlcmlmeUVtYWlsEhsudXNlci52MS5WZXJpZnlFbWFpbFJlcXVlc3QaHC51c2VyLnYxLlZlcmlmeUVtYWlsUmVzcG9uc2UiAEJ8Cgtjb20udXNlci52MUIJVXNlclByb3RvUAFaJWNvbm5lY3QtZ28tZXhhbXBsZS9hcGkvdXNlci92MTt1c2VydjGiAgNVWFiqAgdVc2VyLlYxygIHVXNlclxWMeICE1VzZXJcVjFcR1BCTWV0YWRhdGHqAghVc2VyOjpWMWIGcHJvdG8z", [file_buf_validate_validate, file_google_protobuf_field_mask, file_google_protobuf_timestamp]);
+
+/**
+ * User 用户资料
+ *
+ * @generated from message user.v1.User
+ */
+export type User = Message<"user.v1.User"> & {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id: bigint;
+
+  /**
+   * 用户名，创建后不可修改
+   *
+   * @generated from field: string username = 2;
+   */
+  username: string;
+
+  /**
+   * @generated from field: string nickname = 3;
+   */
+  nickname: string;
+
+  /**
+   * 为空表示未设置
+   *
+   * @generated from field: string email = 4;
+   */
+  email: string;
+
+  /**
+   * 头像地址，为空表示未设置
+   *
+   * @generated from field: string avatar = 5;
+   */
+  avatar: string;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp create_time = 6;
+   */
+  createTime?: Timestamp;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp update_time = 7;
+   */
+  updateTime?: Timestamp;
+
+  /**
+   * 邮箱是否已通过 VerifyEmail 验证，修改邮箱后重置为 false
+   *
+   * @generated from field: bool email_verified = 8;
+   */
+  emailVerified: boolean;
+};
+
+/**
+ * Describes the message user.v1.User.
+ * Use `create(UserSchema)` to create a new message.
+ */
+export const UserSchema: GenMessage<User> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 0);
 
 /**
  * @generated from message user.v1.SignInRequest
@@ -32,7 +97,7 @@ export type SignInRequest = Message<"user.v1.SignInRequest"> & {
  * Use `create(SignInRequestSchema)` to create a new message.
  */
 export const SignInRequestSchema: GenMessage<SignInRequest> = /*@__PURE__*/
-  messageDesc(file_api_user_v1_user, 0);
+  messageDesc(file_api_user_v1_user, 1);
 
 /**
  * @generated from message user.v1.SignInResponse
@@ -54,19 +119,1180 @@ export type SignInResponse = Message<"user.v1.SignInResponse"> & {
  * Use `create(SignInResponseSchema)` to create a new message.
  */
 export const SignInResponseSchema: GenMessage<SignInResponse> = /*@__PURE__*/
-  messageDesc(file_api_user_v1_user, 1);
+  messageDesc(file_api_user_v1_user, 2);
 
 /**
- * @generated from service user.v1.UserService
+ * @generated from message user.v1.RegisterRequest
  */
-export const UserService: GenService<{
+export type RegisterRequest = Message<"user.v1.RegisterRequest"> & {
   /**
-   * @generated from rpc user.v1.UserService.SignIn
+   * @generated from field: string username = 1;
    */
-  signIn: {
-    methodKind: "unary";
-    input: typeof SignInRequestSchema;
-    output: typeof SignInResponseSchema;
+  username: string;
+
+  /**
+   * @generated from field: string password = 2;
+   */
+  password: string;
+};
+
+/**
+ * Describes the message user.v1.RegisterRequest.
+ * Use `create(RegisterRequestSchema)` to create a new message.
+ */
+export const RegisterRequestSchema: GenMessage<RegisterRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 3);
+
+/**
+ * @generated from message user.v1.RegisterResponse
+ */
+export type RegisterResponse = Message<"user.v1.RegisterResponse"> & {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id: bigint;
+
+  /**
+   * @generated from field: string username = 2;
+   */
+  username: string;
+};
+
+/**
+ * Describes the message user.v1.RegisterResponse.
+ * Use `create(RegisterResponseSchema)` to create a new message.
+ */
+export const RegisterResponseSchema: GenMessage<RegisterResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 4);
+
+/**
+ * @generated from message user.v1.PasswordSignInRequest
+ */
+export type PasswordSignInRequest = Message<"user.v1.PasswordSignInRequest"> & {
+  /**
+   * @generated from field: string username = 1;
+   */
+  username: string;
+
+  /**
+   * @generated from field: string password = 2;
+   */
+  password: string;
+};
+
+/**
+ * Describes the message user.v1.PasswordSignInRequest.
+ * Use `create(PasswordSignInRequestSchema)` to create a new message.
+ */
+export const PasswordSignInRequestSchema: GenMessage<PasswordSignInRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 5);
+
+/**
+ * @generated from message user.v1.PasswordSignInResponse
+ */
+export type PasswordSignInResponse = Message<"user.v1.PasswordSignInResponse"> & {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id: bigint;
+
+  /**
+   * @generated from field: string username = 2;
+   */
+  username: string;
+
+  /**
+   * 不透明的会话 ID，以 Bearer 方式携带
+   *
+   * @generated from field: string session_id = 3;
+   */
+  sessionId: string;
+
+  /**
+   * @generated from field: string refresh_token = 4;
+   */
+  refreshToken: string;
+
+  /**
+   * 会话有效期（秒）
+   *
+   * @generated from field: int64 expires_in = 5;
+   */
+  expiresIn: bigint;
+
+  /**
+   * 为 true 时账号开启了 MFA，不签发会话，需要携带 mfa_challenge_token 调用 VerifyMFA 完成登录
+   *
+   * @generated from field: bool mfa_required = 6;
+   */
+  mfaRequired: boolean;
+
+  /**
+   * @generated from field: string mfa_challenge_token = 7;
+   */
+  mfaChallengeToken: string;
+};
+
+/**
+ * Describes the message user.v1.PasswordSignInResponse.
+ * Use `create(PasswordSignInResponseSchema)` to create a new message.
+ */
+export const PasswordSignInResponseSchema: GenMessage<PasswordSignInResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 6);
+
+/**
+ * @generated from message user.v1.VerifyMFARequest
+ */
+export type VerifyMFARequest = Message<"user.v1.VerifyMFARequest"> & {
+  /**
+   * PasswordSignIn 返回的 mfa_challenge_token
+   *
+   * @generated from field: string challenge_token = 1;
+   */
+  challengeToken: string;
+
+  /**
+   * @generated from oneof user.v1.VerifyMFARequest.credential
+   */
+  credential: {
+    /**
+     * 验证器应用中的 6 位验证码
+     *
+     * @generated from field: string code = 2;
+     */
+    value: string;
+    case: "code";
+  } | {
+    /**
+     * 一次性恢复码，使用后失效
+     *
+     * @generated from field: string recovery_code = 3;
+     */
+    value: string;
+    case: "recoveryCode";
+  } | { case: undefined; value?: undefined };
+};
+
+/**
+ * Describes the message user.v1.VerifyMFARequest.
+ * Use `create(VerifyMFARequestSchema)` to create a new message.
+ */
+export const VerifyMFARequestSchema: GenMessage<VerifyMFARequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 7);
+
+/**
+ * @generated from message user.v1.VerifyMFAResponse
+ */
+export type VerifyMFAResponse = Message<"user.v1.VerifyMFAResponse"> & {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id: bigint;
+
+  /**
+   * @generated from field: string username = 2;
+   */
+  username: string;
+
+  /**
+   * @generated from field: string session_id = 3;
+   */
+  sessionId: string;
+
+  /**
+   * @generated from field: string refresh_token = 4;
+   */
+  refreshToken: string;
+
+  /**
+   * @generated from field: int64 expires_in = 5;
+   */
+  expiresIn: bigint;
+};
+
+/**
+ * Describes the message user.v1.VerifyMFAResponse.
+ * Use `create(VerifyMFAResponseSchema)` to create a new message.
+ */
+export const VerifyMFAResponseSchema: GenMessage<VerifyMFAResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 8);
+
+/**
+ * @generated from message user.v1.EnrollTOTPRequest
+ */
+export type EnrollTOTPRequest = Message<"user.v1.EnrollTOTPRequest"> & {
+};
+
+/**
+ * Describes the message user.v1.EnrollTOTPRequest.
+ * Use `create(EnrollTOTPRequestSchema)` to create a new message.
+ */
+export const EnrollTOTPRequestSchema: GenMessage<EnrollTOTPRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 9);
+
+/**
+ * @generated from message user.v1.EnrollTOTPResponse
+ */
+export type EnrollTOTPResponse = Message<"user.v1.EnrollTOTPResponse"> & {
+  /**
+   * base32 编码的密钥，供无法扫码时手动输入
+   *
+   * @generated from field: string secret = 1;
+   */
+  secret: string;
+
+  /**
+   * 验证器应用扫码使用的 otpauth URI
+   *
+   * @generated from field: string otpauth_uri = 2;
+   */
+  otpauthUri: string;
+};
+
+/**
+ * Describes the message user.v1.EnrollTOTPResponse.
+ * Use `create(EnrollTOTPResponseSchema)` to create a new message.
+ */
+export const EnrollTOTPResponseSchema: GenMessage<EnrollTOTPResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 10);
+
+/**
+ * @generated from message user.v1.ConfirmTOTPRequest
+ */
+export type ConfirmTOTPRequest = Message<"user.v1.ConfirmTOTPRequest"> & {
+  /**
+   * @generated from field: string code = 1;
+   */
+  code: string;
+};
+
+/**
+ * Describes the message user.v1.ConfirmTOTPRequest.
+ * Use `create(ConfirmTOTPRequestSchema)` to create a new message.
+ */
+export const ConfirmTOTPRequestSchema: GenMessage<ConfirmTOTPRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 11);
+
+/**
+ * @generated from message user.v1.ConfirmTOTPResponse
+ */
+export type ConfirmTOTPResponse = Message<"user.v1.ConfirmTOTPResponse"> & {
+  /**
+   * 一次性恢复码，只返回这一次，丢失验证器时用于登录
+   *
+   * @generated from field: repeated string recovery_codes = 1;
+   */
+  recoveryCodes: string[];
+};
+
+/**
+ * Describes the message user.v1.ConfirmTOTPResponse.
+ * Use `create(ConfirmTOTPResponseSchema)` to create a new message.
+ */
+export const ConfirmTOTPResponseSchema: GenMessage<ConfirmTOTPResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 12);
+
+/**
+ * @generated from message user.v1.RegenerateRecoveryCodesRequest
+ */
+export type RegenerateRecoveryCodesRequest = Message<"user.v1.RegenerateRecoveryCodesRequest"> & {
+  /**
+   * 当前的 TOTP 验证码
+   *
+   * @generated from field: string code = 1;
+   */
+  code: string;
+};
+
+/**
+ * Describes the message user.v1.RegenerateRecoveryCodesRequest.
+ * Use `create(RegenerateRecoveryCodesRequestSchema)` to create a new message.
+ */
+export const RegenerateRecoveryCodesRequestSchema: GenMessage<RegenerateRecoveryCodesRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 13);
+
+/**
+ * @generated from message user.v1.RegenerateRecoveryCodesResponse
+ */
+export type RegenerateRecoveryCodesResponse = Message<"user.v1.RegenerateRecoveryCodesResponse"> & {
+  /**
+   * 新的恢复码，之前的恢复码全部失效
+   *
+   * @generated from field: repeated string recovery_codes = 1;
+   */
+  recoveryCodes: string[];
+};
+
+/**
+ * Describes the message user.v1.RegenerateRecoveryCodesResponse.
+ * Use `create(RegenerateRecoveryCodesResponseSchema)` to create a new message.
+ */
+export const RegenerateRecoveryCodesResponseSchema: GenMessage<RegenerateRecoveryCodesResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 14);
+
+/**
+ * @generated from message user.v1.RequestPasswordResetRequest
+ */
+export type RequestPasswordResetRequest = Message<"user.v1.RequestPasswordResetRequest"> & {
+  /**
+   * @generated from field: string email = 1;
+   */
+  email: string;
+};
+
+/**
+ * Describes the message user.v1.RequestPasswordResetRequest.
+ * Use `create(RequestPasswordResetRequestSchema)` to create a new message.
+ */
+export const RequestPasswordResetRequestSchema: GenMessage<RequestPasswordResetRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 15);
+
+/**
+ * @generated from message user.v1.RequestPasswordResetResponse
+ */
+export type RequestPasswordResetResponse = Message<"user.v1.RequestPasswordResetResponse"> & {
+};
+
+/**
+ * Describes the message user.v1.RequestPasswordResetResponse.
+ * Use `create(RequestPasswordResetResponseSchema)` to create a new message.
+ */
+export const RequestPasswordResetResponseSchema: GenMessage<RequestPasswordResetResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 16);
+
+/**
+ * @generated from message user.v1.ResetPasswordRequest
+ */
+export type ResetPasswordRequest = Message<"user.v1.ResetPasswordRequest"> & {
+  /**
+   * 重置密码邮件中的令牌
+   *
+   * @generated from field: string token = 1;
+   */
+  token: string;
+
+  /**
+   * @generated from field: string new_password = 2;
+   */
+  newPassword: string;
+};
+
+/**
+ * Describes the message user.v1.ResetPasswordRequest.
+ * Use `create(ResetPasswordRequestSchema)` to create a new message.
+ */
+export const ResetPasswordRequestSchema: GenMessage<ResetPasswordRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 17);
+
+/**
+ * @generated from message user.v1.ResetPasswordResponse
+ */
+export type ResetPasswordResponse = Message<"user.v1.ResetPasswordResponse"> & {
+};
+
+/**
+ * Describes the message user.v1.ResetPasswordResponse.
+ * Use `create(ResetPasswordResponseSchema)` to create a new message.
+ */
+export const ResetPasswordResponseSchema: GenMessage<ResetPasswordResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 18);
+
+/**
+ * @generated from message user.v1.SendVerificationEmailRequest
+ */
+export type SendVerificationEmailRequest = Message<"user.v1.SendVerificationEmailRequest"> & {
+};
+
+/**
+ * Describes the message user.v1.SendVerificationEmailRequest.
+ * Use `create(SendVerificationEmailRequestSchema)` to create a new message.
+ */
+export const SendVerificationEmailRequestSchema: GenMessage<SendVerificationEmailRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 19);
+
+/**
+ * @generated from message user.v1.SendVerificationEmailResponse
+ */
+export type SendVerificationEmailResponse = Message<"user.v1.SendVerificationEmailResponse"> & {
+};
+
+/**
+ * Describes the message user.v1.SendVerificationEmailResponse.
+ * Use `create(SendVerificationEmailResponseSchema)` to create a new message.
+ */
+export const SendVerificationEmailResponseSchema: GenMessage<SendVerificationEmailResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 20);
+
+/**
+ * @generated from message user.v1.VerifyEmailRequest
+ */
+export type VerifyEmailRequest = Message<"user.v1.VerifyEmailRequest"> & {
+  /**
+   * 验证邮件中的令牌
+   *
+   * @generated from field: string token = 1;
+   */
+  token: string;
+};
+
+/**
+ * Describes the message user.v1.VerifyEmailRequest.
+ * Use `create(VerifyEmailRequestSchema)` to create a new message.
+ */
+export const VerifyEmailRequestSchema: GenMessage<VerifyEmailRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 21);
+
+/**
+ * @generated from message user.v1.VerifyEmailResponse
+ */
+export type VerifyEmailResponse = Message<"user.v1.VerifyEmailResponse"> & {
+};
+
+/**
+ * Describes the message user.v1.VerifyEmailResponse.
+ * Use `create(VerifyEmailResponseSchema)` to create a new message.
+ */
+export const VerifyEmailResponseSchema: GenMessage<VerifyEmailResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 22);
+
+/**
+ * @generated from message user.v1.RefreshTokenRequest
+ */
+export type RefreshTokenRequest = Message<"user.v1.RefreshTokenRequest"> & {
+  /**
+   * @generated from field: string refresh_token = 1;
+   */
+  refreshToken: string;
+};
+
+/**
+ * Describes the message user.v1.RefreshTokenRequest.
+ * Use `create(RefreshTokenRequestSchema)` to create a new message.
+ */
+export const RefreshTokenRequestSchema: GenMessage<RefreshTokenRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 23);
+
+/**
+ * @generated from message user.v1.RefreshTokenResponse
+ */
+export type RefreshTokenResponse = Message<"user.v1.RefreshTokenResponse"> & {
+  /**
+   * @generated from field: string session_id = 1;
+   */
+  sessionId: string;
+
+  /**
+   * @generated from field: string refresh_token = 2;
+   */
+  refreshToken: string;
+
+  /**
+   * @generated from field: int64 expires_in = 3;
+   */
+  expiresIn: bigint;
+};
+
+/**
+ * Describes the message user.v1.RefreshTokenResponse.
+ * Use `create(RefreshTokenResponseSchema)` to create a new message.
+ */
+export const RefreshTokenResponseSchema: GenMessage<RefreshTokenResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 24);
+
+/**
+ * @generated from message user.v1.SignOutRequest
+ */
+export type SignOutRequest = Message<"user.v1.SignOutRequest"> & {
+  /**
+   * 为空时退出当前 Authorization 头所携带的会话
+   *
+   * @generated from field: string session_id = 1;
+   */
+  sessionId: string;
+
+  /**
+   * 为 true 时退出该用户在所有设备上的会话
+   *
+   * @generated from field: bool everywhere = 2;
+   */
+  everywhere: boolean;
+};
+
+/**
+ * Describes the message user.v1.SignOutRequest.
+ * Use `create(SignOutRequestSchema)` to create a new message.
+ */
+export const SignOutRequestSchema: GenMessage<SignOutRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 25);
+
+/**
+ * @generated from message user.v1.SignOutResponse
+ */
+export type SignOutResponse = Message<"user.v1.SignOutResponse"> & {
+};
+
+/**
+ * Describes the message user.v1.SignOutResponse.
+ * Use `create(SignOutResponseSchema)` to create a new message.
+ */
+export const SignOutResponseSchema: GenMessage<SignOutResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 26);
+
+/**
+ * @generated from message user.v1.GetUserRequest
+ */
+export type GetUserRequest = Message<"user.v1.GetUserRequest"> & {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id: bigint;
+};
+
+/**
+ * Describes the message user.v1.GetUserRequest.
+ * Use `create(GetUserRequestSchema)` to create a new message.
+ */
+export const GetUserRequestSchema: GenMessage<GetUserRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 27);
+
+/**
+ * @generated from message user.v1.GetUserResponse
+ */
+export type GetUserResponse = Message<"user.v1.GetUserResponse"> & {
+  /**
+   * @generated from field: user.v1.User user = 1;
+   */
+  user?: User;
+};
+
+/**
+ * Describes the message user.v1.GetUserResponse.
+ * Use `create(GetUserResponseSchema)` to create a new message.
+ */
+export const GetUserResponseSchema: GenMessage<GetUserResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 28);
+
+/**
+ * @generated from message user.v1.UpdateUserRequest
+ */
+export type UpdateUserRequest = Message<"user.v1.UpdateUserRequest"> & {
+  /**
+   * user.id 指定要更新的用户
+   *
+   * @generated from field: user.v1.User user = 1;
+   */
+  user?: User;
+
+  /**
+   * 要更新的字段，支持 nickname、email、avatar
+   *
+   * @generated from field: google.protobuf.FieldMask update_mask = 2;
+   */
+  updateMask?: FieldMask;
+};
+
+/**
+ * Describes the message user.v1.UpdateUserRequest.
+ * Use `create(UpdateUserRequestSchema)` to create a new message.
+ */
+export const UpdateUserRequestSchema: GenMessage<UpdateUserRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 29);
+
+/**
+ * @generated from message user.v1.UpdateUserResponse
+ */
+export type UpdateUserResponse = Message<"user.v1.UpdateUserResponse"> & {
+  /**
+   * @generated from field: user.v1.User user = 1;
+   */
+  user?: User;
+};
+
+/**
+ * Describes the message user.v1.UpdateUserResponse.
+ * Use `create(UpdateUserResponseSchema)` to create a new message.
+ */
+export const UpdateUserResponseSchema: GenMessage<UpdateUserResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 30);
+
+/**
+ * @generated from message user.v1.DeleteUserRequest
+ */
+export type DeleteUserRequest = Message<"user.v1.DeleteUserRequest"> & {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id: bigint;
+};
+
+/**
+ * Describes the message user.v1.DeleteUserRequest.
+ * Use `create(DeleteUserRequestSchema)` to create a new message.
+ */
+export const DeleteUserRequestSchema: GenMessage<DeleteUserRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 31);
+
+/**
+ * @generated from message user.v1.DeleteUserResponse
+ */
+export type DeleteUserResponse = Message<"user.v1.DeleteUserResponse"> & {
+};
+
+/**
+ * Describes the message user.v1.DeleteUserResponse.
+ * Use `create(DeleteUserResponseSchema)` to create a new message.
+ */
+export const DeleteUserResponseSchema: GenMessage<DeleteUserResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 32);
+
+/**
+ * @generated from message user.v1.UnlockUserRequest
+ */
+export type UnlockUserRequest = Message<"user.v1.UnlockUserRequest"> & {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id: bigint;
+};
+
+/**
+ * Describes the message user.v1.UnlockUserRequest.
+ * Use `create(UnlockUserRequestSchema)` to create a new message.
+ */
+export const UnlockUserRequestSchema: GenMessage<UnlockUserRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 33);
+
+/**
+ * @generated from message user.v1.UnlockUserResponse
+ */
+export type UnlockUserResponse = Message<"user.v1.UnlockUserResponse"> & {
+};
+
+/**
+ * Describes the message user.v1.UnlockUserResponse.
+ * Use `create(UnlockUserResponseSchema)` to create a new message.
+ */
+export const UnlockUserResponseSchema: GenMessage<UnlockUserResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 34);
+
+/**
+ * @generated from message user.v1.ListUsersRequest
+ */
+export type ListUsersRequest = Message<"user.v1.ListUsersRequest"> & {
+  /**
+   * 每页数量，为 0 时使用默认值，最大 100
+   *
+   * @generated from field: int32 page_size = 1;
+   */
+  pageSize: number;
+
+  /**
+   * 上一页返回的 next_page_token，为空时从第一页开始
+   *
+   * @generated from field: string page_token = 2;
+   */
+  pageToken: string;
+};
+
+/**
+ * Describes the message user.v1.ListUsersRequest.
+ * Use `create(ListUsersRequestSchema)` to create a new message.
+ */
+export const ListUsersRequestSchema: GenMessage<ListUsersRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 35);
+
+/**
+ * @generated from message user.v1.ListUsersResponse
+ */
+export type ListUsersResponse = Message<"user.v1.ListUsersResponse"> & {
+  /**
+   * @generated from field: repeated user.v1.User users = 1;
+   */
+  users: User[];
+
+  /**
+   * 为空表示没有更多数据
+   *
+   * @generated from field: string next_page_token = 2;
+   */
+  nextPageToken: string;
+};
+
+/**
+ * Describes the message user.v1.ListUsersResponse.
+ * Use `create(ListUsersResponseSchema)` to create a new message.
+ */
+export const ListUsersResponseSchema: GenMessage<ListUsersResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 36);
+
+/**
+ * @generated from message user.v1.SearchUsersRequest
+ */
+export type SearchUsersRequest = Message<"user.v1.SearchUsersRequest"> & {
+  /**
+   * 搜索关键字，对用户名、昵称、邮箱做前缀和模糊匹配
+   *
+   * @generated from field: string query = 1;
+   */
+  query: string;
+
+  /**
+   * 每页数量，为 0 时使用默认值，最大 100
+   *
+   * @generated from field: int32 page_size = 2;
+   */
+  pageSize: number;
+
+  /**
+   * 上一页返回的 next_page_token
+   *
+   * @generated from field: string page_token = 3;
+   */
+  pageToken: string;
+};
+
+/**
+ * Describes the message user.v1.SearchUsersRequest.
+ * Use `create(SearchUsersRequestSchema)` to create a new message.
+ */
+export const SearchUsersRequestSchema: GenMessage<SearchUsersRequest> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 37);
+
+/**
+ * @generated from message user.v1.SearchUsersResponse
+ */
+export type SearchUsersResponse = Message<"user.v1.SearchUsersResponse"> & {
+  /**
+   * @generated from field: repeated user.v1.SearchUsersResponse.Hit hits = 1;
+   */
+  hits: SearchUsersResponse_Hit[];
+
+  /**
+   * 命中总数
+   *
+   * @generated from field: int64 total = 2;
+   */
+  total: bigint;
+
+  /**
+   * @generated from field: string next_page_token = 3;
+   */
+  nextPageToken: string;
+};
+
+/**
+ * Describes the message user.v1.SearchUsersResponse.
+ * Use `create(SearchUsersResponseSchema)` to create a new message.
+ */
+export const SearchUsersResponseSchema: GenMessage<SearchUsersResponse> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 38);
+
+/**
+ * @generated from message user.v1.SearchUsersResponse.Hit
+ */
+export type SearchUsersResponse_Hit = Message<"user.v1.SearchUsersResponse.Hit"> & {
+  /**
+   * @generated from field: user.v1.User user = 1;
+   */
+  user?: User;
+
+  /**
+   * @generated from field: float score = 2;
+   */
+  score: number;
+
+  /**
+   * 字段名到高亮片段的映射，命中部分使用 <em></em> 包裹
+   *
+   * @generated from field: map<string, user.v1.SearchUsersResponse.Highlight> highlights = 3;
+   */
+  highlights: { [key: string]: SearchUsersResponse_Highlight };
+};
+
+/**
+ * Describes the message user.v1.SearchUsersResponse.Hit.
+ * Use `create(SearchUsersResponse_HitSchema)` to create a new message.
+ */
+export const SearchUsersResponse_HitSchema: GenMessage<SearchUsersResponse_Hit> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 38, 0);
+
+/**
+ * @generated from message user.v1.SearchUsersResponse.Highlight
+ */
+export type SearchUsersResponse_Highlight = Message<"user.v1.SearchUsersResponse.Highlight"> & {
+  /**
+   * @generated from field: repeated string fragments = 1;
+   */
+  fragments: string[];
+};
+
+/**
+ * Describes the message user.v1.SearchUsersResponse.Highlight.
+ * Use `create(SearchUsersResponse_HighlightSchema)` to create a new message.
+ */
+export const SearchUsersResponse_HighlightSchema: GenMessage<SearchUsersResponse_Highlight> = /*@__PURE__*/
+  messageDesc(file_api_user_v1_user, 38, 1);
+
+/**
+ * ErrorReason 业务错误的原因
+ * 错误的 details 中携带 google.rpc.ErrorInfo，其 reason 为去掉 ERROR_REASON_ 前缀的枚举名（例如 USER_NOT_FOUND），
+ * domain 为 connect-go-example；字段校验失败时还携带 google.rpc.BadRequest，并附带 google.rpc.LocalizedMessage
+ *
+ * @generated from enum user.v1.ErrorReason
+ */
+export enum ErrorReason {
+  /**
+   * @generated from enum value: ERROR_REASON_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * 请求字段不合法，具体字段见 BadRequest
+   *
+   * @generated from enum value: ERROR_REASON_INVALID_ARGUMENT = 1;
+   */
+  INVALID_ARGUMENT = 1,
+
+  /**
+   * 用户名已被占用，metadata: username
+   *
+   * @generated from enum value: ERROR_REASON_USER_ALREADY_EXISTS = 2;
+   */
+  USER_ALREADY_EXISTS = 2,
+
+  /**
+   * 用户不存在，metadata: id
+   *
+   * @generated from enum value: ERROR_REASON_USER_NOT_FOUND = 3;
+   */
+  USER_NOT_FOUND = 3,
+
+  /**
+   * 用户名或密码错误
+   *
+   * @generated from enum value: ERROR_REASON_INVALID_CREDENTIALS = 4;
+   */
+  INVALID_CREDENTIALS = 4,
+
+  /**
+   * 分页令牌不合法
+   *
+   * @generated from enum value: ERROR_REASON_INVALID_PAGE_TOKEN = 5;
+   */
+  INVALID_PAGE_TOKEN = 5,
+
+  /**
+   * 会话不存在或已过期
+   *
+   * @generated from enum value: ERROR_REASON_SESSION_NOT_FOUND = 6;
+   */
+  SESSION_NOT_FOUND = 6,
+
+  /**
+   * 刷新令牌不合法
+   *
+   * @generated from enum value: ERROR_REASON_INVALID_REFRESH_TOKEN = 7;
+   */
+  INVALID_REFRESH_TOKEN = 7,
+
+  /**
+   * 刷新令牌被重复使用，整个会话已吊销
+   *
+   * @generated from enum value: ERROR_REASON_REFRESH_TOKEN_REUSED = 8;
+   */
+  REFRESH_TOKEN_REUSED = 8,
+
+  /**
+   * 搜索关键词为空
+   *
+   * @generated from enum value: ERROR_REASON_EMPTY_SEARCH_QUERY = 9;
+   */
+  EMPTY_SEARCH_QUERY = 9,
+
+  /**
+   * 没有调用该接口的权限，或操作的不是本人的账号；授权策略拒绝时 metadata: procedure, policy, required_roles, missing_permissions
+   *
+   * @generated from enum value: ERROR_REASON_PERMISSION_DENIED = 10;
+   */
+  PERMISSION_DENIED = 10,
+
+  /**
+   * 请求过于频繁，错误携带 google.rpc.RetryInfo 和 Retry-After 头，metadata: procedure
+   *
+   * @generated from enum value: ERROR_REASON_RATE_LIMITED = 11;
+   */
+  RATE_LIMITED = 11,
+
+  /**
+   * 连续登录失败次数过多，账号暂时锁定，metadata: retry_after（秒）
+   *
+   * @generated from enum value: ERROR_REASON_ACCOUNT_LOCKED = 12;
+   */
+  ACCOUNT_LOCKED = 12,
+
+  /**
+   * 服务端未配置 MFA 加密密钥，或调用方不是本地账号
+   *
+   * @generated from enum value: ERROR_REASON_MFA_UNAVAILABLE = 13;
+   */
+  MFA_UNAVAILABLE = 13,
+
+  /**
+   * 尚未绑定 MFA
+   *
+   * @generated from enum value: ERROR_REASON_MFA_NOT_ENROLLED = 14;
+   */
+  MFA_NOT_ENROLLED = 14,
+
+  /**
+   * 已经开启 MFA，需要先关闭才能重新绑定
+   *
+   * @generated from enum value: ERROR_REASON_MFA_ALREADY_ENABLED = 15;
+   */
+  MFA_ALREADY_ENABLED = 15,
+
+  /**
+   * MFA 验证码或恢复码错误，或验证码已被使用
+   *
+   * @generated from enum value: ERROR_REASON_INVALID_MFA_CODE = 16;
+   */
+  INVALID_MFA_CODE = 16,
+
+  /**
+   * MFA 挑战令牌不存在、已过期或尝试次数过多
+   *
+   * @generated from enum value: ERROR_REASON_INVALID_MFA_CHALLENGE = 17;
+   */
+  INVALID_MFA_CHALLENGE = 17,
+
+  /**
+   * 重置密码或验证邮箱的令牌不存在、已过期或已被使用
+   *
+   * @generated from enum value: ERROR_REASON_INVALID_TOKEN = 18;
+   */
+  INVALID_TOKEN = 18,
+
+  /**
+   * 当前用户未设置邮箱
+   *
+   * @generated from enum value: ERROR_REASON_EMAIL_NOT_SET = 19;
+   */
+  EMAIL_NOT_SET = 19,
+
+  /**
+   * 邮箱已经验证过
+   *
+   * @generated from enum value: ERROR_REASON_EMAIL_ALREADY_VERIFIED = 20;
+   */
+  EMAIL_ALREADY_VERIFIED = 20,
+
+  /**
+   * 搜索索引尚未就绪，例如 Elasticsearch 暂时不可用，稍后重试
+   *
+   * @generated from enum value: ERROR_REASON_SEARCH_UNAVAILABLE = 21;
+   */
+  SEARCH_UNAVAILABLE = 21,
+}
+
+/**
+ * Describes the enum user.v1.ErrorReason.
+ */
+export const ErrorReasonSchema: GenEnum<ErrorReason> = /*@__PURE__*/
+  enumDesc(file_api_user_v1_user, 0);
+
+/**
+ * @generated from service user.v1.UserService
+ */
+export const UserService: GenService<{
+  /**
+   * Casdoor OAuth 授权码登录
+   *
+   * @generated from rpc user.v1.UserService.SignIn
+   */
+  signIn: {
+    methodKind: "unary";
+    input: typeof SignInRequestSchema;
+    output: typeof SignInResponseSchema;
+  },
+  /**
+   * 本地用户名/密码注册
+   *
+   * @generated from rpc user.v1.UserService.Register
+   */
+  register: {
+    methodKind: "unary";
+    input: typeof RegisterRequestSchema;
+    output: typeof RegisterResponseSchema;
+  },
+  /**
+   * 本地用户名/密码登录
+   *
+   * @generated from rpc user.v1.UserService.PasswordSignIn
+   */
+  passwordSignIn: {
+    methodKind: "unary";
+    input: typeof PasswordSignInRequestSchema;
+    output: typeof PasswordSignInResponseSchema;
+  },
+  /**
+   * 使用刷新令牌轮换会话
+   *
+   * @generated from rpc user.v1.UserService.RefreshToken
+   */
+  refreshToken: {
+    methodKind: "unary";
+    input: typeof RefreshTokenRequestSchema;
+    output: typeof RefreshTokenResponseSchema;
+  },
+  /**
+   * 退出登录
+   *
+   * @generated from rpc user.v1.UserService.SignOut
+   */
+  signOut: {
+    methodKind: "unary";
+    input: typeof SignOutRequestSchema;
+    output: typeof SignOutResponseSchema;
+  },
+  /**
+   * 获取用户资料，非本人且非管理员时不返回邮箱
+   *
+   * @generated from rpc user.v1.UserService.GetUser
+   */
+  getUser: {
+    methodKind: "unary";
+    input: typeof GetUserRequestSchema;
+    output: typeof GetUserResponseSchema;
+  },
+  /**
+   * 按 update_mask 部分更新用户资料，只有本人和管理员可以调用
+   *
+   * @generated from rpc user.v1.UserService.UpdateUser
+   */
+  updateUser: {
+    methodKind: "unary";
+    input: typeof UpdateUserRequestSchema;
+    output: typeof UpdateUserResponseSchema;
+  },
+  /**
+   * 删除用户（软删除），只有本人和管理员可以调用
+   *
+   * @generated from rpc user.v1.UserService.DeleteUser
+   */
+  deleteUser: {
+    methodKind: "unary";
+    input: typeof DeleteUserRequestSchema;
+    output: typeof DeleteUserResponseSchema;
+  },
+  /**
+   * 分页列出用户，非本人且非管理员时不返回邮箱
+   *
+   * @generated from rpc user.v1.UserService.ListUsers
+   */
+  listUsers: {
+    methodKind: "unary";
+    input: typeof ListUsersRequestSchema;
+    output: typeof ListUsersResponseSchema;
+  },
+  /**
+   * 全文搜索用户，非本人且非管理员时不返回邮箱；索引尚未就绪时返回 Unavailable
+   *
+   * @generated from rpc user.v1.UserService.SearchUsers
+   */
+  searchUsers: {
+    methodKind: "unary";
+    input: typeof SearchUsersRequestSchema;
+    output: typeof SearchUsersResponseSchema;
+  },
+  /**
+   * 解除因登录失败导致的账号锁定，只有管理员可以调用
+   *
+   * @generated from rpc user.v1.UserService.UnlockUser
+   */
+  unlockUser: {
+    methodKind: "unary";
+    input: typeof UnlockUserRequestSchema;
+    output: typeof UnlockUserResponseSchema;
+  },
+  /**
+   * 登录第二步：使用 TOTP 验证码或恢复码完成开启了 MFA 的账号登录
+   *
+   * @generated from rpc user.v1.UserService.VerifyMFA
+   */
+  verifyMFA: {
+    methodKind: "unary";
+    input: typeof VerifyMFARequestSchema;
+    output: typeof VerifyMFAResponseSchema;
+  },
+  /**
+   * 为当前用户生成 TOTP 密钥，确认前不生效
+   *
+   * @generated from rpc user.v1.UserService.EnrollTOTP
+   */
+  enrollTOTP: {
+    methodKind: "unary";
+    input: typeof EnrollTOTPRequestSchema;
+    output: typeof EnrollTOTPResponseSchema;
+  },
+  /**
+   * 使用验证码确认绑定并开启 MFA，返回恢复码
+   *
+   * @generated from rpc user.v1.UserService.ConfirmTOTP
+   */
+  confirmTOTP: {
+    methodKind: "unary";
+    input: typeof ConfirmTOTPRequestSchema;
+    output: typeof ConfirmTOTPResponseSchema;
+  },
+  /**
+   * 重新生成恢复码
+   *
+   * @generated from rpc user.v1.UserService.RegenerateRecoveryCodes
+   */
+  regenerateRecoveryCodes: {
+    methodKind: "unary";
+    input: typeof RegenerateRecoveryCodesRequestSchema;
+    output: typeof RegenerateRecoveryCodesResponseSchema;
+  },
+  /**
+   * 向已验证的邮箱发送重置密码邮件；邮件在后台发送，无论邮箱是否存在都立即返回成功，避免枚举账号
+   *
+   * @generated from rpc user.v1.UserService.RequestPasswordReset
+   */
+  requestPasswordReset: {
+    methodKind: "unary";
+    input: typeof RequestPasswordResetRequestSchema;
+    output: typeof RequestPasswordResetResponseSchema;
+  },
+  /**
+   * 使用重置密码令牌设置新密码，成功后退出所有会话
+   *
+   * @generated from rpc user.v1.UserService.ResetPassword
+   */
+  resetPassword: {
+    methodKind: "unary";
+    input: typeof ResetPasswordRequestSchema;
+    output: typeof ResetPasswordResponseSchema;
+  },
+  /**
+   * 向当前用户的邮箱发送验证邮件
+   *
+   * @generated from rpc user.v1.UserService.SendVerificationEmail
+   */
+  sendVerificationEmail: {
+    methodKind: "unary";
+    input: typeof SendVerificationEmailRequestSchema;
+    output: typeof SendVerificationEmailResponseSchema;
+  },
+  /**
+   * 使用验证邮件中的令牌验证邮箱
+   *
+   * @generated from rpc user.v1.UserService.VerifyEmail
+   */
+  verifyEmail: {
+    methodKind: "unary";
+    input: typeof VerifyEmailRequestSchema;
+    output: typeof VerifyEmailResponseSchema;
   },
 }> = /*@__PURE__*/
   serviceDesc(file_api_user_v1_user, 0);
@@ -5,11 +5,9 @@ import (
 	"connect-go-example/internal/pkg/meta"
 	"connect-go-example/internal/pkg/otel"
 	"context"
-	"errors"
 	"flag"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
 
 	confv1 "connect-go-example/internal/conf/v1"
@@ -22,7 +20,6 @@ import (
 
 	"github.com/google/uuid"
 	"go.uber.org/fx"
-	"go.uber.org/zap"
 )
 
 var (
@@ -40,6 +37,15 @@ func main() {
 	flag.Parse()
 	setConsulEnv()
 
+	// migrate up|down|status 子命令只执行数据库迁移
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(flag.Arg(1)); err != nil {
+			log.Printf("Migrate failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	fxApp := NewApp(
 		*serviceName,
 		*serviceVersion,
@@ -64,19 +70,24 @@ func main() {
 	}
 }
 
-// NewApp 创建并配置 FX 应用
-func NewApp(serviceName, serviceVersion, deploymentEnvironment string) *fx.App {
+// newAppInfo 生成当前实例的应用信息
+func newAppInfo(serviceName, serviceVersion, deploymentEnvironment string) meta.AppInfo {
 	host, err := meta.GetOutboundIP()
 	if err != nil {
 		fmt.Printf("Warn: not get host:%v", err)
 	}
-	appInfo := meta.AppInfo{
+	return meta.AppInfo{
 		ID:          uuid.New().String(),
 		Name:        serviceName,
 		Host:        host,
 		Version:     serviceVersion,
 		Environment: deploymentEnvironment,
 	}
+}
+
+// NewApp 创建并配置 FX 应用
+func NewApp(serviceName, serviceVersion, deploymentEnvironment string) *fx.App {
+	appInfo := newAppInfo(serviceName, serviceVersion, deploymentEnvironment)
 
 	return fx.New(
 		// 基础模块
@@ -106,44 +117,8 @@ func NewApp(serviceName, serviceVersion, deploymentEnvironment string) *fx.App {
 			func(conf *confv1.Bootstrap) error {
 				return config.ValidateConfig(conf)
 			},
-
-			// 注册应用到注册中心
-			func(_ *registry.ConsulRegistry) {},
-
-			// 初始化并启动核心应用逻辑
-			func(lc fx.Lifecycle, conf *confv1.Bootstrap, logger *zap.Logger, srv *http.Server, otelShutdown func(context.Context) error) {
-				lc.Append(fx.Hook{
-					// 启动服务时的操作
-					OnStart: func(ctx context.Context) error {
-						logger.Info("Starting HTTP server",
-							zap.String("addr", srv.Addr),
-							zap.String("version", serviceVersion),
-							zap.String("environment", deploymentEnvironment),
-						)
-						go func() {
-							if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-								logger.Fatal("Failed to start HTTP server", zap.Error(err))
-							}
-						}()
-						return nil
-					},
-					// 停止服务前的操作
-					OnStop: func(ctx context.Context) error {
-						logger.Info("Stopping HTTP server...")
-						// 优雅关闭服务器
-						if err := srv.Shutdown(ctx); err != nil {
-							logger.Error("Failed to shutdown server gracefully", zap.Error(err))
-						}
-						// 关闭 Otel
-						if otelShutdown != nil {
-							if err := otelShutdown(ctx); err != nil {
-								logger.Error("Failed to shutdown OTel", zap.Error(err))
-							}
-						}
-						return nil
-					},
-				})
-			},
+			// HTTP 服务的启动和优雅关闭由 server.Module 负责，
+			// 注册中心随服务注册，OTel 在所有组件关闭后刷新
 		),
 	)
 }
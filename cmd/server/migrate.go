@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"connect-go-example/internal/data"
+	"connect-go-example/internal/pkg/config"
+	logger "connect-go-example/internal/pkg/log"
+
+	"go.uber.org/fx"
+)
+
+// runMigrate 执行 migrate 子命令，只初始化配置、日志和数据库连接
+func runMigrate(command string) error {
+	if command == "" {
+		command = "up"
+	}
+
+	var migrator *data.Migrator
+	app := fx.New(
+		config.Module,
+		logger.Module,
+		fx.Supply(newAppInfo(*serviceName, *serviceVersion, *deploymentEnvironment)),
+		fx.Provide(data.NewDB, data.NewMigrator),
+		fx.Populate(&migrator),
+		fx.NopLogger,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := app.Start(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		_ = app.Stop(context.Background())
+	}()
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migration(s)\n", applied)
+	case "down":
+		migration, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rolled back %d_%s\n", migration.Version, migration.Name)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.Applied {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or status", command)
+	}
+	return nil
+}
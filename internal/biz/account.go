@@ -0,0 +1,226 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/mail"
+	"connect-go-example/internal/pkg/password"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidToken = NewError(KindInvalidArgument, ReasonInvalidToken, "token is invalid, expired or already used").
+			WithField("token", "token is invalid, expired or already used")
+	ErrEmailNotSet          = NewError(KindFailedPrecondition, ReasonEmailNotSet, "email is not set")
+	ErrEmailAlreadyVerified = NewError(KindFailedPrecondition, ReasonEmailAlreadyVerified, "email is already verified")
+)
+
+// 邮件令牌的默认有效期
+const (
+	defaultPasswordResetTTL = time.Hour
+	defaultVerificationTTL  = 24 * time.Hour
+)
+
+// TokenPurpose 一次性令牌的用途，不同用途的令牌不能混用
+type TokenPurpose string
+
+const (
+	TokenPurposePasswordReset     TokenPurpose = "password_reset"
+	TokenPurposeEmailVerification TokenPurpose = "email_verification"
+)
+
+// AccountRepo 重置密码和验证邮箱的存储接口
+// 令牌只以哈希形式保存，并绑定签发时的邮箱，用户修改邮箱后令牌失效
+type AccountRepo interface {
+	// ListUsersByVerifiedEmail 查询邮箱已验证的用户，同一个邮箱可能对应多个账号
+	ListUsersByVerifiedEmail(ctx context.Context, email string) ([]*UserInfo, error)
+	// CreateToken 签发一次性令牌，同时作废该用户同类尚未使用的令牌
+	CreateToken(ctx context.Context, user *UserInfo, purpose TokenPurpose, ttl time.Duration) (string, error)
+	// ResetPassword 使用令牌更新密码并返回用户 ID，令牌不可用时返回 ErrInvalidToken
+	// 令牌校验通过后才调用 credential 生成新密码的哈希，无效令牌不会触发开销较大的哈希计算
+	ResetPassword(ctx context.Context, token string, credential func() (UserCredential, error)) (int64, error)
+	// VerifyEmail 使用令牌将邮箱标记为已验证并返回用户 ID，令牌不可用时返回 ErrInvalidToken
+	VerifyEmail(ctx context.Context, token string) (int64, error)
+}
+
+// emailTokenPolicy 邮件令牌配置
+type emailTokenPolicy struct {
+	linkBaseURL      string
+	passwordResetTTL time.Duration
+	verificationTTL  time.Duration
+}
+
+func newEmailTokenPolicy(cfg *conf.Auth_EmailTokens) emailTokenPolicy {
+	policy := emailTokenPolicy{
+		linkBaseURL:      cfg.GetLinkBaseUrl(),
+		passwordResetTTL: time.Duration(cfg.GetPasswordResetTtl()) * time.Second,
+		verificationTTL:  time.Duration(cfg.GetVerificationTtl()) * time.Second,
+	}
+	if policy.passwordResetTTL <= 0 {
+		policy.passwordResetTTL = defaultPasswordResetTTL
+	}
+	if policy.verificationTTL <= 0 {
+		policy.verificationTTL = defaultVerificationTTL
+	}
+	return policy
+}
+
+// link 生成邮件中的链接，未配置 link_base_url 时直接返回令牌
+func (p emailTokenPolicy) link(token string) string {
+	if p.linkBaseURL == "" {
+		return token
+	}
+	u, err := url.Parse(p.linkBaseURL)
+	if err != nil {
+		return token
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// formatTTL 将有效期格式化为邮件中的文字，例如 "1 hour"、"30 minutes"
+func formatTTL(d time.Duration) string {
+	unit, n := "minute", max(int64(d/time.Minute), 1)
+	if d >= time.Hour && d%time.Hour == 0 {
+		unit, n = "hour", int64(d/time.Hour)
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", n, unit)
+}
+
+// AccountUseCase 本地账号的重置密码和邮箱验证
+type AccountUseCase struct {
+	repo     AccountRepo
+	users    UserRepo
+	sessions SessionRepo
+	mailer   mail.Mailer
+	policy   emailTokenPolicy
+	l        *zap.Logger
+}
+
+// NewAccountUseCase 创建 AccountUseCase
+func NewAccountUseCase(repo AccountRepo, users UserRepo, sessions SessionRepo, mailer mail.Mailer, cfg *conf.Bootstrap, logger *zap.Logger) *AccountUseCase {
+	return &AccountUseCase{
+		repo:     repo,
+		users:    users,
+		sessions: sessions,
+		mailer:   mailer,
+		policy:   newEmailTokenPolicy(cfg.GetAuth().GetEmailTokens()),
+		l:        logger,
+	}
+}
+
+// RequestPasswordReset 向邮箱已验证的账号发送重置密码邮件
+// 邮箱不存在、未验证或邮件发送失败时同样返回成功，避免通过该接口探测账号是否存在
+// 邮件由 Mailer 的队列在后台发送，请求不等待 SMTP
+func (uc *AccountUseCase) RequestPasswordReset(ctx context.Context, email string) error {
+	users, err := uc.repo.ListUsersByVerifiedEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		token, err := uc.repo.CreateToken(ctx, user, TokenPurposePasswordReset, uc.policy.passwordResetTTL)
+		if err != nil {
+			return err
+		}
+		msg := mail.Message{
+			To:      []string{user.Email},
+			Subject: "Reset your password",
+			Text: fmt.Sprintf("Hi %s,\n\n"+
+				"We received a request to reset the password for your account. Use the link below to choose a new password:\n\n"+
+				"%s\n\n"+
+				"The link expires in %s and can only be used once. If you did not request a password reset, you can ignore this email.\n",
+				user.Username, uc.policy.link(token), formatTTL(uc.policy.passwordResetTTL)),
+		}
+		if err := uc.mailer.Send(ctx, msg); err != nil {
+			uc.l.Error("Failed to send password reset email", zap.Int64("id", user.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// ResetPassword 使用重置密码令牌设置新密码
+// 成功后吊销该用户的所有会话，并解除因登录失败导致的锁定
+func (uc *AccountUseCase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	userID, err := uc.repo.ResetPassword(ctx, token, func() (UserCredential, error) {
+		hash, salt, err := password.Hash(newPassword)
+		if err != nil {
+			return UserCredential{}, fmt.Errorf("hash password failed: %w", err)
+		}
+		return UserCredential{PasswordHash: hash, Salt: salt}, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := uc.sessions.RevokeUserSessions(ctx, userID); err != nil {
+		return fmt.Errorf("revoke sessions after password reset failed: %w", err)
+	}
+	if user, err := uc.users.GetUser(ctx, userID); err == nil {
+		if err := uc.users.UnlockUser(ctx, user.Username); err != nil {
+			uc.l.Warn("Failed to unlock account after password reset", zap.Int64("id", userID), zap.Error(err))
+		}
+	}
+
+	uc.l.Info("Password reset", zap.Int64("id", userID))
+	return nil
+}
+
+// SendVerificationEmail 向当前用户的邮箱发送验证邮件
+func (uc *AccountUseCase) SendVerificationEmail(ctx context.Context) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == 0 {
+		// Casdoor 账号的邮箱由 Casdoor 管理，只有本地会话可以验证邮箱
+		return ErrSessionNotFound
+	}
+
+	user, err := uc.users.GetUser(ctx, principal.UserID)
+	if err != nil {
+		return err
+	}
+	if user.Email == "" {
+		return ErrEmailNotSet
+	}
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	token, err := uc.repo.CreateToken(ctx, user, TokenPurposeEmailVerification, uc.policy.verificationTTL)
+	if err != nil {
+		return err
+	}
+	msg := mail.Message{
+		To:      []string{user.Email},
+		Subject: "Verify your email address",
+		Text: fmt.Sprintf("Hi %s,\n\n"+
+			"Please confirm that this is your email address by opening the link below:\n\n"+
+			"%s\n\n"+
+			"The link expires in %s. If you did not add this email address to an account, you can ignore this email.\n",
+			user.Username, uc.policy.link(token), formatTTL(uc.policy.verificationTTL)),
+	}
+	if err := uc.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("send verification email failed: %w", err)
+	}
+	return nil
+}
+
+// VerifyEmail 使用验证邮件中的令牌将邮箱标记为已验证
+func (uc *AccountUseCase) VerifyEmail(ctx context.Context, token string) error {
+	userID, err := uc.repo.VerifyEmail(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	uc.l.Info("Email verified", zap.Int64("id", userID))
+	return nil
+}
@@ -0,0 +1,248 @@
+package biz
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/mail"
+	"connect-go-example/internal/pkg/password"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+// fakeToken 内存中的一次性令牌
+type fakeToken struct {
+	userID  int64
+	email   string
+	purpose TokenPurpose
+	used    bool
+}
+
+// fakeAccountRepo 是基于内存的 AccountRepo 实现，用户数据保存在 fakeUserRepo 中，不模拟过期
+type fakeAccountRepo struct {
+	users  *fakeUserRepo
+	tokens map[string]*fakeToken
+}
+
+func (r *fakeAccountRepo) ListUsersByVerifiedEmail(ctx context.Context, email string) ([]*UserInfo, error) {
+	var users []*UserInfo
+	for _, user := range r.users.profiles {
+		if user.Email == email && user.EmailVerified && !r.users.deleted[user.ID] {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (r *fakeAccountRepo) CreateToken(ctx context.Context, user *UserInfo, purpose TokenPurpose, ttl time.Duration) (string, error) {
+	for _, t := range r.tokens {
+		if t.userID == user.ID && t.purpose == purpose {
+			t.used = true
+		}
+	}
+	token := "token-" + strconv.Itoa(len(r.tokens)+1)
+	r.tokens[token] = &fakeToken{userID: user.ID, email: user.Email, purpose: purpose}
+	return token, nil
+}
+
+// consume 消费令牌，令牌签发后用户修改了邮箱时同样失败
+func (r *fakeAccountRepo) consume(token string, purpose TokenPurpose) (*UserInfo, error) {
+	t, ok := r.tokens[token]
+	if !ok || t.used || t.purpose != purpose {
+		return nil, ErrInvalidToken
+	}
+	t.used = true
+	user, err := r.users.GetUser(context.Background(), t.userID)
+	if err != nil || user.Email != t.email {
+		return nil, ErrInvalidToken
+	}
+	return user, nil
+}
+
+func (r *fakeAccountRepo) ResetPassword(ctx context.Context, token string, credential func() (UserCredential, error)) (int64, error) {
+	user, err := r.consume(token, TokenPurposePasswordReset)
+	if err != nil {
+		return 0, err
+	}
+	cred, err := credential()
+	if err != nil {
+		return 0, err
+	}
+	r.users.users[user.Username] = &cred
+	return user.ID, nil
+}
+
+func (r *fakeAccountRepo) VerifyEmail(ctx context.Context, token string) (int64, error) {
+	user, err := r.consume(token, TokenPurposeEmailVerification)
+	if err != nil {
+		return 0, err
+	}
+	user.EmailVerified = true
+	return user.ID, nil
+}
+
+// tokenPattern 从邮件链接中取出令牌
+var tokenPattern = regexp.MustCompile(`token=(\S+)`)
+
+// AccountUseCaseTestSuite 是 AccountUseCase 的测试套件
+type AccountUseCaseTestSuite struct {
+	suite.Suite
+	users    *fakeUserRepo
+	sessions *fakeSessionRepo
+	mailer   *mail.MemoryMailer
+	uc       *AccountUseCase
+	ctx      context.Context
+}
+
+func (suite *AccountUseCaseTestSuite) SetupTest() {
+	suite.users = newFakeUserRepo()
+	suite.sessions = &fakeSessionRepo{}
+	suite.mailer = mail.NewMemoryMailer()
+	cfg := &conf.Bootstrap{Auth: &conf.Auth{
+		EmailTokens: &conf.Auth_EmailTokens{LinkBaseUrl: "https://example.com/account?lang=en"},
+	}}
+	repo := &fakeAccountRepo{users: suite.users, tokens: map[string]*fakeToken{}}
+	suite.uc = NewAccountUseCase(repo, suite.users, suite.sessions, suite.mailer, cfg, zap.NewNop())
+	suite.ctx = context.Background()
+}
+
+// createUser 创建本地账号并设置邮箱
+func (suite *AccountUseCaseTestSuite) createUser(username, email string, verified bool) *UserInfo {
+	user, err := suite.users.CreateUser(suite.ctx, username, UserCredential{PasswordHash: "hash"})
+	require.NoError(suite.T(), err)
+	user.Email = email
+	user.EmailVerified = verified
+	return user
+}
+
+// lastToken 返回最后一封邮件中的令牌
+func (suite *AccountUseCaseTestSuite) lastToken() string {
+	messages := suite.mailer.Messages()
+	require.NotEmpty(suite.T(), messages)
+	match := tokenPattern.FindStringSubmatch(messages[len(messages)-1].Text)
+	require.Len(suite.T(), match, 2)
+	return match[1]
+}
+
+func (suite *AccountUseCaseTestSuite) TestRequestPasswordReset() {
+	suite.createUser("alice", "alice@example.com", true)
+	suite.createUser("bob", "bob@example.com", false)
+
+	// 未验证和不存在的邮箱同样返回成功，但不发送邮件
+	assert.NoError(suite.T(), suite.uc.RequestPasswordReset(suite.ctx, "bob@example.com"))
+	assert.NoError(suite.T(), suite.uc.RequestPasswordReset(suite.ctx, "nobody@example.com"))
+	assert.Empty(suite.T(), suite.mailer.Messages())
+	require.NoError(suite.T(), suite.uc.RequestPasswordReset(suite.ctx, "alice@example.com"))
+
+	messages := suite.mailer.Messages()
+	require.Len(suite.T(), messages, 1)
+	assert.Equal(suite.T(), []string{"alice@example.com"}, messages[0].To)
+	assert.Contains(suite.T(), messages[0].Text, "https://example.com/account?lang=en&token=")
+	assert.Contains(suite.T(), messages[0].Text, "1 hour")
+}
+
+func (suite *AccountUseCaseTestSuite) TestResetPassword() {
+	suite.createUser("alice", "alice@example.com", true)
+	suite.users.locked["alice"] = time.Minute
+
+	require.NoError(suite.T(), suite.uc.RequestPasswordReset(suite.ctx, "alice@example.com"))
+	token := suite.lastToken()
+
+	require.NoError(suite.T(), suite.uc.ResetPassword(suite.ctx, token, "n3w-s3cret-pass"))
+	ok, err := password.Verify("n3w-s3cret-pass", suite.users.users["alice"].PasswordHash)
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), []int64{1}, suite.sessions.revoked)
+	assert.NotContains(suite.T(), suite.users.locked, "alice")
+
+	// 令牌只能使用一次
+	assert.ErrorIs(suite.T(), suite.uc.ResetPassword(suite.ctx, token, "an0ther-pass"), ErrInvalidToken)
+}
+
+func (suite *AccountUseCaseTestSuite) TestResetPassword_OnlyLatestTokenValid() {
+	suite.createUser("alice", "alice@example.com", true)
+
+	require.NoError(suite.T(), suite.uc.RequestPasswordReset(suite.ctx, "alice@example.com"))
+	first := suite.lastToken()
+	require.NoError(suite.T(), suite.uc.RequestPasswordReset(suite.ctx, "alice@example.com"))
+	second := suite.lastToken()
+
+	assert.ErrorIs(suite.T(), suite.uc.ResetPassword(suite.ctx, first, "n3w-s3cret-pass"), ErrInvalidToken)
+	assert.NoError(suite.T(), suite.uc.ResetPassword(suite.ctx, second, "n3w-s3cret-pass"))
+}
+
+func (suite *AccountUseCaseTestSuite) TestVerifyEmail() {
+	user := suite.createUser("alice", "alice@example.com", false)
+	ctx := NewPrincipalContext(suite.ctx, &Principal{Source: PrincipalSourceSession, UserID: user.ID, Username: user.Username})
+
+	require.NoError(suite.T(), suite.uc.SendVerificationEmail(ctx))
+	token := suite.lastToken()
+
+	// 验证邮箱的令牌不能用于重置密码
+	assert.ErrorIs(suite.T(), suite.uc.ResetPassword(suite.ctx, token, "n3w-s3cret-pass"), ErrInvalidToken)
+
+	require.NoError(suite.T(), suite.uc.SendVerificationEmail(ctx))
+	require.NoError(suite.T(), suite.uc.VerifyEmail(suite.ctx, suite.lastToken()))
+	assert.True(suite.T(), user.EmailVerified)
+
+	assert.ErrorIs(suite.T(), suite.uc.SendVerificationEmail(ctx), ErrEmailAlreadyVerified)
+}
+
+func (suite *AccountUseCaseTestSuite) TestVerifyEmail_EmailChanged() {
+	user := suite.createUser("alice", "alice@example.com", false)
+	ctx := NewPrincipalContext(suite.ctx, &Principal{Source: PrincipalSourceSession, UserID: user.ID, Username: user.Username})
+
+	require.NoError(suite.T(), suite.uc.SendVerificationEmail(ctx))
+	user.Email = "mallory@example.com"
+
+	assert.ErrorIs(suite.T(), suite.uc.VerifyEmail(suite.ctx, suite.lastToken()), ErrInvalidToken)
+	assert.False(suite.T(), user.EmailVerified)
+}
+
+func (suite *AccountUseCaseTestSuite) TestSendVerificationEmail_Errors() {
+	user := suite.createUser("alice", "", false)
+
+	assert.ErrorIs(suite.T(), suite.uc.SendVerificationEmail(suite.ctx), ErrSessionNotFound)
+
+	jwt := NewPrincipalContext(suite.ctx, &Principal{Source: PrincipalSourceJWT, Subject: "casdoor-id", Username: "alice"})
+	assert.ErrorIs(suite.T(), suite.uc.SendVerificationEmail(jwt), ErrSessionNotFound)
+
+	ctx := NewPrincipalContext(suite.ctx, &Principal{Source: PrincipalSourceSession, UserID: user.ID, Username: user.Username})
+	assert.ErrorIs(suite.T(), suite.uc.SendVerificationEmail(ctx), ErrEmailNotSet)
+	assert.Empty(suite.T(), suite.mailer.Messages())
+}
+
+// 运行测试套件
+func TestAccountUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountUseCaseTestSuite))
+}
+
+func TestEmailTokenPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      *conf.Auth_EmailTokens
+		wantLink string
+		wantTTL  string
+	}{
+		{"defaults", nil, "abc", "1 hour"},
+		{"base url", &conf.Auth_EmailTokens{LinkBaseUrl: "https://example.com/reset"}, "https://example.com/reset?token=abc", "1 hour"},
+		{"keeps query", &conf.Auth_EmailTokens{LinkBaseUrl: "https://example.com/reset?lang=zh"}, "https://example.com/reset?lang=zh&token=abc", "1 hour"},
+		{"minutes", &conf.Auth_EmailTokens{PasswordResetTtl: 1800}, "abc", "30 minutes"},
+		{"hours", &conf.Auth_EmailTokens{PasswordResetTtl: 7200}, "abc", "2 hours"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newEmailTokenPolicy(tt.cfg)
+			assert.Equal(t, tt.wantLink, policy.link("abc"))
+			assert.Equal(t, tt.wantTTL, formatTTL(policy.passwordResetTTL))
+			assert.Equal(t, defaultVerificationTTL, policy.verificationTTL)
+		})
+	}
+}
@@ -3,5 +3,5 @@ package biz
 import "go.uber.org/fx"
 
 var Module = fx.Module("biz",
-	fx.Provide(NewUserUseCase),
+	fx.Provide(NewUserUseCase, NewSearchUseCase, NewAccountUseCase),
 )
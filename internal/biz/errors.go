@@ -0,0 +1,105 @@
+package biz
+
+import (
+	"maps"
+)
+
+// ErrorDomain 错误详情 ErrorInfo 中的 domain，业务错误和拦截器返回的错误共用
+const ErrorDomain = "connect-go-example"
+
+// Kind 业务错误的类别，service 层据此选择 Connect 错误码
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindInvalidArgument
+	KindNotFound
+	KindAlreadyExists
+	KindUnauthenticated
+	KindPermissionDenied
+	KindFailedPrecondition
+	KindResourceExhausted
+	KindUnavailable
+)
+
+// 错误原因，与 user.v1.ErrorReason 的枚举值（去掉 ERROR_REASON_ 前缀）一一对应，客户端据此区分错误
+const (
+	ReasonUserAlreadyExists    = "USER_ALREADY_EXISTS"
+	ReasonUserNotFound         = "USER_NOT_FOUND"
+	ReasonInvalidCredentials   = "INVALID_CREDENTIALS"
+	ReasonInvalidPageToken     = "INVALID_PAGE_TOKEN"
+	ReasonSessionNotFound      = "SESSION_NOT_FOUND"
+	ReasonInvalidRefreshToken  = "INVALID_REFRESH_TOKEN"
+	ReasonRefreshTokenReused   = "REFRESH_TOKEN_REUSED"
+	ReasonEmptySearchQuery     = "EMPTY_SEARCH_QUERY"
+	ReasonInvalidArgument      = "INVALID_ARGUMENT"
+	ReasonPermissionDenied     = "PERMISSION_DENIED"
+	ReasonAccountLocked        = "ACCOUNT_LOCKED"
+	ReasonMFAUnavailable       = "MFA_UNAVAILABLE"
+	ReasonMFANotEnrolled       = "MFA_NOT_ENROLLED"
+	ReasonMFAAlreadyEnabled    = "MFA_ALREADY_ENABLED"
+	ReasonInvalidMFACode       = "INVALID_MFA_CODE"
+	ReasonInvalidMFAChallenge  = "INVALID_MFA_CHALLENGE"
+	ReasonInvalidToken         = "INVALID_TOKEN"
+	ReasonEmailNotSet          = "EMAIL_NOT_SET"
+	ReasonEmailAlreadyVerified = "EMAIL_ALREADY_VERIFIED"
+	ReasonSearchUnavailable    = "SEARCH_UNAVAILABLE"
+)
+
+// FieldViolation 请求中某个字段不合法
+type FieldViolation struct {
+	Field       string // 字段路径，例如 "page_token"
+	Description string
+}
+
+// Error 业务错误，携带机器可读的原因、元数据和可选的字段校验信息
+// errors.Is 按 Reason 比较，因此附加了元数据的副本仍然匹配对应的哨兵错误
+type Error struct {
+	Kind     Kind
+	Reason   string
+	Message  string
+	Metadata map[string]string
+	Field    *FieldViolation
+}
+
+// NewError 创建业务错误
+func NewError(kind Kind, reason, message string) *Error {
+	return &Error{Kind: kind, Reason: reason, Message: message}
+}
+
+// InvalidArgument 创建字段校验错误
+func InvalidArgument(field, description string) *Error {
+	return &Error{
+		Kind:    KindInvalidArgument,
+		Reason:  ReasonInvalidArgument,
+		Message: description,
+		Field:   &FieldViolation{Field: field, Description: description},
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Reason == e.Reason
+}
+
+// WithMetadata 返回附加了元数据的副本，不修改哨兵错误本身
+func (e *Error) WithMetadata(key, value string) *Error {
+	clone := *e
+	clone.Metadata = maps.Clone(e.Metadata)
+	if clone.Metadata == nil {
+		clone.Metadata = make(map[string]string)
+	}
+	clone.Metadata[key] = value
+	return &clone
+}
+
+// WithField 返回附加了字段校验信息的副本
+func (e *Error) WithField(field, description string) *Error {
+	clone := *e
+	clone.Field = &FieldViolation{Field: field, Description: description}
+	return &clone
+}
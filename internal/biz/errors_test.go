@@ -0,0 +1,32 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError_IsMatchesReason(t *testing.T) {
+	err := fmt.Errorf("get user: %w", ErrUserNotFound.WithMetadata("id", "42"))
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.NotErrorIs(t, err, ErrUserAlreadyExists)
+
+	var bizErr *Error
+	require.True(t, errors.As(err, &bizErr))
+	assert.Equal(t, map[string]string{"id": "42"}, bizErr.Metadata)
+	// 哨兵错误本身不被修改
+	assert.Nil(t, ErrUserNotFound.Metadata)
+}
+
+func TestInvalidArgument(t *testing.T) {
+	err := InvalidArgument("username", "username is required")
+
+	assert.Equal(t, KindInvalidArgument, err.Kind)
+	assert.Equal(t, ReasonInvalidArgument, err.Reason)
+	assert.Equal(t, &FieldViolation{Field: "username", Description: "username is required"}, err.Field)
+	assert.EqualError(t, err, "username is required")
+}
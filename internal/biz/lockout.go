@@ -0,0 +1,181 @@
+package biz
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	conf "connect-go-example/internal/conf/v1"
+
+	"go.uber.org/zap"
+)
+
+var ErrAccountLocked = NewError(KindFailedPrecondition, ReasonAccountLocked, "account is temporarily locked due to too many failed sign-in attempts")
+
+// 锁定策略的默认值
+const (
+	defaultLockoutMaxFailures  = 5
+	defaultLockoutWindow       = 15 * time.Minute
+	defaultLockoutBaseDuration = time.Minute
+	defaultLockoutMaxDuration  = time.Hour
+	defaultLockoutResetAfter   = 24 * time.Hour
+)
+
+// LockoutPolicy 连续登录失败后的锁定策略
+// 窗口内失败 MaxFailures 次后锁定 BaseDuration，之后每次锁定时长翻倍，不超过 MaxDuration；
+// ResetAfter 内没有再次锁定时，锁定时长恢复为 BaseDuration
+type LockoutPolicy struct {
+	MaxFailures  int
+	Window       time.Duration
+	BaseDuration time.Duration
+	MaxDuration  time.Duration
+	ResetAfter   time.Duration
+}
+
+// newLockoutPolicy 根据配置创建锁定策略，0 值使用默认值，关闭时返回 nil
+func newLockoutPolicy(cfg *conf.Auth_Lockout) *LockoutPolicy {
+	if cfg.GetDisabled() {
+		return nil
+	}
+
+	policy := &LockoutPolicy{
+		MaxFailures:  int(cfg.GetMaxFailures()),
+		Window:       time.Duration(cfg.GetWindow()) * time.Second,
+		BaseDuration: time.Duration(cfg.GetBaseDuration()) * time.Second,
+		MaxDuration:  time.Duration(cfg.GetMaxDuration()) * time.Second,
+		ResetAfter:   time.Duration(cfg.GetResetAfter()) * time.Second,
+	}
+	if policy.MaxFailures <= 0 {
+		policy.MaxFailures = defaultLockoutMaxFailures
+	}
+	if policy.Window <= 0 {
+		policy.Window = defaultLockoutWindow
+	}
+	if policy.BaseDuration <= 0 {
+		policy.BaseDuration = defaultLockoutBaseDuration
+	}
+	if policy.MaxDuration <= 0 {
+		policy.MaxDuration = max(defaultLockoutMaxDuration, policy.BaseDuration)
+	}
+	if policy.ResetAfter <= 0 {
+		policy.ResetAfter = defaultLockoutResetAfter
+	}
+	return policy
+}
+
+// LockDuration 第 n 次（从 1 开始）锁定的时长
+func (p *LockoutPolicy) LockDuration(n int) time.Duration {
+	if n <= 1 {
+		return p.BaseDuration
+	}
+	// 指数超过上限后直接取上限，避免溢出
+	factor := math.Pow(2, float64(n-1))
+	if float64(p.BaseDuration)*factor >= float64(p.MaxDuration) {
+		return p.MaxDuration
+	}
+	return time.Duration(float64(p.BaseDuration) * factor)
+}
+
+// LoginResult 一次登录尝试的结果
+type LoginResult string
+
+const (
+	LoginResultSuccess         LoginResult = "success"
+	LoginResultInvalidPassword LoginResult = "invalid_password"
+	LoginResultUnknownUser     LoginResult = "unknown_user"
+	LoginResultLocked          LoginResult = "locked"
+	LoginResultMFARequired     LoginResult = "mfa_required"     // 密码正确，等待第二步验证
+	LoginResultInvalidMFACode  LoginResult = "invalid_mfa_code" // 第二步验证失败
+)
+
+// LoginAttempt 一次本地账号登录尝试，用于审计
+type LoginAttempt struct {
+	Username     string
+	UserID       int64 // 用户存在时的 ID，否则为 0
+	IP           string
+	ForwardedFor string
+	UserAgent    string
+	Result       LoginResult
+}
+
+// checkLockout 账号处于锁定状态时返回 ErrAccountLocked
+// 计数按用户名而不是用户 ID 记录，不存在的用户名同样会被锁定，避免通过锁定行为探测账号是否存在
+func (uc *UserUseCase) checkLockout(ctx context.Context, username string) error {
+	if uc.lockout == nil {
+		return nil
+	}
+
+	remaining, err := uc.repo.LockedFor(ctx, username)
+	if err != nil {
+		// 计数存储不可用时不阻止登录，密码校验和限流仍然生效
+		uc.l.Warn("Failed to check account lockout", zap.String("username", username), zap.Error(err))
+		return nil
+	}
+	if remaining <= 0 {
+		return nil
+	}
+	retryAfter := int64(math.Ceil(remaining.Seconds()))
+	return ErrAccountLocked.WithMetadata("retry_after", strconv.FormatInt(retryAfter, 10))
+}
+
+// recordFailure 记录一次失败，达到阈值时锁定账号
+func (uc *UserUseCase) recordFailure(ctx context.Context, username string) {
+	if uc.lockout == nil {
+		return
+	}
+
+	lockedFor, err := uc.repo.RecordLoginFailure(ctx, username, *uc.lockout)
+	if err != nil {
+		uc.l.Warn("Failed to record sign-in failure", zap.String("username", username), zap.Error(err))
+		return
+	}
+	if lockedFor > 0 {
+		uc.l.Warn("Account locked after repeated sign-in failures",
+			zap.String("username", username),
+			zap.Duration("duration", lockedFor),
+		)
+	}
+}
+
+// resetFailures 登录成功后清除失败计数
+func (uc *UserUseCase) resetFailures(ctx context.Context, username string) {
+	if uc.lockout == nil {
+		return
+	}
+
+	if err := uc.repo.ResetLoginFailures(ctx, username); err != nil {
+		uc.l.Warn("Failed to reset sign-in failures", zap.String("username", username), zap.Error(err))
+	}
+}
+
+// recordAttempt 写入登录记录，失败时只记录日志，不影响登录结果
+func (uc *UserUseCase) recordAttempt(ctx context.Context, attempt LoginAttempt) {
+	if err := uc.repo.RecordLoginAttempt(ctx, attempt); err != nil {
+		uc.l.Warn("Failed to record sign-in attempt", zap.String("username", attempt.Username), zap.Error(err))
+	}
+}
+
+// UnlockUser 解除账号的锁定并清除失败计数，只有管理员可以调用
+func (uc *UserUseCase) UnlockUser(ctx context.Context, id int64) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || !principal.IsAdmin() {
+		return ErrPermissionDenied
+	}
+
+	user, err := uc.repo.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.UnlockUser(ctx, user.Username); err != nil {
+		return err
+	}
+
+	uc.l.Info("Account unlocked",
+		zap.Int64("id", id),
+		zap.String("username", user.Username),
+		zap.String("operator", principal.Username),
+	)
+	return nil
+}
@@ -0,0 +1,177 @@
+package biz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	conf "connect-go-example/internal/conf/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+func (r *fakeUserRepo) LockedFor(ctx context.Context, username string) (time.Duration, error) {
+	return r.locked[username], nil
+}
+
+func (r *fakeUserRepo) RecordLoginFailure(ctx context.Context, username string, policy LockoutPolicy) (time.Duration, error) {
+	r.failures[username]++
+	if r.failures[username] < policy.MaxFailures {
+		return 0, nil
+	}
+	r.failures[username] = 0
+	r.lockouts[username]++
+	duration := policy.LockDuration(r.lockouts[username])
+	r.locked[username] = duration
+	return duration, nil
+}
+
+func (r *fakeUserRepo) ResetLoginFailures(ctx context.Context, username string) error {
+	delete(r.failures, username)
+	return nil
+}
+
+func (r *fakeUserRepo) UnlockUser(ctx context.Context, username string) error {
+	delete(r.failures, username)
+	delete(r.locked, username)
+	delete(r.lockouts, username)
+	return nil
+}
+
+func (r *fakeUserRepo) RecordLoginAttempt(ctx context.Context, attempt LoginAttempt) error {
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+func TestLockoutPolicy_LockDuration(t *testing.T) {
+	policy := newLockoutPolicy(&conf.Auth_Lockout{BaseDuration: 60, MaxDuration: 600})
+
+	assert.Equal(t, time.Minute, policy.LockDuration(1))
+	assert.Equal(t, 2*time.Minute, policy.LockDuration(2))
+	assert.Equal(t, 8*time.Minute, policy.LockDuration(4))
+	assert.Equal(t, 10*time.Minute, policy.LockDuration(5))
+	assert.Equal(t, 10*time.Minute, policy.LockDuration(1000))
+}
+
+func TestNewLockoutPolicy(t *testing.T) {
+	assert.Nil(t, newLockoutPolicy(&conf.Auth_Lockout{Disabled: true}))
+
+	policy := newLockoutPolicy(nil)
+	require.NotNil(t, policy)
+	assert.Equal(t, defaultLockoutMaxFailures, policy.MaxFailures)
+	assert.Equal(t, defaultLockoutWindow, policy.Window)
+	assert.Equal(t, defaultLockoutMaxDuration, policy.MaxDuration)
+}
+
+// LockoutTestSuite 测试登录失败锁定和登录记录
+type LockoutTestSuite struct {
+	suite.Suite
+	repo *fakeUserRepo
+	uc   *UserUseCase
+}
+
+func (suite *LockoutTestSuite) SetupTest() {
+	suite.repo = newFakeUserRepo()
+	suite.uc = NewUserUseCase(suite.repo, &fakeSessionRepo{}, newFakeMFARepo(), &conf.Bootstrap{Auth: &conf.Auth{
+		Lockout: &conf.Auth_Lockout{MaxFailures: 3, BaseDuration: 60},
+	}}, zap.NewNop())
+
+	_, err := suite.uc.Register(context.Background(), RegisterRequest{Username: "alice", Password: "s3cret-pass"})
+	require.NoError(suite.T(), err)
+}
+
+func (suite *LockoutTestSuite) signIn(username, password string) error {
+	_, err := suite.uc.PasswordSignIn(context.Background(), PasswordSignInRequest{
+		Username:  username,
+		Password:  password,
+		IP:        "192.0.2.1",
+		UserAgent: "test",
+	})
+	return err
+}
+
+func (suite *LockoutTestSuite) TestLocksAfterMaxFailures() {
+	for range 3 {
+		assert.ErrorIs(suite.T(), suite.signIn("alice", "wrong-pass"), ErrInvalidCredentials)
+	}
+
+	// 锁定期间正确的密码也无法登录
+	err := suite.signIn("alice", "s3cret-pass")
+	require.ErrorIs(suite.T(), err, ErrAccountLocked)
+	var bizErr *Error
+	require.ErrorAs(suite.T(), err, &bizErr)
+	assert.Equal(suite.T(), "60", bizErr.Metadata["retry_after"])
+
+	results := make([]LoginResult, 0, len(suite.repo.attempts))
+	for _, attempt := range suite.repo.attempts {
+		assert.Equal(suite.T(), "192.0.2.1", attempt.IP)
+		results = append(results, attempt.Result)
+	}
+	assert.Equal(suite.T(), []LoginResult{
+		LoginResultInvalidPassword,
+		LoginResultInvalidPassword,
+		LoginResultInvalidPassword,
+		LoginResultLocked,
+	}, results)
+}
+
+func (suite *LockoutTestSuite) TestUnknownUserIsLocked() {
+	for range 3 {
+		assert.ErrorIs(suite.T(), suite.signIn("mallory", "guess"), ErrInvalidCredentials)
+	}
+	assert.ErrorIs(suite.T(), suite.signIn("mallory", "guess"), ErrAccountLocked)
+	assert.Equal(suite.T(), LoginResultUnknownUser, suite.repo.attempts[0].Result)
+	assert.Zero(suite.T(), suite.repo.attempts[0].UserID)
+}
+
+func (suite *LockoutTestSuite) TestSuccessResetsFailures() {
+	for range 2 {
+		require.ErrorIs(suite.T(), suite.signIn("alice", "wrong-pass"), ErrInvalidCredentials)
+	}
+	require.NoError(suite.T(), suite.signIn("alice", "s3cret-pass"))
+	assert.Zero(suite.T(), suite.repo.failures["alice"])
+
+	last := suite.repo.attempts[len(suite.repo.attempts)-1]
+	assert.Equal(suite.T(), LoginResultSuccess, last.Result)
+	assert.Equal(suite.T(), int64(1), last.UserID)
+}
+
+func (suite *LockoutTestSuite) TestExponentialLockDuration() {
+	for range 3 {
+		_ = suite.signIn("alice", "wrong-pass")
+	}
+	assert.Equal(suite.T(), time.Minute, suite.repo.locked["alice"])
+
+	// 锁定到期后再次连续失败，锁定时长翻倍
+	delete(suite.repo.locked, "alice")
+	for range 3 {
+		_ = suite.signIn("alice", "wrong-pass")
+	}
+	assert.Equal(suite.T(), 2*time.Minute, suite.repo.locked["alice"])
+}
+
+func (suite *LockoutTestSuite) TestUnlockUser() {
+	for range 3 {
+		_ = suite.signIn("alice", "wrong-pass")
+	}
+	require.ErrorIs(suite.T(), suite.signIn("alice", "s3cret-pass"), ErrAccountLocked)
+
+	// 只有管理员可以解锁，本人也不行
+	assert.ErrorIs(suite.T(), suite.uc.UnlockUser(context.Background(), 1), ErrPermissionDenied)
+	assert.ErrorIs(suite.T(), suite.uc.UnlockUser(as(1), 1), ErrPermissionDenied)
+	require.ErrorIs(suite.T(), suite.signIn("alice", "s3cret-pass"), ErrAccountLocked)
+
+	admin := as(2, RoleAdmin)
+	require.NoError(suite.T(), suite.uc.UnlockUser(admin, 1))
+	assert.NoError(suite.T(), suite.signIn("alice", "s3cret-pass"))
+
+	assert.ErrorIs(suite.T(), suite.uc.UnlockUser(admin, 42), ErrUserNotFound)
+}
+
+// 运行测试套件
+func TestLockoutTestSuite(t *testing.T) {
+	suite.Run(t, new(LockoutTestSuite))
+}
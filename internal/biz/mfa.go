@@ -0,0 +1,344 @@
+package biz
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/totp"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrMFAUnavailable      = NewError(KindFailedPrecondition, ReasonMFAUnavailable, "multi-factor authentication is not available for this account")
+	ErrMFANotEnrolled      = NewError(KindFailedPrecondition, ReasonMFANotEnrolled, "multi-factor authentication is not enrolled")
+	ErrMFAAlreadyEnabled   = NewError(KindAlreadyExists, ReasonMFAAlreadyEnabled, "multi-factor authentication is already enabled")
+	ErrInvalidMFACode      = NewError(KindUnauthenticated, ReasonInvalidMFACode, "invalid verification code")
+	ErrInvalidMFAChallenge = NewError(KindUnauthenticated, ReasonInvalidMFAChallenge, "mfa challenge is invalid or expired")
+)
+
+// MFA 相关的默认值
+const (
+	defaultMFAIssuer         = "connect-go-example"
+	defaultMFAChallengeTTL   = 5 * time.Minute
+	defaultRecoveryCodeCount = 10
+
+	// maxMFAChallengeAttempts 同一个挑战令牌允许的验证失败次数，超过后令牌失效，需要重新输入密码
+	maxMFAChallengeAttempts = 5
+	// totpSkew 允许前后各一个时间步的时钟偏差
+	totpSkew = 1
+)
+
+// recoveryCodeEncoding 恢复码使用小写 base32，避免 0/O、1/I 等易混淆字符
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// MFA 用户的 TOTP 绑定状态
+type MFA struct {
+	UserID       int64
+	Secret       []byte // 解密后的 TOTP 密钥
+	Enabled      bool   // 已确认绑定
+	LastUsedStep int64  // 最近一次通过校验的时间步
+}
+
+// MFAChallenge 密码校验通过、等待第二步验证的登录
+type MFAChallenge struct {
+	Token     string // 不透明的挑战令牌，只在签发时返回
+	UserID    int64
+	Username  string
+	Roles     []string
+	ExpiresAt time.Time
+}
+
+// TOTPEnrollment 待确认的 TOTP 绑定
+type TOTPEnrollment struct {
+	Secret string // base32 编码的密钥
+	URI    string // otpauth URI
+}
+
+// VerifyMFARequest 登录第二步，Code 和 RecoveryCode 二选一
+type VerifyMFARequest struct {
+	ChallengeToken string
+	Code           string
+	RecoveryCode   string
+	// 以下字段只用于登录审计
+	IP           string
+	ForwardedFor string
+	UserAgent    string
+}
+
+// MFARepo MFA 密钥、恢复码和登录挑战的存储接口
+// 密钥在存储层加密，业务层只接触明文
+type MFARepo interface {
+	// GetMFA 查询用户的 TOTP 绑定，未绑定时返回 ErrMFANotEnrolled
+	GetMFA(ctx context.Context, userID int64) (*MFA, error)
+	// SavePendingMFA 保存待确认的密钥，覆盖之前未确认的密钥；已开启时返回 ErrMFAAlreadyEnabled
+	SavePendingMFA(ctx context.Context, userID int64, secret []byte) error
+	// EnableMFA 确认绑定并写入恢复码，step 为确认时使用的时间步
+	EnableMFA(ctx context.Context, userID int64, step int64, recoveryCodes []string) error
+	// UseTOTPStep 记录通过校验的时间步，step 不大于上次记录的值时返回 false
+	UseTOTPStep(ctx context.Context, userID int64, step int64) (bool, error)
+	// ReplaceRecoveryCodes 删除旧的恢复码并写入新的恢复码
+	ReplaceRecoveryCodes(ctx context.Context, userID int64, recoveryCodes []string) error
+	// RedeemRecoveryCode 使用一个恢复码，不存在或已使用时返回 false
+	RedeemRecoveryCode(ctx context.Context, userID int64, recoveryCode string) (bool, error)
+
+	// CreateChallenge 签发登录挑战令牌
+	CreateChallenge(ctx context.Context, user *UserInfo, ttl time.Duration) (*MFAChallenge, error)
+	// GetChallenge 查询挑战，不存在或已过期时返回 ErrInvalidMFAChallenge
+	GetChallenge(ctx context.Context, token string) (*MFAChallenge, error)
+	// RecordChallengeFailure 记录一次验证失败，达到 maxAttempts 时删除挑战
+	RecordChallengeFailure(ctx context.Context, token string, maxAttempts int) error
+	// DeleteChallenge 删除挑战，验证通过后调用，令牌不能再次使用；挑战已不存在时返回 ErrInvalidMFAChallenge
+	DeleteChallenge(ctx context.Context, token string) error
+}
+
+// mfaPolicy MFA 配置
+type mfaPolicy struct {
+	issuer            string
+	challengeTTL      time.Duration
+	recoveryCodeCount int
+}
+
+func newMFAPolicy(cfg *conf.Auth_Mfa) mfaPolicy {
+	policy := mfaPolicy{
+		issuer:            cfg.GetIssuer(),
+		challengeTTL:      time.Duration(cfg.GetChallengeTtl()) * time.Second,
+		recoveryCodeCount: int(cfg.GetRecoveryCodes()),
+	}
+	if policy.issuer == "" {
+		policy.issuer = defaultMFAIssuer
+	}
+	if policy.challengeTTL <= 0 {
+		policy.challengeTTL = defaultMFAChallengeTTL
+	}
+	if policy.recoveryCodeCount <= 0 {
+		policy.recoveryCodeCount = defaultRecoveryCodeCount
+	}
+	return policy
+}
+
+// mfaEnabled 用户是否开启了 MFA；查询失败时返回错误，不能跳过第二步
+func (uc *UserUseCase) mfaEnabled(ctx context.Context, userID int64) (bool, error) {
+	mfa, err := uc.mfa.GetMFA(ctx, userID)
+	if errors.Is(err, ErrMFANotEnrolled) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return mfa.Enabled, nil
+}
+
+// VerifyMFA 校验登录第二步，通过后签发会话
+// 验证失败同样计入账号锁定；同一个挑战失败次数过多时失效，需要重新输入密码
+func (uc *UserUseCase) VerifyMFA(ctx context.Context, req VerifyMFARequest) (*Session, error) {
+	challenge, err := uc.mfa.GetChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := func(result LoginResult) LoginAttempt {
+		return LoginAttempt{
+			Username:     challenge.Username,
+			UserID:       challenge.UserID,
+			IP:           req.IP,
+			ForwardedFor: req.ForwardedFor,
+			UserAgent:    req.UserAgent,
+			Result:       result,
+		}
+	}
+
+	if err := uc.checkLockout(ctx, challenge.Username); err != nil {
+		uc.recordAttempt(ctx, attempt(LoginResultLocked))
+		return nil, err
+	}
+
+	ok, err := uc.verifySecondFactor(ctx, challenge.UserID, req)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := uc.mfa.RecordChallengeFailure(ctx, req.ChallengeToken, maxMFAChallengeAttempts); err != nil {
+			uc.l.Warn("Failed to record mfa challenge failure", zap.String("username", challenge.Username), zap.Error(err))
+		}
+		uc.recordFailure(ctx, challenge.Username)
+		uc.recordAttempt(ctx, attempt(LoginResultInvalidMFACode))
+		return nil, ErrInvalidMFACode
+	}
+
+	if err := uc.mfa.DeleteChallenge(ctx, req.ChallengeToken); err != nil {
+		return nil, err
+	}
+	uc.resetFailures(ctx, challenge.Username)
+	uc.recordAttempt(ctx, attempt(LoginResultSuccess))
+	return uc.sessions.CreateSession(ctx, &UserInfo{ID: challenge.UserID, Username: challenge.Username, Roles: challenge.Roles})
+}
+
+// verifySecondFactor 校验 TOTP 验证码或恢复码
+func (uc *UserUseCase) verifySecondFactor(ctx context.Context, userID int64, req VerifyMFARequest) (bool, error) {
+	if req.RecoveryCode != "" {
+		ok, err := uc.mfa.RedeemRecoveryCode(ctx, userID, normalizeRecoveryCode(req.RecoveryCode))
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			uc.l.Info("Recovery code redeemed", zap.Int64("user_id", userID))
+		}
+		return ok, nil
+	}
+
+	mfa, err := uc.mfa.GetMFA(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if !mfa.Enabled {
+		return false, ErrMFANotEnrolled
+	}
+	return uc.useTOTPCode(ctx, mfa, req.Code)
+}
+
+// useTOTPCode 校验验证码并记录时间步，同一个验证码只能使用一次
+func (uc *UserUseCase) useTOTPCode(ctx context.Context, mfa *MFA, code string) (bool, error) {
+	step, ok := totp.Validate(mfa.Secret, code, time.Now(), totpSkew)
+	if !ok {
+		return false, nil
+	}
+	return uc.mfa.UseTOTPStep(ctx, mfa.UserID, step)
+}
+
+// EnrollTOTP 为当前用户生成新的 TOTP 密钥，调用 ConfirmTOTP 之前不生效
+func (uc *UserUseCase) EnrollTOTP(ctx context.Context) (*TOTPEnrollment, error) {
+	principal, err := localPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.mfa.SavePendingMFA(ctx, principal.UserID, secret); err != nil {
+		return nil, err
+	}
+
+	return &TOTPEnrollment{
+		Secret: totp.EncodeSecret(secret),
+		URI:    totp.KeyURI(uc.mfaPolicy.issuer, principal.Username, secret),
+	}, nil
+}
+
+// ConfirmTOTP 使用验证器应用生成的验证码确认绑定，开启 MFA 并返回恢复码
+func (uc *UserUseCase) ConfirmTOTP(ctx context.Context, code string) ([]string, error) {
+	principal, err := localPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mfa, err := uc.mfa.GetMFA(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	step, ok := totp.Validate(mfa.Secret, code, time.Now(), totpSkew)
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, err := uc.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.mfa.EnableMFA(ctx, principal.UserID, step, normalizeRecoveryCodes(codes)); err != nil {
+		return nil, err
+	}
+
+	uc.l.Info("MFA enabled", zap.Int64("user_id", principal.UserID), zap.String("username", principal.Username))
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes 校验当前的验证码后重新生成恢复码，旧的恢复码全部失效
+func (uc *UserUseCase) RegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error) {
+	principal, err := localPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mfa, err := uc.mfa.GetMFA(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !mfa.Enabled {
+		return nil, ErrMFANotEnrolled
+	}
+
+	ok, err := uc.useTOTPCode(ctx, mfa, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, err := uc.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.mfa.ReplaceRecoveryCodes(ctx, principal.UserID, normalizeRecoveryCodes(codes)); err != nil {
+		return nil, err
+	}
+
+	uc.l.Info("Recovery codes regenerated", zap.Int64("user_id", principal.UserID))
+	return codes, nil
+}
+
+// localPrincipal 返回本地账号的调用方身份，Casdoor 账号由 Casdoor 自己管理 MFA
+func localPrincipal(ctx context.Context) (*Principal, error) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID <= 0 {
+		return nil, ErrMFAUnavailable
+	}
+	return principal, nil
+}
+
+// generateRecoveryCodes 生成一组形如 abcde-fghij 的恢复码，每个 50 位熵
+func (uc *UserUseCase) generateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, uc.mfaPolicy.recoveryCodeCount)
+	for range uc.mfaPolicy.recoveryCodeCount {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, fmt.Errorf("generate recovery code failed: %w", err)
+		}
+		encoded := recoveryCodeEncoding.EncodeToString(b)[:10]
+		codes = append(codes, encoded[:5]+"-"+encoded[5:])
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode 去掉分隔符和空白并转为小写，用户输入时可以忽略格式
+func normalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ', '\t':
+			return -1
+		}
+		return r
+	}, strings.ToLower(code))
+}
+
+// normalizeRecoveryCodes 存储前统一格式，与兑换时的输入保持一致
+func normalizeRecoveryCodes(codes []string) []string {
+	normalized := make([]string, len(codes))
+	for i, code := range codes {
+		normalized[i] = normalizeRecoveryCode(code)
+	}
+	return normalized
+}
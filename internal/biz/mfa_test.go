@@ -0,0 +1,292 @@
+package biz
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/totp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+// fakeMFARepo 是基于内存的 MFARepo 实现
+type fakeMFARepo struct {
+	mfa        map[int64]*MFA
+	codes      map[int64]map[string]bool // 恢复码 -> 是否已使用
+	challenges map[string]*MFAChallenge
+	failures   map[string]int
+}
+
+func newFakeMFARepo() *fakeMFARepo {
+	return &fakeMFARepo{
+		mfa:        map[int64]*MFA{},
+		codes:      map[int64]map[string]bool{},
+		challenges: map[string]*MFAChallenge{},
+		failures:   map[string]int{},
+	}
+}
+
+func (r *fakeMFARepo) GetMFA(ctx context.Context, userID int64) (*MFA, error) {
+	mfa, ok := r.mfa[userID]
+	if !ok {
+		return nil, ErrMFANotEnrolled
+	}
+	clone := *mfa
+	return &clone, nil
+}
+
+func (r *fakeMFARepo) SavePendingMFA(ctx context.Context, userID int64, secret []byte) error {
+	if mfa, ok := r.mfa[userID]; ok && mfa.Enabled {
+		return ErrMFAAlreadyEnabled
+	}
+	r.mfa[userID] = &MFA{UserID: userID, Secret: secret}
+	return nil
+}
+
+func (r *fakeMFARepo) EnableMFA(ctx context.Context, userID int64, step int64, recoveryCodes []string) error {
+	mfa, ok := r.mfa[userID]
+	if !ok || mfa.Enabled {
+		return ErrMFAAlreadyEnabled
+	}
+	mfa.Enabled, mfa.LastUsedStep = true, step
+	return r.ReplaceRecoveryCodes(ctx, userID, recoveryCodes)
+}
+
+func (r *fakeMFARepo) UseTOTPStep(ctx context.Context, userID int64, step int64) (bool, error) {
+	mfa := r.mfa[userID]
+	if step <= mfa.LastUsedStep {
+		return false, nil
+	}
+	mfa.LastUsedStep = step
+	return true, nil
+}
+
+func (r *fakeMFARepo) ReplaceRecoveryCodes(ctx context.Context, userID int64, recoveryCodes []string) error {
+	r.codes[userID] = map[string]bool{}
+	for _, code := range recoveryCodes {
+		r.codes[userID][code] = false
+	}
+	return nil
+}
+
+func (r *fakeMFARepo) RedeemRecoveryCode(ctx context.Context, userID int64, recoveryCode string) (bool, error) {
+	used, ok := r.codes[userID][recoveryCode]
+	if !ok || used {
+		return false, nil
+	}
+	r.codes[userID][recoveryCode] = true
+	return true, nil
+}
+
+func (r *fakeMFARepo) CreateChallenge(ctx context.Context, user *UserInfo, ttl time.Duration) (*MFAChallenge, error) {
+	challenge := &MFAChallenge{
+		Token:     "challenge-" + user.Username + "-" + string(rune('a'+len(r.challenges))),
+		UserID:    user.ID,
+		Username:  user.Username,
+		Roles:     user.Roles,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	r.challenges[challenge.Token] = challenge
+	return challenge, nil
+}
+
+func (r *fakeMFARepo) GetChallenge(ctx context.Context, token string) (*MFAChallenge, error) {
+	challenge, ok := r.challenges[token]
+	if !ok {
+		return nil, ErrInvalidMFAChallenge
+	}
+	return challenge, nil
+}
+
+func (r *fakeMFARepo) RecordChallengeFailure(ctx context.Context, token string, maxAttempts int) error {
+	r.failures[token]++
+	if r.failures[token] >= maxAttempts {
+		delete(r.challenges, token)
+	}
+	return nil
+}
+
+func (r *fakeMFARepo) DeleteChallenge(ctx context.Context, token string) error {
+	if _, ok := r.challenges[token]; !ok {
+		return ErrInvalidMFAChallenge
+	}
+	delete(r.challenges, token)
+	return nil
+}
+
+// MFATestSuite 测试 TOTP 绑定和登录第二步
+type MFATestSuite struct {
+	suite.Suite
+	repo     *fakeUserRepo
+	sessions *fakeSessionRepo
+	mfa      *fakeMFARepo
+	uc       *UserUseCase
+	ctx      context.Context // alice 已登录
+}
+
+func (suite *MFATestSuite) SetupTest() {
+	suite.repo = newFakeUserRepo()
+	suite.sessions = &fakeSessionRepo{}
+	suite.mfa = newFakeMFARepo()
+	suite.uc = NewUserUseCase(suite.repo, suite.sessions, suite.mfa, &conf.Bootstrap{Auth: &conf.Auth{
+		Lockout: &conf.Auth_Lockout{MaxFailures: 10},
+		Mfa:     &conf.Auth_Mfa{Issuer: "Example", RecoveryCodes: 3},
+	}}, zap.NewNop())
+
+	user, err := suite.uc.Register(context.Background(), RegisterRequest{Username: "alice", Password: "s3cret-pass"})
+	require.NoError(suite.T(), err)
+	suite.ctx = NewPrincipalContext(context.Background(), &Principal{
+		Source:   PrincipalSourceSession,
+		UserID:   user.ID,
+		Username: user.Username,
+	})
+}
+
+// enable 绑定并开启 MFA，返回恢复码
+func (suite *MFATestSuite) enable() []string {
+	_, err := suite.uc.EnrollTOTP(suite.ctx)
+	require.NoError(suite.T(), err)
+
+	codes, err := suite.uc.ConfirmTOTP(suite.ctx, totp.Code(suite.mfa.mfa[1].Secret, time.Now()))
+	require.NoError(suite.T(), err)
+	return codes
+}
+
+// nextCode 返回下一个时间步的验证码，避免与上一次使用的验证码重复
+func (suite *MFATestSuite) nextCode() string {
+	return totp.Code(suite.mfa.mfa[1].Secret, time.Now().Add(totp.Period))
+}
+
+func (suite *MFATestSuite) signIn() *MFAChallenge {
+	res, err := suite.uc.PasswordSignIn(context.Background(), PasswordSignInRequest{Username: "alice", Password: "s3cret-pass"})
+	require.NoError(suite.T(), err)
+	require.Nil(suite.T(), res.Session)
+	require.NotNil(suite.T(), res.Challenge)
+	return res.Challenge
+}
+
+func (suite *MFATestSuite) TestEnrollAndConfirm() {
+	enrollment, err := suite.uc.EnrollTOTP(suite.ctx)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), totp.EncodeSecret(suite.mfa.mfa[1].Secret), enrollment.Secret)
+	assert.True(suite.T(), strings.HasPrefix(enrollment.URI, "otpauth://totp/Example:alice?"))
+
+	// 确认前不影响登录
+	res, err := suite.uc.PasswordSignIn(context.Background(), PasswordSignInRequest{Username: "alice", Password: "s3cret-pass"})
+	require.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), res.Session)
+
+	_, err = suite.uc.ConfirmTOTP(suite.ctx, "000000")
+	assert.ErrorIs(suite.T(), err, ErrInvalidMFACode)
+
+	codes, err := suite.uc.ConfirmTOTP(suite.ctx, totp.Code(suite.mfa.mfa[1].Secret, time.Now()))
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), codes, 3)
+	for _, code := range codes {
+		assert.Regexp(suite.T(), `^[a-z2-7]{5}-[a-z2-7]{5}$`, code)
+	}
+	assert.True(suite.T(), suite.mfa.mfa[1].Enabled)
+
+	_, err = suite.uc.EnrollTOTP(suite.ctx)
+	assert.ErrorIs(suite.T(), err, ErrMFAAlreadyEnabled)
+	_, err = suite.uc.ConfirmTOTP(suite.ctx, suite.nextCode())
+	assert.ErrorIs(suite.T(), err, ErrMFAAlreadyEnabled)
+}
+
+func (suite *MFATestSuite) TestRequiresLocalAccount() {
+	_, err := suite.uc.EnrollTOTP(context.Background())
+	assert.ErrorIs(suite.T(), err, ErrMFAUnavailable)
+
+	jwtCtx := NewPrincipalContext(context.Background(), &Principal{Source: PrincipalSourceJWT, Subject: "casdoor-id"})
+	_, err = suite.uc.EnrollTOTP(jwtCtx)
+	assert.ErrorIs(suite.T(), err, ErrMFAUnavailable)
+
+	_, err = suite.uc.ConfirmTOTP(suite.ctx, "123456")
+	assert.ErrorIs(suite.T(), err, ErrMFANotEnrolled)
+}
+
+func (suite *MFATestSuite) TestVerifyMFA_TOTP() {
+	suite.enable()
+	suite.repo.profiles[0].Roles = []string{RoleAdmin}
+	challenge := suite.signIn()
+	assert.Empty(suite.T(), suite.sessions.created)
+	assert.Equal(suite.T(), LoginResultMFARequired, suite.repo.attempts[len(suite.repo.attempts)-1].Result)
+
+	code := suite.nextCode()
+	session, err := suite.uc.VerifyMFA(context.Background(), VerifyMFARequest{ChallengeToken: challenge.Token, Code: code})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "alice", session.Username)
+	assert.Equal(suite.T(), []string{RoleAdmin}, session.Roles)
+	assert.Equal(suite.T(), LoginResultSuccess, suite.repo.attempts[len(suite.repo.attempts)-1].Result)
+
+	// 挑战令牌只能使用一次
+	_, err = suite.uc.VerifyMFA(context.Background(), VerifyMFARequest{ChallengeToken: challenge.Token, Code: code})
+	assert.ErrorIs(suite.T(), err, ErrInvalidMFAChallenge)
+
+	// 同一个验证码不能在新的登录中重放
+	_, err = suite.uc.VerifyMFA(context.Background(), VerifyMFARequest{ChallengeToken: suite.signIn().Token, Code: code})
+	assert.ErrorIs(suite.T(), err, ErrInvalidMFACode)
+}
+
+func (suite *MFATestSuite) TestVerifyMFA_RecoveryCode() {
+	codes := suite.enable()
+
+	// 忽略大小写和分隔符
+	input := strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))
+	_, err := suite.uc.VerifyMFA(context.Background(), VerifyMFARequest{ChallengeToken: suite.signIn().Token, RecoveryCode: input})
+	require.NoError(suite.T(), err)
+
+	_, err = suite.uc.VerifyMFA(context.Background(), VerifyMFARequest{ChallengeToken: suite.signIn().Token, RecoveryCode: codes[0]})
+	assert.ErrorIs(suite.T(), err, ErrInvalidMFACode)
+}
+
+func (suite *MFATestSuite) TestVerifyMFA_ChallengeExhausted() {
+	suite.enable()
+	challenge := suite.signIn()
+
+	for range maxMFAChallengeAttempts {
+		_, err := suite.uc.VerifyMFA(context.Background(), VerifyMFARequest{ChallengeToken: challenge.Token, RecoveryCode: "wrong"})
+		assert.ErrorIs(suite.T(), err, ErrInvalidMFACode)
+	}
+	assert.Equal(suite.T(), maxMFAChallengeAttempts, suite.repo.failures["alice"])
+
+	_, err := suite.uc.VerifyMFA(context.Background(), VerifyMFARequest{ChallengeToken: challenge.Token, Code: suite.nextCode()})
+	assert.ErrorIs(suite.T(), err, ErrInvalidMFAChallenge)
+}
+
+func (suite *MFATestSuite) TestPasswordStepKeepsFailures() {
+	suite.enable()
+	_, err := suite.uc.VerifyMFA(context.Background(), VerifyMFARequest{ChallengeToken: suite.signIn().Token, RecoveryCode: "wrong"})
+	require.ErrorIs(suite.T(), err, ErrInvalidMFACode)
+
+	// 重新输入正确的密码不会清除验证码的失败计数
+	suite.signIn()
+	assert.Equal(suite.T(), 1, suite.repo.failures["alice"])
+}
+
+func (suite *MFATestSuite) TestRegenerateRecoveryCodes() {
+	old := suite.enable()
+
+	_, err := suite.uc.RegenerateRecoveryCodes(suite.ctx, "000000")
+	assert.ErrorIs(suite.T(), err, ErrInvalidMFACode)
+
+	codes, err := suite.uc.RegenerateRecoveryCodes(suite.ctx, suite.nextCode())
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), codes, 3)
+
+	_, err = suite.uc.VerifyMFA(context.Background(), VerifyMFARequest{ChallengeToken: suite.signIn().Token, RecoveryCode: old[0]})
+	assert.ErrorIs(suite.T(), err, ErrInvalidMFACode)
+	_, err = suite.uc.VerifyMFA(context.Background(), VerifyMFARequest{ChallengeToken: suite.signIn().Token, RecoveryCode: codes[0]})
+	assert.NoError(suite.T(), err)
+}
+
+// 运行测试套件
+func TestMFATestSuite(t *testing.T) {
+	suite.Run(t, new(MFATestSuite))
+}
@@ -0,0 +1,51 @@
+package biz
+
+import (
+	"context"
+	"slices"
+)
+
+// PrincipalSource 调用方身份的来源
+type PrincipalSource string
+
+const (
+	PrincipalSourceSession PrincipalSource = "session" // 本地账号的 Redis 会话
+	PrincipalSourceJWT     PrincipalSource = "jwt"     // Casdoor 签发的 JWT
+)
+
+// Principal 已认证的调用方身份，由认证拦截器写入 context
+type Principal struct {
+	Source      PrincipalSource
+	UserID      int64  // 本地账号 ID，仅会话来源有值
+	Subject     string // Casdoor 用户 ID，仅 JWT 来源有值
+	Username    string
+	SessionID   string // 仅会话来源有值
+	Roles       []string
+	Permissions []string
+}
+
+// RoleAdmin 管理员角色，可以查看和修改任意用户的资料
+const RoleAdmin = "admin"
+
+// IsAdmin 调用方是否具备管理员角色
+func (p *Principal) IsAdmin() bool {
+	return slices.Contains(p.Roles, RoleAdmin)
+}
+
+// Owns 调用方是否是本地账号 id 本人，Casdoor 账号不拥有任何本地账号
+func (p *Principal) Owns(id int64) bool {
+	return p.UserID != 0 && p.UserID == id
+}
+
+type principalKey struct{}
+
+// NewPrincipalContext 返回携带调用方身份的 context
+func NewPrincipalContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext 取出调用方身份，未认证时返回 false
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
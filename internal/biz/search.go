@@ -0,0 +1,118 @@
+package biz
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// maxSearchWindow Elasticsearch 默认的 index.max_result_window，from+size 不能超过该值
+const maxSearchWindow = 10000
+
+var (
+	ErrEmptySearchQuery = NewError(KindInvalidArgument, ReasonEmptySearchQuery, "search query is empty").
+				WithField("query", "query is required")
+	ErrSearchUnavailable = NewError(KindUnavailable, ReasonSearchUnavailable, "search is temporarily unavailable")
+)
+
+type (
+	// SearchUsersRequest 全文搜索用户请求
+	SearchUsersRequest struct {
+		Query     string
+		PageSize  int
+		PageToken string // 上一页返回的 NextPageToken
+	}
+
+	// UserSearchHit 一条搜索结果
+	UserSearchHit struct {
+		User       *UserInfo
+		Score      float64
+		Highlights map[string][]string // 字段名到高亮片段
+	}
+
+	// SearchUsersResponse 搜索结果
+	SearchUsersResponse struct {
+		Hits          []*UserSearchHit
+		Total         int64
+		NextPageToken string // 为空表示没有下一页
+	}
+)
+
+// UserSearchRepo 用户全文检索接口
+type UserSearchRepo interface {
+	// SearchUsers 返回按相关度排序的第 offset 条起最多 limit 条结果，以及命中总数
+	// 索引尚未就绪时返回 ErrSearchUnavailable
+	SearchUsers(ctx context.Context, query string, offset, limit int) ([]*UserSearchHit, int64, error)
+}
+
+type SearchUseCase struct {
+	repo UserSearchRepo
+	l    *zap.Logger
+}
+
+func NewSearchUseCase(repo UserSearchRepo, logger *zap.Logger) *SearchUseCase {
+	return &SearchUseCase{
+		repo: repo,
+		l:    logger,
+	}
+}
+
+// SearchUsers 按关键字搜索用户，PageToken 对调用方不透明
+func (uc *SearchUseCase) SearchUsers(ctx context.Context, req SearchUsersRequest) (*SearchUsersResponse, error) {
+	if req.Query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	pageSize = min(pageSize, maxPageSize)
+
+	offset, err := decodeSearchPageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	// 超出 ES 结果窗口的部分无法通过 from/size 获取
+	limit := min(pageSize, maxSearchWindow-offset)
+	if limit <= 0 {
+		return &SearchUsersResponse{}, nil
+	}
+
+	hits, total, err := uc.repo.SearchUsers(ctx, req.Query, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hit := range hits {
+		hit.User = redactUser(ctx, hit.User)
+	}
+
+	res := &SearchUsersResponse{Hits: hits, Total: total}
+	if next := offset + len(hits); len(hits) == limit && int64(next) < total && next < maxSearchWindow {
+		res.NextPageToken = encodeSearchPageToken(next)
+	}
+	return res, nil
+}
+
+// encodeSearchPageToken 将下一页的起始偏移编码为不透明的分页令牌
+func encodeSearchPageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodeSearchPageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidPageToken
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset <= 0 {
+		return 0, ErrInvalidPageToken
+	}
+	return offset, nil
+}
@@ -0,0 +1,90 @@
+package biz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeUserSearchRepo 按偏移从固定的结果中截取
+type fakeUserSearchRepo struct {
+	total   int64
+	email   string
+	offsets []int
+}
+
+func (r *fakeUserSearchRepo) SearchUsers(ctx context.Context, query string, offset, limit int) ([]*UserSearchHit, int64, error) {
+	r.offsets = append(r.offsets, offset)
+	var hits []*UserSearchHit
+	for i := offset; i < offset+limit && int64(i) < r.total; i++ {
+		hits = append(hits, &UserSearchHit{User: &UserInfo{ID: int64(i + 1), Email: r.email}})
+	}
+	return hits, r.total, nil
+}
+
+func TestSearchUseCase_SearchUsers(t *testing.T) {
+	repo := &fakeUserSearchRepo{total: 5}
+	uc := NewSearchUseCase(repo, zap.NewNop())
+
+	var (
+		ids   []int64
+		token string
+	)
+	for {
+		res, err := uc.SearchUsers(context.Background(), SearchUsersRequest{Query: "ali", PageSize: 2, PageToken: token})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), res.Total)
+		for _, hit := range res.Hits {
+			ids = append(ids, hit.User.ID)
+		}
+		if token = res.NextPageToken; token == "" {
+			break
+		}
+	}
+
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, ids)
+	assert.Equal(t, []int{0, 2, 4}, repo.offsets)
+}
+
+func TestSearchUseCase_SearchUsers_RedactsEmail(t *testing.T) {
+	repo := &fakeUserSearchRepo{total: 2, email: "user@example.com"}
+	uc := NewSearchUseCase(repo, zap.NewNop())
+
+	res, err := uc.SearchUsers(as(1), SearchUsersRequest{Query: "user"})
+	require.NoError(t, err)
+	require.Len(t, res.Hits, 2)
+	assert.Equal(t, "user@example.com", res.Hits[0].User.Email)
+	assert.Empty(t, res.Hits[1].User.Email)
+
+	res, err = uc.SearchUsers(as(1, RoleAdmin), SearchUsersRequest{Query: "user"})
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", res.Hits[1].User.Email)
+}
+
+func TestSearchUseCase_SearchUsers_Invalid(t *testing.T) {
+	uc := NewSearchUseCase(&fakeUserSearchRepo{}, zap.NewNop())
+
+	_, err := uc.SearchUsers(context.Background(), SearchUsersRequest{})
+	assert.ErrorIs(t, err, ErrEmptySearchQuery)
+
+	_, err = uc.SearchUsers(context.Background(), SearchUsersRequest{Query: "ali", PageToken: "!!!"})
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+}
+
+func TestSearchUseCase_SearchUsers_ResultWindow(t *testing.T) {
+	repo := &fakeUserSearchRepo{total: 20000}
+	uc := NewSearchUseCase(repo, zap.NewNop())
+
+	res, err := uc.SearchUsers(context.Background(), SearchUsersRequest{
+		Query:     "ali",
+		PageSize:  100,
+		PageToken: encodeSearchPageToken(maxSearchWindow - 50),
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, res.Hits, 50)
+	assert.Empty(t, res.NextPageToken)
+}
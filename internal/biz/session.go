@@ -0,0 +1,40 @@
+package biz
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	ErrSessionNotFound     = NewError(KindUnauthenticated, ReasonSessionNotFound, "session not found or expired")
+	ErrInvalidRefreshToken = NewError(KindUnauthenticated, ReasonInvalidRefreshToken, "invalid refresh token")
+	ErrRefreshTokenReused  = NewError(KindUnauthenticated, ReasonRefreshTokenReused, "refresh token reused, session revoked")
+)
+
+// Session 登录会话
+// ID 是不透明的会话令牌，客户端以 Bearer 方式携带；RefreshToken 每次刷新都会轮换
+type Session struct {
+	ID               string
+	RefreshToken     string
+	FamilyID         string // 同一次登录派生出的所有会话共享一个 family，用于重放检测时整体吊销
+	UserID           int64
+	Username         string
+	Roles            []string // 登录时的角色，刷新会话时沿用，重新登录后生效
+	ExpiresAt        time.Time
+	RefreshExpiresAt time.Time
+}
+
+// SessionRepo 会话存储接口
+type SessionRepo interface {
+	// CreateSession 为用户创建新的会话族并签发会话 ID 和刷新令牌
+	CreateSession(ctx context.Context, user *UserInfo) (*Session, error)
+	// GetSession 按会话 ID 查询会话，不存在或已过期时返回 ErrSessionNotFound
+	GetSession(ctx context.Context, sessionID string) (*Session, error)
+	// RefreshSession 使用刷新令牌轮换会话，旧令牌立即失效
+	// 令牌被重复使用时吊销整个会话族并返回 ErrRefreshTokenReused
+	RefreshSession(ctx context.Context, refreshToken string) (*Session, error)
+	// RevokeSession 吊销会话 ID 所属的会话族
+	RevokeSession(ctx context.Context, sessionID string) error
+	// RevokeUserSessions 吊销用户的所有会话（退出所有设备）
+	RevokeUserSessions(ctx context.Context, userID int64) error
+}
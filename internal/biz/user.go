@@ -2,43 +2,367 @@ package biz
 
 import (
 	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/password"
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
 
 	"go.uber.org/zap"
 )
 
-var ErrUserAlreadyExists = errors.New("user Already Exists")
+var (
+	ErrUserAlreadyExists  = NewError(KindAlreadyExists, ReasonUserAlreadyExists, "user Already Exists")
+	ErrUserNotFound       = NewError(KindNotFound, ReasonUserNotFound, "user not found")
+	ErrInvalidCredentials = NewError(KindUnauthenticated, ReasonInvalidCredentials, "invalid username or password")
+	ErrInvalidPageToken   = NewError(KindInvalidArgument, ReasonInvalidPageToken, "invalid page token").
+				WithField("page_token", "page token is malformed or does not belong to this query")
+	ErrPermissionDenied = NewError(KindPermissionDenied, ReasonPermissionDenied, "permission denied")
+)
+
+const (
+	// defaultPageSize ListUsers 未指定每页数量时的默认值
+	defaultPageSize = 20
+	// maxPageSize ListUsers 每页数量的上限
+	maxPageSize = 100
+)
 
 // UserInfo 业务层用户模型
 type UserInfo struct {
+	ID            int64
+	Username      string
+	Nickname      string
+	Email         string
+	EmailVerified bool // 邮箱已通过 VerifyEmail 验证，修改邮箱后重置
+	Avatar        string
+	Roles         []string // 本地账号的角色，只在登录时加载并写入会话
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// UserCredential 本地账号的密码凭证
+type UserCredential struct {
+	PasswordHash string // PHC 格式的 argon2id 哈希
+	Salt         string
 }
 
 type (
+	// SignInRequest OAuth 授权码登录请求
 	SignInRequest struct {
+		Code  string // Casdoor 回调返回的授权码
+		State string // 防 CSRF 的 state 参数
 	}
 
+	// SignInResponse 登录结果
 	SignInResponse struct {
+		State string
+		Data  string // access token
+	}
+
+	// RegisterRequest 本地账号注册请求
+	RegisterRequest struct {
+		Username string
+		Password string
+	}
+
+	// PasswordSignInRequest 本地账号登录请求
+	PasswordSignInRequest struct {
+		Username string
+		Password string
+		// 以下字段只用于登录审计
+		IP           string
+		ForwardedFor string
+		UserAgent    string
+	}
+
+	// PasswordSignInResponse 本地账号登录结果，Session 和 Challenge 只有一个不为 nil
+	PasswordSignInResponse struct {
+		Session   *Session
+		Challenge *MFAChallenge // 账号开启了 MFA，需要调用 VerifyMFA 完成登录
+	}
+
+	// UpdateUserRequest 部分更新用户资料，为 nil 的字段保持不变
+	UpdateUserRequest struct {
+		ID       int64
+		Nickname *string
+		Email    *string
+		Avatar   *string
+	}
+
+	// ListUsersRequest 分页查询用户
+	ListUsersRequest struct {
+		PageSize  int
+		PageToken string // 上一页返回的 NextPageToken
+	}
+
+	// ListUsersResponse 分页查询结果
+	ListUsersResponse struct {
+		Users         []*UserInfo
+		NextPageToken string // 为空表示没有下一页
 	}
 )
 
 // UserRepo 用户接口
 type UserRepo interface {
 	SignIn(ctx context.Context, req SignInRequest) (*SignInResponse, error)
+	// CreateUser 创建本地账号，用户名已存在时返回 ErrUserAlreadyExists
+	CreateUser(ctx context.Context, username string, cred UserCredential) (*UserInfo, error)
+	// GetUserCredential 按用户名查询账号及其密码凭证，不存在时返回 ErrUserNotFound
+	GetUserCredential(ctx context.Context, username string) (*UserInfo, *UserCredential, error)
+	// GetUser 按 ID 查询未删除的用户，不存在时返回 ErrUserNotFound
+	GetUser(ctx context.Context, id int64) (*UserInfo, error)
+	// UpdateUser 部分更新用户资料，不存在时返回 ErrUserNotFound
+	UpdateUser(ctx context.Context, req UpdateUserRequest) (*UserInfo, error)
+	// DeleteUser 软删除用户，不存在时返回 ErrUserNotFound
+	DeleteUser(ctx context.Context, id int64) error
+	// ListUsers 按 ID 升序返回 ID 大于 afterID 的最多 limit 个用户
+	ListUsers(ctx context.Context, afterID int64, limit int) ([]*UserInfo, error)
+	// LockedFor 返回用户名剩余的锁定时间，未锁定时为 0
+	LockedFor(ctx context.Context, username string) (time.Duration, error)
+	// RecordLoginFailure 记录一次登录失败，本次失败触发锁定时返回锁定时长，否则为 0
+	RecordLoginFailure(ctx context.Context, username string, policy LockoutPolicy) (time.Duration, error)
+	// ResetLoginFailures 清除失败计数
+	ResetLoginFailures(ctx context.Context, username string) error
+	// UnlockUser 解除锁定，同时清除失败计数和锁定次数
+	UnlockUser(ctx context.Context, username string) error
+	// RecordLoginAttempt 写入登录记录
+	RecordLoginAttempt(ctx context.Context, attempt LoginAttempt) error
 }
 
 type UserUseCase struct {
-	repo UserRepo
-	cfg  *conf.Auth
+	repo      UserRepo
+	sessions  SessionRepo
+	mfa       MFARepo
+	cfg       *conf.Auth
+	lockout   *LockoutPolicy // 为 nil 表示不锁定账号
+	mfaPolicy mfaPolicy
+	l         *zap.Logger
 }
 
-func NewUserUseCase(repo UserRepo, cfg *conf.Bootstrap, logger *zap.Logger) *UserUseCase {
+func NewUserUseCase(repo UserRepo, sessions SessionRepo, mfa MFARepo, cfg *conf.Bootstrap, logger *zap.Logger) *UserUseCase {
 	return &UserUseCase{
-		repo: repo,
-		cfg:  cfg.Auth,
+		repo:      repo,
+		sessions:  sessions,
+		mfa:       mfa,
+		cfg:       cfg.Auth,
+		lockout:   newLockoutPolicy(cfg.GetAuth().GetLockout()),
+		mfaPolicy: newMFAPolicy(cfg.GetAuth().GetMfa()),
+		l:         logger,
 	}
 }
 
 func (uc *UserUseCase) SignIn(ctx context.Context, req SignInRequest) (*SignInResponse, error) {
 	return uc.repo.SignIn(ctx, req)
 }
+
+// Register 注册本地账号，密码使用 argon2id 哈希后存储
+func (uc *UserUseCase) Register(ctx context.Context, req RegisterRequest) (*UserInfo, error) {
+	hash, salt, err := password.Hash(req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("hash password failed: %w", err)
+	}
+
+	return uc.repo.CreateUser(ctx, req.Username, UserCredential{
+		PasswordHash: hash,
+		Salt:         salt,
+	})
+}
+
+// PasswordSignIn 校验本地账号的用户名和密码，成功后签发新的会话；
+// 账号开启了 MFA 时不签发会话，而是返回挑战令牌，由 VerifyMFA 完成第二步。
+// 用户不存在和密码错误统一返回 ErrInvalidCredentials，避免泄露账号是否存在；
+// 连续失败次数过多时锁定账号，锁定期间返回 ErrAccountLocked。每次尝试都会写入登录记录
+func (uc *UserUseCase) PasswordSignIn(ctx context.Context, req PasswordSignInRequest) (*PasswordSignInResponse, error) {
+	if err := uc.checkLockout(ctx, req.Username); err != nil {
+		uc.recordAttempt(ctx, req.attempt(0, LoginResultLocked))
+		return nil, err
+	}
+
+	user, cred, err := uc.repo.GetUserCredential(ctx, req.Username)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			// 对一个假哈希做同样的计算，使两种失败的响应耗时一致
+			_, _ = password.Verify(req.Password, dummyHash())
+			uc.recordFailure(ctx, req.Username)
+			uc.recordAttempt(ctx, req.attempt(0, LoginResultUnknownUser))
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	ok, err := password.Verify(req.Password, cred.PasswordHash)
+	if err != nil {
+		return nil, fmt.Errorf("verify password failed: %w", err)
+	}
+	if !ok {
+		uc.recordFailure(ctx, req.Username)
+		uc.recordAttempt(ctx, req.attempt(user.ID, LoginResultInvalidPassword))
+		return nil, ErrInvalidCredentials
+	}
+
+	mfaEnabled, err := uc.mfaEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfaEnabled {
+		// 失败计数等到第二步通过后再清除，避免交替输入密码绕过验证码的失败计数
+		challenge, err := uc.mfa.CreateChallenge(ctx, user, uc.mfaPolicy.challengeTTL)
+		if err != nil {
+			return nil, err
+		}
+		uc.recordAttempt(ctx, req.attempt(user.ID, LoginResultMFARequired))
+		return &PasswordSignInResponse{Challenge: challenge}, nil
+	}
+
+	uc.resetFailures(ctx, req.Username)
+	uc.recordAttempt(ctx, req.attempt(user.ID, LoginResultSuccess))
+	session, err := uc.sessions.CreateSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &PasswordSignInResponse{Session: session}, nil
+}
+
+// attempt 构造本次登录的审计记录
+func (req PasswordSignInRequest) attempt(userID int64, result LoginResult) LoginAttempt {
+	return LoginAttempt{
+		Username:     req.Username,
+		UserID:       userID,
+		IP:           req.IP,
+		ForwardedFor: req.ForwardedFor,
+		UserAgent:    req.UserAgent,
+		Result:       result,
+	}
+}
+
+// RefreshToken 使用刷新令牌换取新的会话和刷新令牌
+func (uc *UserUseCase) RefreshToken(ctx context.Context, refreshToken string) (*Session, error) {
+	session, err := uc.sessions.RefreshSession(ctx, refreshToken)
+	if errors.Is(err, ErrRefreshTokenReused) {
+		uc.l.Warn("Refresh token reuse detected, session family revoked")
+	}
+	return session, err
+}
+
+// SignOut 吊销会话；everywhere 为 true 时吊销该用户在所有设备上的会话
+func (uc *UserUseCase) SignOut(ctx context.Context, sessionID string, everywhere bool) error {
+	session, err := uc.sessions.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if everywhere {
+		return uc.sessions.RevokeUserSessions(ctx, session.UserID)
+	}
+	return uc.sessions.RevokeSession(ctx, sessionID)
+}
+
+// GetUser 查询用户资料，非本人且非管理员时不返回邮箱
+func (uc *UserUseCase) GetUser(ctx context.Context, id int64) (*UserInfo, error) {
+	user, err := uc.repo.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return redactUser(ctx, user), nil
+}
+
+// UpdateUser 修改用户资料，只有本人和管理员可以修改
+func (uc *UserUseCase) UpdateUser(ctx context.Context, req UpdateUserRequest) (*UserInfo, error) {
+	if err := authorizeUser(ctx, req.ID); err != nil {
+		return nil, err
+	}
+	return uc.repo.UpdateUser(ctx, req)
+}
+
+// DeleteUser 软删除用户，并吊销其所有会话；只有本人和管理员可以删除
+func (uc *UserUseCase) DeleteUser(ctx context.Context, id int64) error {
+	if err := authorizeUser(ctx, id); err != nil {
+		return err
+	}
+	if err := uc.repo.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+
+	if err := uc.sessions.RevokeUserSessions(ctx, id); err != nil {
+		uc.l.Warn("Failed to revoke sessions of deleted user", zap.Int64("id", id), zap.Error(err))
+	}
+	return nil
+}
+
+// ListUsers 基于游标分页查询用户，PageToken 对调用方不透明
+func (uc *UserUseCase) ListUsers(ctx context.Context, req ListUsersRequest) (*ListUsersResponse, error) {
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	pageSize = min(pageSize, maxPageSize)
+
+	afterID, err := decodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// 多查一条用于判断是否还有下一页
+	users, err := uc.repo.ListUsers(ctx, afterID, pageSize+1)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ListUsersResponse{Users: users}
+	if len(users) > pageSize {
+		res.Users = users[:pageSize]
+		res.NextPageToken = encodePageToken(res.Users[pageSize-1].ID)
+	}
+	for i, user := range res.Users {
+		res.Users[i] = redactUser(ctx, user)
+	}
+	return res, nil
+}
+
+// authorizeUser 只允许本人或管理员操作用户 id 的资料
+func authorizeUser(ctx context.Context, id int64) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || !(principal.Owns(id) || principal.IsAdmin()) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// redactUser 调用方不是本人且不是管理员时，返回去掉邮箱的副本
+func redactUser(ctx context.Context, user *UserInfo) *UserInfo {
+	if principal, ok := PrincipalFromContext(ctx); ok && (principal.Owns(user.ID) || principal.IsAdmin()) {
+		return user
+	}
+	redacted := *user
+	redacted.Email = ""
+	redacted.EmailVerified = false
+	return &redacted
+}
+
+// encodePageToken 将上一页最后一条记录的 ID 编码为不透明的分页令牌
+func encodePageToken(lastID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(lastID, 10)))
+}
+
+func decodePageToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidPageToken
+	}
+	lastID, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || lastID <= 0 {
+		return 0, ErrInvalidPageToken
+	}
+	return lastID, nil
+}
+
+var dummyHash = sync.OnceValue(func() string {
+	hash, _, _ := password.Hash("dummy-password")
+	return hash
+})
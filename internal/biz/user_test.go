@@ -0,0 +1,324 @@
+package biz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	conf "connect-go-example/internal/conf/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+// fakeUserRepo 是基于内存的 UserRepo 实现
+type fakeUserRepo struct {
+	users    map[string]*UserCredential
+	profiles []*UserInfo // 按 ID 升序
+	deleted  map[int64]bool
+
+	// 登录失败计数，不模拟过期
+	failures map[string]int
+	locked   map[string]time.Duration
+	lockouts map[string]int
+	attempts []LoginAttempt
+}
+
+func newFakeUserRepo() *fakeUserRepo {
+	return &fakeUserRepo{
+		users:    map[string]*UserCredential{},
+		deleted:  map[int64]bool{},
+		failures: map[string]int{},
+		locked:   map[string]time.Duration{},
+		lockouts: map[string]int{},
+	}
+}
+
+func (r *fakeUserRepo) SignIn(ctx context.Context, req SignInRequest) (*SignInResponse, error) {
+	return &SignInResponse{}, nil
+}
+
+func (r *fakeUserRepo) CreateUser(ctx context.Context, username string, cred UserCredential) (*UserInfo, error) {
+	if _, ok := r.users[username]; ok {
+		return nil, ErrUserAlreadyExists
+	}
+	r.users[username] = &cred
+	user := &UserInfo{ID: int64(len(r.profiles) + 1), Username: username}
+	r.profiles = append(r.profiles, user)
+	return user, nil
+}
+
+func (r *fakeUserRepo) GetUserCredential(ctx context.Context, username string) (*UserInfo, *UserCredential, error) {
+	cred, ok := r.users[username]
+	if !ok {
+		return nil, nil, ErrUserNotFound
+	}
+	for _, user := range r.profiles {
+		if user.Username == username {
+			return user, cred, nil
+		}
+	}
+	return &UserInfo{Username: username}, cred, nil
+}
+
+func (r *fakeUserRepo) GetUser(ctx context.Context, id int64) (*UserInfo, error) {
+	if id <= 0 || id > int64(len(r.profiles)) || r.deleted[id] {
+		return nil, ErrUserNotFound
+	}
+	return r.profiles[id-1], nil
+}
+
+func (r *fakeUserRepo) UpdateUser(ctx context.Context, req UpdateUserRequest) (*UserInfo, error) {
+	user, err := r.GetUser(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Nickname != nil {
+		user.Nickname = *req.Nickname
+	}
+	if req.Email != nil {
+		user.Email = *req.Email
+	}
+	if req.Avatar != nil {
+		user.Avatar = *req.Avatar
+	}
+	return user, nil
+}
+
+func (r *fakeUserRepo) DeleteUser(ctx context.Context, id int64) error {
+	if _, err := r.GetUser(ctx, id); err != nil {
+		return err
+	}
+	r.deleted[id] = true
+	return nil
+}
+
+func (r *fakeUserRepo) ListUsers(ctx context.Context, afterID int64, limit int) ([]*UserInfo, error) {
+	var users []*UserInfo
+	for _, user := range r.profiles {
+		if user.ID > afterID && !r.deleted[user.ID] && len(users) < limit {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+// fakeSessionRepo 只记录签发和吊销的会话
+type fakeSessionRepo struct {
+	SessionRepo
+	created []*Session
+	revoked []int64 // 被吊销全部会话的用户 ID
+}
+
+func (r *fakeSessionRepo) CreateSession(ctx context.Context, user *UserInfo) (*Session, error) {
+	s := &Session{ID: "sid", UserID: user.ID, Username: user.Username, Roles: user.Roles}
+	r.created = append(r.created, s)
+	return s, nil
+}
+
+func (r *fakeSessionRepo) RevokeUserSessions(ctx context.Context, userID int64) error {
+	r.revoked = append(r.revoked, userID)
+	return nil
+}
+
+// UserUseCaseTestSuite 是 UserUseCase 的测试套件
+type UserUseCaseTestSuite struct {
+	suite.Suite
+	repo     *fakeUserRepo
+	sessions *fakeSessionRepo
+	uc       *UserUseCase
+}
+
+func (suite *UserUseCaseTestSuite) SetupTest() {
+	suite.repo = newFakeUserRepo()
+	suite.sessions = &fakeSessionRepo{}
+	suite.uc = NewUserUseCase(suite.repo, suite.sessions, newFakeMFARepo(), &conf.Bootstrap{Auth: &conf.Auth{}}, zap.NewNop())
+}
+
+func (suite *UserUseCaseTestSuite) TestRegister_HashesPassword() {
+	user, err := suite.uc.Register(context.Background(), RegisterRequest{Username: "alice", Password: "s3cret-pass"})
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "alice", user.Username)
+
+	cred := suite.repo.users["alice"]
+	assert.NotContains(suite.T(), cred.PasswordHash, "s3cret-pass")
+	assert.NotEmpty(suite.T(), cred.Salt)
+}
+
+func (suite *UserUseCaseTestSuite) TestRegister_AlreadyExists() {
+	_, err := suite.uc.Register(context.Background(), RegisterRequest{Username: "alice", Password: "s3cret-pass"})
+	require.NoError(suite.T(), err)
+
+	_, err = suite.uc.Register(context.Background(), RegisterRequest{Username: "alice", Password: "another-pass"})
+
+	assert.ErrorIs(suite.T(), err, ErrUserAlreadyExists)
+}
+
+func (suite *UserUseCaseTestSuite) TestPasswordSignIn() {
+	_, err := suite.uc.Register(context.Background(), RegisterRequest{Username: "alice", Password: "s3cret-pass"})
+	require.NoError(suite.T(), err)
+	suite.repo.profiles[0].Roles = []string{RoleAdmin}
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		wantErr  error
+	}{
+		{"success", "alice", "s3cret-pass", nil},
+		{"wrong password", "alice", "wrong-pass", ErrInvalidCredentials},
+		{"unknown user", "bob", "s3cret-pass", ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			res, err := suite.uc.PasswordSignIn(context.Background(), PasswordSignInRequest{
+				Username: tt.username,
+				Password: tt.password,
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(suite.T(), err, tt.wantErr)
+				return
+			}
+			require.NoError(suite.T(), err)
+			assert.Equal(suite.T(), tt.username, res.Session.Username)
+			assert.Equal(suite.T(), []string{RoleAdmin}, res.Session.Roles)
+			assert.Nil(suite.T(), res.Challenge)
+			assert.Len(suite.T(), suite.sessions.created, 1)
+		})
+	}
+}
+
+func (suite *UserUseCaseTestSuite) register(usernames ...string) {
+	for _, username := range usernames {
+		_, err := suite.uc.Register(context.Background(), RegisterRequest{Username: username, Password: "s3cret-pass"})
+		require.NoError(suite.T(), err)
+	}
+}
+
+// as 返回以本地账号 id 身份调用的 context
+func as(id int64, roles ...string) context.Context {
+	return NewPrincipalContext(context.Background(), &Principal{Source: PrincipalSourceSession, UserID: id, Roles: roles})
+}
+
+func (suite *UserUseCaseTestSuite) TestUpdateUser_Partial() {
+	suite.register("alice")
+	nickname, email := "Alice", "alice@example.com"
+	_, err := suite.uc.UpdateUser(as(1), UpdateUserRequest{ID: 1, Email: &email})
+	require.NoError(suite.T(), err)
+
+	user, err := suite.uc.UpdateUser(as(1), UpdateUserRequest{ID: 1, Nickname: &nickname})
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Alice", user.Nickname)
+	assert.Equal(suite.T(), "alice@example.com", user.Email)
+}
+
+func (suite *UserUseCaseTestSuite) TestUpdateUser_PermissionDenied() {
+	suite.register("alice", "bob")
+	nickname := "Mallory"
+	jwt := NewPrincipalContext(context.Background(), &Principal{Source: PrincipalSourceJWT, Subject: "casdoor-id"})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{"anonymous", context.Background()},
+		{"other user", as(2)},
+		{"casdoor user", jwt},
+		{"non-admin role", as(2, "editor")},
+	}
+	for _, tt := range tests {
+		_, err := suite.uc.UpdateUser(tt.ctx, UpdateUserRequest{ID: 1, Nickname: &nickname})
+		assert.ErrorIs(suite.T(), err, ErrPermissionDenied, tt.name)
+		assert.ErrorIs(suite.T(), suite.uc.DeleteUser(tt.ctx, 1), ErrPermissionDenied, tt.name)
+	}
+	assert.Empty(suite.T(), suite.repo.profiles[0].Nickname)
+	assert.Empty(suite.T(), suite.sessions.revoked)
+
+	// 管理员可以修改任意用户
+	user, err := suite.uc.UpdateUser(as(2, RoleAdmin), UpdateUserRequest{ID: 1, Nickname: &nickname})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Mallory", user.Nickname)
+}
+
+func (suite *UserUseCaseTestSuite) TestGetUser_RedactsEmail() {
+	suite.register("alice", "bob")
+	email := "alice@example.com"
+	_, err := suite.uc.UpdateUser(as(1), UpdateUserRequest{ID: 1, Email: &email})
+	require.NoError(suite.T(), err)
+	suite.repo.profiles[0].EmailVerified = true
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantEmail string
+	}{
+		{"owner", as(1), email},
+		{"admin", as(2, RoleAdmin), email},
+		{"other user", as(2), ""},
+		{"anonymous", context.Background(), ""},
+	}
+	for _, tt := range tests {
+		user, err := suite.uc.GetUser(tt.ctx, 1)
+		require.NoError(suite.T(), err, tt.name)
+		assert.Equal(suite.T(), tt.wantEmail, user.Email, tt.name)
+		assert.Equal(suite.T(), tt.wantEmail != "", user.EmailVerified, tt.name)
+
+		res, err := suite.uc.ListUsers(tt.ctx, ListUsersRequest{})
+		require.NoError(suite.T(), err, tt.name)
+		assert.Equal(suite.T(), tt.wantEmail, res.Users[0].Email, tt.name)
+	}
+	// 脱敏不修改存储中的数据
+	assert.Equal(suite.T(), email, suite.repo.profiles[0].Email)
+}
+
+func (suite *UserUseCaseTestSuite) TestDeleteUser_RevokesSessions() {
+	suite.register("alice")
+
+	require.NoError(suite.T(), suite.uc.DeleteUser(as(1), 1))
+
+	assert.Equal(suite.T(), []int64{1}, suite.sessions.revoked)
+	_, err := suite.uc.GetUser(as(1), 1)
+	assert.ErrorIs(suite.T(), err, ErrUserNotFound)
+	assert.ErrorIs(suite.T(), suite.uc.DeleteUser(as(1), 1), ErrUserNotFound)
+}
+
+func (suite *UserUseCaseTestSuite) TestListUsers_Pagination() {
+	suite.register("u1", "u2", "u3", "u4", "u5")
+	require.NoError(suite.T(), suite.uc.DeleteUser(as(2), 2))
+
+	var (
+		names []string
+		token string
+		pages int
+	)
+	for {
+		res, err := suite.uc.ListUsers(context.Background(), ListUsersRequest{PageSize: 2, PageToken: token})
+		require.NoError(suite.T(), err)
+		for _, user := range res.Users {
+			names = append(names, user.Username)
+		}
+		pages++
+		if token = res.NextPageToken; token == "" {
+			break
+		}
+	}
+
+	assert.Equal(suite.T(), []string{"u1", "u3", "u4", "u5"}, names)
+	assert.Equal(suite.T(), 2, pages)
+}
+
+func (suite *UserUseCaseTestSuite) TestListUsers_InvalidPageToken() {
+	for _, token := range []string{"!!!", encodePageToken(0), "YWJj"} {
+		_, err := suite.uc.ListUsers(context.Background(), ListUsersRequest{PageToken: token})
+		assert.ErrorIs(suite.T(), err, ErrInvalidPageToken, token)
+	}
+}
+
+// 运行测试套件
+func TestUserUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(UserUseCaseTestSuite))
+}
@@ -29,6 +29,9 @@ type Bootstrap struct {
 	Trace         *Trace                 `protobuf:"bytes,4,opt,name=trace,proto3" json:"trace,omitempty"`
 	Discovery     *Discovery             `protobuf:"bytes,5,opt,name=discovery,proto3" json:"discovery,omitempty"`
 	Search        *Search                `protobuf:"bytes,6,opt,name=search,proto3" json:"search,omitempty"`
+	Authorization *Authorization         `protobuf:"bytes,7,opt,name=authorization,proto3" json:"authorization,omitempty"`
+	RateLimit     *RateLimit             `protobuf:"bytes,8,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	Mail          *Mail                  `protobuf:"bytes,9,opt,name=mail,proto3" json:"mail,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -105,9 +108,32 @@ func (x *Bootstrap) GetSearch() *Search {
 	return nil
 }
 
+func (x *Bootstrap) GetAuthorization() *Authorization {
+	if x != nil {
+		return x.Authorization
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetRateLimit() *RateLimit {
+	if x != nil {
+		return x.RateLimit
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetMail() *Mail {
+	if x != nil {
+		return x.Mail
+	}
+	return nil
+}
+
 type Server struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Http          *Server_HTTP           `protobuf:"bytes,1,opt,name=http,proto3" json:"http,omitempty"`
+	Health        *Server_Health         `protobuf:"bytes,2,opt,name=health,proto3" json:"health,omitempty"`
+	Shutdown      *Server_Shutdown       `protobuf:"bytes,3,opt,name=shutdown,proto3" json:"shutdown,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -149,10 +175,25 @@ func (x *Server) GetHttp() *Server_HTTP {
 	return nil
 }
 
+func (x *Server) GetHealth() *Server_Health {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+func (x *Server) GetShutdown() *Server_Shutdown {
+	if x != nil {
+		return x.Shutdown
+	}
+	return nil
+}
+
 type Data struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Database      *Data_Database         `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
 	Redis         *Data_Redis            `protobuf:"bytes,2,opt,name=redis,proto3" json:"redis,omitempty"`
+	Outbox        *Data_Outbox           `protobuf:"bytes,3,opt,name=outbox,proto3" json:"outbox,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -201,6 +242,13 @@ func (x *Data) GetRedis() *Data_Redis {
 	return nil
 }
 
+func (x *Data) GetOutbox() *Data_Outbox {
+	if x != nil {
+		return x.Outbox
+	}
+	return nil
+}
+
 type Auth struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	Endpoint         string                 `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
@@ -209,6 +257,11 @@ type Auth struct {
 	OrganizationName string                 `protobuf:"bytes,4,opt,name=organization_name,json=organizationName,proto3" json:"organization_name,omitempty"`
 	ApplicationName  string                 `protobuf:"bytes,5,opt,name=application_name,json=applicationName,proto3" json:"application_name,omitempty"`
 	Certificate      string                 `protobuf:"bytes,6,opt,name=certificate,proto3" json:"certificate,omitempty"`
+	Session          *Auth_Session          `protobuf:"bytes,7,opt,name=session,proto3" json:"session,omitempty"`
+	PublicProcedures []string               `protobuf:"bytes,8,rep,name=public_procedures,json=publicProcedures,proto3" json:"public_procedures,omitempty"` // 额外的免认证 RPC，例如 /user.v1.UserService/SignIn
+	Lockout          *Auth_Lockout          `protobuf:"bytes,9,opt,name=lockout,proto3" json:"lockout,omitempty"`
+	Mfa              *Auth_Mfa              `protobuf:"bytes,10,opt,name=mfa,proto3" json:"mfa,omitempty"`
+	EmailTokens      *Auth_EmailTokens      `protobuf:"bytes,11,opt,name=email_tokens,json=emailTokens,proto3" json:"email_tokens,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -285,6 +338,224 @@ func (x *Auth) GetCertificate() string {
 	return ""
 }
 
+func (x *Auth) GetSession() *Auth_Session {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+func (x *Auth) GetPublicProcedures() []string {
+	if x != nil {
+		return x.PublicProcedures
+	}
+	return nil
+}
+
+func (x *Auth) GetLockout() *Auth_Lockout {
+	if x != nil {
+		return x.Lockout
+	}
+	return nil
+}
+
+func (x *Auth) GetMfa() *Auth_Mfa {
+	if x != nil {
+		return x.Mfa
+	}
+	return nil
+}
+
+func (x *Auth) GetEmailTokens() *Auth_EmailTokens {
+	if x != nil {
+		return x.EmailTokens
+	}
+	return nil
+}
+
+type Authorization struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Policies      []*Authorization_Policy `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	DefaultDeny   bool                    `protobuf:"varint,2,opt,name=default_deny,json=defaultDeny,proto3" json:"default_deny,omitempty"` // 没有匹配的策略时是否拒绝
+	ConsulKey     string                  `protobuf:"bytes,3,opt,name=consul_key,json=consulKey,proto3" json:"consul_key,omitempty"`        // 可选，从单独的 Consul KV 键加载策略，配置后忽略 policies
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Authorization) Reset() {
+	*x = Authorization{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Authorization) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Authorization) ProtoMessage() {}
+
+func (x *Authorization) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Authorization.ProtoReflect.Descriptor instead.
+func (*Authorization) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Authorization) GetPolicies() []*Authorization_Policy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+func (x *Authorization) GetDefaultDeny() bool {
+	if x != nil {
+		return x.DefaultDeny
+	}
+	return false
+}
+
+func (x *Authorization) GetConsulKey() string {
+	if x != nil {
+		return x.ConsulKey
+	}
+	return ""
+}
+
+// RateLimit 按 procedure 配置的限流规则，计数保存在 Redis 中，Redis 不可用时退化为进程内限流
+// RequestPasswordReset（每小时 5 次）、ResetPassword、VerifyEmail 和 PasswordSignIn（每分钟 10 次）、SignIn（每分钟 20 次）默认按 ip 限流，可以通过同名规则覆盖
+type RateLimit struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Rules             []*RateLimit_Rule      `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	ApiKeyHeader      string                 `protobuf:"bytes,2,opt,name=api_key_header,json=apiKeyHeader,proto3" json:"api_key_header,omitempty"`                 // 携带 API key 的请求头，默认 X-API-Key
+	TrustForwardedFor bool                   `protobuf:"varint,3,opt,name=trust_forwarded_for,json=trustForwardedFor,proto3" json:"trust_forwarded_for,omitempty"` // 位于反向代理之后时从 X-Forwarded-For 读取客户端 IP
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RateLimit) Reset() {
+	*x = RateLimit{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimit) ProtoMessage() {}
+
+func (x *RateLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimit.ProtoReflect.Descriptor instead.
+func (*RateLimit) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RateLimit) GetRules() []*RateLimit_Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *RateLimit) GetApiKeyHeader() string {
+	if x != nil {
+		return x.ApiKeyHeader
+	}
+	return ""
+}
+
+func (x *RateLimit) GetTrustForwardedFor() bool {
+	if x != nil {
+		return x.TrustForwardedFor
+	}
+	return false
+}
+
+// Mail 事务性邮件的投递方式
+type Mail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Driver        string                 `protobuf:"bytes,1,opt,name=driver,proto3" json:"driver,omitempty"` // log（默认，只写日志）、smtp、memory（仅用于测试）
+	From          string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`     // 发件人，例如 "Example <no-reply@example.com>"
+	Smtp          *Mail_SMTP             `protobuf:"bytes,3,opt,name=smtp,proto3" json:"smtp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Mail) Reset() {
+	*x = Mail{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Mail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Mail) ProtoMessage() {}
+
+func (x *Mail) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Mail.ProtoReflect.Descriptor instead.
+func (*Mail) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Mail) GetDriver() string {
+	if x != nil {
+		return x.Driver
+	}
+	return ""
+}
+
+func (x *Mail) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *Mail) GetSmtp() *Mail_SMTP {
+	if x != nil {
+		return x.Smtp
+	}
+	return nil
+}
+
 type Trace struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Endpoint      string                 `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
@@ -295,7 +566,7 @@ type Trace struct {
 
 func (x *Trace) Reset() {
 	*x = Trace{}
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[4]
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -307,7 +578,7 @@ func (x *Trace) String() string {
 func (*Trace) ProtoMessage() {}
 
 func (x *Trace) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[4]
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -320,7 +591,7 @@ func (x *Trace) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Trace.ProtoReflect.Descriptor instead.
 func (*Trace) Descriptor() ([]byte, []int) {
-	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{4}
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *Trace) GetEndpoint() string {
@@ -338,15 +609,20 @@ func (x *Trace) GetInsecure() bool {
 }
 
 type Discovery struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Consul        *Discovery_Consul      `protobuf:"bytes,1,opt,name=consul,proto3" json:"consul,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Consul *Discovery_Consul      `protobuf:"bytes,1,opt,name=consul,proto3" json:"consul,omitempty"`
+	// 注册中心后端：consul、noop、static、file、kubernetes；默认配置了 consul.addr 时为 consul，否则为 noop
+	Backend       string                `protobuf:"bytes,2,opt,name=backend,proto3" json:"backend,omitempty"`
+	Static        *Discovery_Static     `protobuf:"bytes,3,opt,name=static,proto3" json:"static,omitempty"`
+	File          *Discovery_File       `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
+	Kubernetes    *Discovery_Kubernetes `protobuf:"bytes,5,opt,name=kubernetes,proto3" json:"kubernetes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Discovery) Reset() {
 	*x = Discovery{}
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[5]
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -358,7 +634,7 @@ func (x *Discovery) String() string {
 func (*Discovery) ProtoMessage() {}
 
 func (x *Discovery) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[5]
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -371,7 +647,7 @@ func (x *Discovery) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Discovery.ProtoReflect.Descriptor instead.
 func (*Discovery) Descriptor() ([]byte, []int) {
-	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{5}
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *Discovery) GetConsul() *Discovery_Consul {
@@ -381,6 +657,34 @@ func (x *Discovery) GetConsul() *Discovery_Consul {
 	return nil
 }
 
+func (x *Discovery) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *Discovery) GetStatic() *Discovery_Static {
+	if x != nil {
+		return x.Static
+	}
+	return nil
+}
+
+func (x *Discovery) GetFile() *Discovery_File {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+func (x *Discovery) GetKubernetes() *Discovery_Kubernetes {
+	if x != nil {
+		return x.Kubernetes
+	}
+	return nil
+}
+
 type Search struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ElasticSearch *Search_ElasticSearch  `protobuf:"bytes,1,opt,name=elastic_search,json=elasticSearch,proto3" json:"elastic_search,omitempty"`
@@ -390,7 +694,7 @@ type Search struct {
 
 func (x *Search) Reset() {
 	*x = Search{}
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[6]
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -402,7 +706,7 @@ func (x *Search) String() string {
 func (*Search) ProtoMessage() {}
 
 func (x *Search) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[6]
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -415,7 +719,7 @@ func (x *Search) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Search.ProtoReflect.Descriptor instead.
 func (*Search) Descriptor() ([]byte, []int) {
-	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{6}
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Search) GetElasticSearch() *Search_ElasticSearch {
@@ -435,7 +739,7 @@ type Server_HTTP struct {
 
 func (x *Server_HTTP) Reset() {
 	*x = Server_HTTP{}
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[7]
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -447,7 +751,7 @@ func (x *Server_HTTP) String() string {
 func (*Server_HTTP) ProtoMessage() {}
 
 func (x *Server_HTTP) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[7]
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -477,35 +781,29 @@ func (x *Server_HTTP) GetTimeout() int64 {
 	return 0
 }
 
-type Data_Database struct {
+type Server_Health struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Host          string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
-	Port          int32                  `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
-	User          string                 `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
-	Password      string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
-	DbName        string                 `protobuf:"bytes,5,opt,name=db_name,json=dbName,proto3" json:"db_name,omitempty"`
-	SslMode       string                 `protobuf:"bytes,6,opt,name=ssl_mode,json=sslMode,proto3" json:"ssl_mode,omitempty"`
-	Timezone      string                 `protobuf:"bytes,7,opt,name=timezone,proto3" json:"timezone,omitempty"`
-	Pool          *Data_DatabasePool     `protobuf:"bytes,8,opt,name=pool,proto3" json:"pool,omitempty"`
+	Interval      int64                  `protobuf:"varint,1,opt,name=interval,proto3" json:"interval,omitempty"` // 依赖探测间隔（秒），默认 10
+	Timeout       int64                  `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`   // 单个依赖的探测超时（秒），默认 3
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Data_Database) Reset() {
-	*x = Data_Database{}
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[8]
+func (x *Server_Health) Reset() {
+	*x = Server_Health{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Data_Database) String() string {
+func (x *Server_Health) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Data_Database) ProtoMessage() {}
+func (*Server_Health) ProtoMessage() {}
 
-func (x *Data_Database) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[8]
+func (x *Server_Health) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -516,30 +814,141 @@ func (x *Data_Database) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Data_Database.ProtoReflect.Descriptor instead.
-func (*Data_Database) Descriptor() ([]byte, []int) {
-	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{2, 0}
+// Deprecated: Use Server_Health.ProtoReflect.Descriptor instead.
+func (*Server_Health) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{1, 1}
 }
 
-func (x *Data_Database) GetHost() string {
+func (x *Server_Health) GetInterval() int64 {
 	if x != nil {
-		return x.Host
+		return x.Interval
 	}
-	return ""
+	return 0
 }
 
-func (x *Data_Database) GetPort() int32 {
+func (x *Server_Health) GetTimeout() int64 {
 	if x != nil {
-		return x.Port
+		return x.Timeout
 	}
 	return 0
 }
 
-func (x *Data_Database) GetUser() string {
-	if x != nil {
-		return x.User
-	}
-	return ""
+type Server_Shutdown struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	PropagationDelay int64                  `protobuf:"varint,1,opt,name=propagation_delay,json=propagationDelay,proto3" json:"propagation_delay,omitempty"` // 摘除流量后等待注册中心和负载均衡感知的时间（秒），默认 5
+	DrainTimeout     int64                  `protobuf:"varint,2,opt,name=drain_timeout,json=drainTimeout,proto3" json:"drain_timeout,omitempty"`             // 等待进行中请求完成的最长时间（秒），默认 15
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Server_Shutdown) Reset() {
+	*x = Server_Shutdown{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_Shutdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_Shutdown) ProtoMessage() {}
+
+func (x *Server_Shutdown) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_Shutdown.ProtoReflect.Descriptor instead.
+func (*Server_Shutdown) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{1, 2}
+}
+
+func (x *Server_Shutdown) GetPropagationDelay() int64 {
+	if x != nil {
+		return x.PropagationDelay
+	}
+	return 0
+}
+
+func (x *Server_Shutdown) GetDrainTimeout() int64 {
+	if x != nil {
+		return x.DrainTimeout
+	}
+	return 0
+}
+
+type Data_Database struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Host          string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Port          int32                  `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	User          string                 `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	Password      string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	DbName        string                 `protobuf:"bytes,5,opt,name=db_name,json=dbName,proto3" json:"db_name,omitempty"`
+	SslMode       string                 `protobuf:"bytes,6,opt,name=ssl_mode,json=sslMode,proto3" json:"ssl_mode,omitempty"`
+	Timezone      string                 `protobuf:"bytes,7,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Pool          *Data_DatabasePool     `protobuf:"bytes,8,opt,name=pool,proto3" json:"pool,omitempty"`
+	AutoMigrate   bool                   `protobuf:"varint,9,opt,name=auto_migrate,json=autoMigrate,proto3" json:"auto_migrate,omitempty"` // 启动时自动执行未应用的迁移
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Data_Database) Reset() {
+	*x = Data_Database{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Data_Database) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Data_Database) ProtoMessage() {}
+
+func (x *Data_Database) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Data_Database.ProtoReflect.Descriptor instead.
+func (*Data_Database) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{2, 0}
+}
+
+func (x *Data_Database) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *Data_Database) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *Data_Database) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
 }
 
 func (x *Data_Database) GetPassword() string {
@@ -577,19 +986,29 @@ func (x *Data_Database) GetPool() *Data_DatabasePool {
 	return nil
 }
 
+func (x *Data_Database) GetAutoMigrate() bool {
+	if x != nil {
+		return x.AutoMigrate
+	}
+	return false
+}
+
 type Data_DatabasePool struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	MaxConns        int32                  `protobuf:"varint,1,opt,name=max_conns,json=maxConns,proto3" json:"max_conns,omitempty"`
-	MinConns        int32                  `protobuf:"varint,2,opt,name=min_conns,json=minConns,proto3" json:"min_conns,omitempty"`
-	MaxConnLifetime int64                  `protobuf:"varint,3,opt,name=max_conn_lifetime,json=maxConnLifetime,proto3" json:"max_conn_lifetime,omitempty"`
-	MaxConnIdleTime int64                  `protobuf:"varint,4,opt,name=max_conn_idle_time,json=maxConnIdleTime,proto3" json:"max_conn_idle_time,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	MaxConns              int32                  `protobuf:"varint,1,opt,name=max_conns,json=maxConns,proto3" json:"max_conns,omitempty"`                                            // 最大连接数，0 使用 pgx 默认值 max(4, CPU 数)
+	MinConns              int32                  `protobuf:"varint,2,opt,name=min_conns,json=minConns,proto3" json:"min_conns,omitempty"`                                            // 最小连接数，不能大于 max_conns
+	MaxConnLifetime       int64                  `protobuf:"varint,3,opt,name=max_conn_lifetime,json=maxConnLifetime,proto3" json:"max_conn_lifetime,omitempty"`                     // 连接最长存活时间（秒）
+	MaxConnIdleTime       int64                  `protobuf:"varint,4,opt,name=max_conn_idle_time,json=maxConnIdleTime,proto3" json:"max_conn_idle_time,omitempty"`                   // 空闲连接的最长保留时间（秒）
+	HealthCheckPeriod     int64                  `protobuf:"varint,5,opt,name=health_check_period,json=healthCheckPeriod,proto3" json:"health_check_period,omitempty"`               // 空闲连接健康检查间隔（秒）
+	StatementTimeout      int64                  `protobuf:"varint,6,opt,name=statement_timeout,json=statementTimeout,proto3" json:"statement_timeout,omitempty"`                    // 单条语句超时（毫秒），0 表示不限制
+	MaxConnLifetimeJitter int64                  `protobuf:"varint,7,opt,name=max_conn_lifetime_jitter,json=maxConnLifetimeJitter,proto3" json:"max_conn_lifetime_jitter,omitempty"` // 连接存活时间的随机抖动（秒），避免连接同时过期
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *Data_DatabasePool) Reset() {
 	*x = Data_DatabasePool{}
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[9]
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -601,7 +1020,7 @@ func (x *Data_DatabasePool) String() string {
 func (*Data_DatabasePool) ProtoMessage() {}
 
 func (x *Data_DatabasePool) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[9]
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -645,6 +1064,27 @@ func (x *Data_DatabasePool) GetMaxConnIdleTime() int64 {
 	return 0
 }
 
+func (x *Data_DatabasePool) GetHealthCheckPeriod() int64 {
+	if x != nil {
+		return x.HealthCheckPeriod
+	}
+	return 0
+}
+
+func (x *Data_DatabasePool) GetStatementTimeout() int64 {
+	if x != nil {
+		return x.StatementTimeout
+	}
+	return 0
+}
+
+func (x *Data_DatabasePool) GetMaxConnLifetimeJitter() int64 {
+	if x != nil {
+		return x.MaxConnLifetimeJitter
+	}
+	return 0
+}
+
 type Data_Redis struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Host          string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
@@ -663,7 +1103,7 @@ type Data_Redis struct {
 
 func (x *Data_Redis) Reset() {
 	*x = Data_Redis{}
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[10]
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -675,7 +1115,7 @@ func (x *Data_Redis) String() string {
 func (*Data_Redis) ProtoMessage() {}
 
 func (x *Data_Redis) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[10]
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -761,30 +1201,34 @@ func (x *Data_Redis) GetMinIdleConns() int32 {
 	return 0
 }
 
-type Discovery_Consul struct {
+// Outbox 事务性发件箱投递任务
+type Data_Outbox struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Addr          string                 `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
-	Scheme        string                 `protobuf:"bytes,2,opt,name=scheme,proto3" json:"scheme,omitempty"`
-	HealthCheck   bool                   `protobuf:"varint,3,opt,name=health_check,json=healthCheck,proto3" json:"health_check,omitempty"`
+	Disabled      bool                   `protobuf:"varint,1,opt,name=disabled,proto3" json:"disabled,omitempty"`                             // 关闭后台投递，例如由单独的进程负责投递
+	PollInterval  int64                  `protobuf:"varint,2,opt,name=poll_interval,json=pollInterval,proto3" json:"poll_interval,omitempty"` // 轮询间隔（秒），默认 1
+	BatchSize     int32                  `protobuf:"varint,3,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`          // 每次最多投递的事件数，默认 100
+	MaxAttempts   int32                  `protobuf:"varint,4,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`    // 最大尝试次数，超过后进入死信状态，默认 10
+	BaseBackoff   int64                  `protobuf:"varint,5,opt,name=base_backoff,json=baseBackoff,proto3" json:"base_backoff,omitempty"`    // 首次重试的退避时间（秒），之后按指数增长，默认 1
+	MaxBackoff    int64                  `protobuf:"varint,6,opt,name=max_backoff,json=maxBackoff,proto3" json:"max_backoff,omitempty"`       // 退避时间上限（秒），默认 300
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Discovery_Consul) Reset() {
-	*x = Discovery_Consul{}
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[11]
+func (x *Data_Outbox) Reset() {
+	*x = Data_Outbox{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Discovery_Consul) String() string {
+func (x *Data_Outbox) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Discovery_Consul) ProtoMessage() {}
+func (*Data_Outbox) ProtoMessage() {}
 
-func (x *Discovery_Consul) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[11]
+func (x *Data_Outbox) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -795,56 +1239,76 @@ func (x *Discovery_Consul) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Discovery_Consul.ProtoReflect.Descriptor instead.
-func (*Discovery_Consul) Descriptor() ([]byte, []int) {
-	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{5, 0}
+// Deprecated: Use Data_Outbox.ProtoReflect.Descriptor instead.
+func (*Data_Outbox) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{2, 3}
 }
 
-func (x *Discovery_Consul) GetAddr() string {
+func (x *Data_Outbox) GetDisabled() bool {
 	if x != nil {
-		return x.Addr
+		return x.Disabled
 	}
-	return ""
+	return false
 }
 
-func (x *Discovery_Consul) GetScheme() string {
+func (x *Data_Outbox) GetPollInterval() int64 {
 	if x != nil {
-		return x.Scheme
+		return x.PollInterval
 	}
-	return ""
+	return 0
 }
 
-func (x *Discovery_Consul) GetHealthCheck() bool {
+func (x *Data_Outbox) GetBatchSize() int32 {
 	if x != nil {
-		return x.HealthCheck
+		return x.BatchSize
 	}
-	return false
+	return 0
 }
 
-type Search_ElasticSearch struct {
+func (x *Data_Outbox) GetMaxAttempts() int32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *Data_Outbox) GetBaseBackoff() int64 {
+	if x != nil {
+		return x.BaseBackoff
+	}
+	return 0
+}
+
+func (x *Data_Outbox) GetMaxBackoff() int64 {
+	if x != nil {
+		return x.MaxBackoff
+	}
+	return 0
+}
+
+type Auth_Session struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Addresses     []string               `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
-	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
-	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Ttl           int64                  `protobuf:"varint,1,opt,name=ttl,proto3" json:"ttl,omitempty"`                                 // 会话有效期（秒）
+	RefreshTtl    int64                  `protobuf:"varint,2,opt,name=refresh_ttl,json=refreshTtl,proto3" json:"refresh_ttl,omitempty"` // 刷新令牌有效期（秒）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Search_ElasticSearch) Reset() {
-	*x = Search_ElasticSearch{}
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[12]
+func (x *Auth_Session) Reset() {
+	*x = Auth_Session{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Search_ElasticSearch) String() string {
+func (x *Auth_Session) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Search_ElasticSearch) ProtoMessage() {}
+func (*Auth_Session) ProtoMessage() {}
 
-func (x *Search_ElasticSearch) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_conf_v1_conf_proto_msgTypes[12]
+func (x *Auth_Session) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -855,100 +1319,1085 @@ func (x *Search_ElasticSearch) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Search_ElasticSearch.ProtoReflect.Descriptor instead.
-func (*Search_ElasticSearch) Descriptor() ([]byte, []int) {
-	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{6, 0}
+// Deprecated: Use Auth_Session.ProtoReflect.Descriptor instead.
+func (*Auth_Session) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{3, 0}
 }
 
-func (x *Search_ElasticSearch) GetAddresses() []string {
+func (x *Auth_Session) GetTtl() int64 {
 	if x != nil {
-		return x.Addresses
+		return x.Ttl
 	}
-	return nil
+	return 0
 }
 
-func (x *Search_ElasticSearch) GetUsername() string {
+func (x *Auth_Session) GetRefreshTtl() int64 {
 	if x != nil {
-		return x.Username
+		return x.RefreshTtl
 	}
-	return ""
+	return 0
 }
 
-func (x *Search_ElasticSearch) GetPassword() string {
+// Lockout 本地账号连续登录失败后的锁定策略
+type Auth_Lockout struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Disabled      bool                   `protobuf:"varint,1,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	MaxFailures   int32                  `protobuf:"varint,2,opt,name=max_failures,json=maxFailures,proto3" json:"max_failures,omitempty"`    // 窗口内允许的失败次数，达到后锁定，默认 5
+	Window        int64                  `protobuf:"varint,3,opt,name=window,proto3" json:"window,omitempty"`                                 // 统计失败次数的窗口（秒），默认 900
+	BaseDuration  int64                  `protobuf:"varint,4,opt,name=base_duration,json=baseDuration,proto3" json:"base_duration,omitempty"` // 首次锁定时长（秒），之后每次锁定翻倍，默认 60
+	MaxDuration   int64                  `protobuf:"varint,5,opt,name=max_duration,json=maxDuration,proto3" json:"max_duration,omitempty"`    // 锁定时长上限（秒），默认 3600
+	ResetAfter    int64                  `protobuf:"varint,6,opt,name=reset_after,json=resetAfter,proto3" json:"reset_after,omitempty"`       // 超过该时间没有再次锁定时，锁定时长恢复为 base_duration（秒），默认 86400
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Auth_Lockout) Reset() {
+	*x = Auth_Lockout{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Auth_Lockout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Auth_Lockout) ProtoMessage() {}
+
+func (x *Auth_Lockout) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[18]
 	if x != nil {
-		return x.Password
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-var File_internal_conf_v1_conf_proto protoreflect.FileDescriptor
+// Deprecated: Use Auth_Lockout.ProtoReflect.Descriptor instead.
+func (*Auth_Lockout) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{3, 1}
+}
 
-const file_internal_conf_v1_conf_proto_rawDesc = "" +
-	"\n" +
-	"\x1binternal/conf/v1/conf.proto\x12\aconf.v1\"\xfb\x01\n" +
-	"\tBootstrap\x12'\n" +
-	"\x06server\x18\x01 \x01(\v2\x0f.conf.v1.ServerR\x06server\x12!\n" +
-	"\x04data\x18\x02 \x01(\v2\r.conf.v1.DataR\x04data\x12!\n" +
-	"\x04auth\x18\x03 \x01(\v2\r.conf.v1.AuthR\x04auth\x12$\n" +
-	"\x05trace\x18\x04 \x01(\v2\x0e.conf.v1.TraceR\x05trace\x120\n" +
-	"\tdiscovery\x18\x05 \x01(\v2\x12.conf.v1.DiscoveryR\tdiscovery\x12'\n" +
-	"\x06search\x18\x06 \x01(\v2\x0f.conf.v1.SearchR\x06search\"h\n" +
-	"\x06Server\x12(\n" +
-	"\x04http\x18\x01 \x01(\v2\x14.conf.v1.Server.HTTPR\x04http\x1a4\n" +
-	"\x04HTTP\x12\x12\n" +
-	"\x04addr\x18\x01 \x01(\tR\x04addr\x12\x18\n" +
-	"\atimeout\x18\x02 \x01(\x03R\atimeout\"\x96\x06\n" +
-	"\x04Data\x122\n" +
-	"\bdatabase\x18\x01 \x01(\v2\x16.conf.v1.Data.DatabaseR\bdatabase\x12)\n" +
-	"\x05redis\x18\x02 \x01(\v2\x13.conf.v1.Data.RedisR\x05redis\x1a\xe2\x01\n" +
-	"\bDatabase\x12\x12\n" +
-	"\x04host\x18\x01 \x01(\tR\x04host\x12\x12\n" +
-	"\x04port\x18\x02 \x01(\x05R\x04port\x12\x12\n" +
-	"\x04user\x18\x03 \x01(\tR\x04user\x12\x1a\n" +
-	"\bpassword\x18\x04 \x01(\tR\bpassword\x12\x17\n" +
-	"\adb_name\x18\x05 \x01(\tR\x06dbName\x12\x19\n" +
-	"\bssl_mode\x18\x06 \x01(\tR\asslMode\x12\x1a\n" +
-	"\btimezone\x18\a \x01(\tR\btimezone\x12.\n" +
-	"\x04pool\x18\b \x01(\v2\x1a.conf.v1.Data.DatabasePoolR\x04pool\x1a\xa1\x01\n" +
-	"\fDatabasePool\x12\x1b\n" +
-	"\tmax_conns\x18\x01 \x01(\x05R\bmaxConns\x12\x1b\n" +
-	"\tmin_conns\x18\x02 \x01(\x05R\bminConns\x12*\n" +
-	"\x11max_conn_lifetime\x18\x03 \x01(\x03R\x0fmaxConnLifetime\x12+\n" +
-	"\x12max_conn_idle_time\x18\x04 \x01(\x03R\x0fmaxConnIdleTime\x1a\xa5\x02\n" +
-	"\x05Redis\x12\x12\n" +
-	"\x04host\x18\x01 \x01(\tR\x04host\x12\x12\n" +
-	"\x04port\x18\x02 \x01(\x05R\x04port\x12\x1a\n" +
-	"\busername\x18\x03 \x01(\tR\busername\x12\x1a\n" +
-	"\bpassword\x18\x04 \x01(\tR\bpassword\x12\x0e\n" +
-	"\x02db\x18\x05 \x01(\x05R\x02db\x12!\n" +
-	"\fdial_timeout\x18\x06 \x01(\x03R\vdialTimeout\x12!\n" +
-	"\fread_timeout\x18\a \x01(\x03R\vreadTimeout\x12#\n" +
-	"\rwrite_timeout\x18\b \x01(\x03R\fwriteTimeout\x12\x1b\n" +
-	"\tpool_size\x18\t \x01(\x05R\bpoolSize\x12$\n" +
-	"\x0emin_idle_conns\x18\n" +
-	" \x01(\x05R\fminIdleConns\"\xde\x01\n" +
-	"\x04Auth\x12\x1a\n" +
-	"\bendpoint\x18\x01 \x01(\tR\bendpoint\x12\x1b\n" +
-	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12#\n" +
-	"\rclient_secret\x18\x03 \x01(\tR\fclientSecret\x12+\n" +
-	"\x11organization_name\x18\x04 \x01(\tR\x10organizationName\x12)\n" +
-	"\x10application_name\x18\x05 \x01(\tR\x0fapplicationName\x12 \n" +
-	"\vcertificate\x18\x06 \x01(\tR\vcertificate\"?\n" +
-	"\x05Trace\x12\x1a\n" +
-	"\bendpoint\x18\x01 \x01(\tR\bendpoint\x12\x1a\n" +
-	"\binsecure\x18\x02 \x01(\bR\binsecure\"\x97\x01\n" +
-	"\tDiscovery\x121\n" +
-	"\x06consul\x18\x01 \x01(\v2\x19.conf.v1.Discovery.ConsulR\x06consul\x1aW\n" +
-	"\x06Consul\x12\x12\n" +
-	"\x04addr\x18\x01 \x01(\tR\x04addr\x12\x16\n" +
-	"\x06scheme\x18\x02 \x01(\tR\x06scheme\x12!\n" +
-	"\fhealth_check\x18\x03 \x01(\bR\vhealthCheck\"\xb5\x01\n" +
-	"\x06Search\x12D\n" +
-	"\x0eelastic_search\x18\x01 \x01(\v2\x1d.conf.v1.Search.ElasticSearchR\relasticSearch\x1ae\n" +
-	"\rElasticSearch\x12\x1c\n" +
-	"\taddresses\x18\x01 \x03(\tR\taddresses\x12\x1a\n" +
-	"\busername\x18\x02 \x01(\tR\busername\x12\x1a\n" +
-	"\bpassword\x18\x03 \x01(\tR\bpasswordB|\n" +
+func (x *Auth_Lockout) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *Auth_Lockout) GetMaxFailures() int32 {
+	if x != nil {
+		return x.MaxFailures
+	}
+	return 0
+}
+
+func (x *Auth_Lockout) GetWindow() int64 {
+	if x != nil {
+		return x.Window
+	}
+	return 0
+}
+
+func (x *Auth_Lockout) GetBaseDuration() int64 {
+	if x != nil {
+		return x.BaseDuration
+	}
+	return 0
+}
+
+func (x *Auth_Lockout) GetMaxDuration() int64 {
+	if x != nil {
+		return x.MaxDuration
+	}
+	return 0
+}
+
+func (x *Auth_Lockout) GetResetAfter() int64 {
+	if x != nil {
+		return x.ResetAfter
+	}
+	return 0
+}
+
+// Mfa 本地账号的 TOTP 多因素认证
+type Auth_Mfa struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EncryptionKey string                 `protobuf:"bytes,1,opt,name=encryption_key,json=encryptionKey,proto3" json:"encryption_key,omitempty"`  // 加密 TOTP 密钥的 AES-256 密钥，base64 编码的 32 字节；为空时无法绑定 MFA
+	Issuer        string                 `protobuf:"bytes,2,opt,name=issuer,proto3" json:"issuer,omitempty"`                                     // otpauth URI 中的 issuer，显示在验证器应用中，默认 connect-go-example
+	ChallengeTtl  int64                  `protobuf:"varint,3,opt,name=challenge_ttl,json=challengeTtl,proto3" json:"challenge_ttl,omitempty"`    // 登录第二步挑战令牌的有效期（秒），默认 300
+	RecoveryCodes int32                  `protobuf:"varint,4,opt,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"` // 每次生成的恢复码数量，默认 10
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Auth_Mfa) Reset() {
+	*x = Auth_Mfa{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Auth_Mfa) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Auth_Mfa) ProtoMessage() {}
+
+func (x *Auth_Mfa) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Auth_Mfa.ProtoReflect.Descriptor instead.
+func (*Auth_Mfa) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{3, 2}
+}
+
+func (x *Auth_Mfa) GetEncryptionKey() string {
+	if x != nil {
+		return x.EncryptionKey
+	}
+	return ""
+}
+
+func (x *Auth_Mfa) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *Auth_Mfa) GetChallengeTtl() int64 {
+	if x != nil {
+		return x.ChallengeTtl
+	}
+	return 0
+}
+
+func (x *Auth_Mfa) GetRecoveryCodes() int32 {
+	if x != nil {
+		return x.RecoveryCodes
+	}
+	return 0
+}
+
+// EmailTokens 重置密码和验证邮箱的一次性令牌
+type Auth_EmailTokens struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	LinkBaseUrl      string                 `protobuf:"bytes,1,opt,name=link_base_url,json=linkBaseUrl,proto3" json:"link_base_url,omitempty"`                 // 邮件中链接的前缀，令牌作为 token 查询参数追加在后面；为空时邮件中只包含令牌
+	PasswordResetTtl int64                  `protobuf:"varint,2,opt,name=password_reset_ttl,json=passwordResetTtl,proto3" json:"password_reset_ttl,omitempty"` // 重置密码令牌的有效期（秒），默认 3600
+	VerificationTtl  int64                  `protobuf:"varint,3,opt,name=verification_ttl,json=verificationTtl,proto3" json:"verification_ttl,omitempty"`      // 验证邮箱令牌的有效期（秒），默认 86400
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Auth_EmailTokens) Reset() {
+	*x = Auth_EmailTokens{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Auth_EmailTokens) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Auth_EmailTokens) ProtoMessage() {}
+
+func (x *Auth_EmailTokens) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Auth_EmailTokens.ProtoReflect.Descriptor instead.
+func (*Auth_EmailTokens) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{3, 3}
+}
+
+func (x *Auth_EmailTokens) GetLinkBaseUrl() string {
+	if x != nil {
+		return x.LinkBaseUrl
+	}
+	return ""
+}
+
+func (x *Auth_EmailTokens) GetPasswordResetTtl() int64 {
+	if x != nil {
+		return x.PasswordResetTtl
+	}
+	return 0
+}
+
+func (x *Auth_EmailTokens) GetVerificationTtl() int64 {
+	if x != nil {
+		return x.VerificationTtl
+	}
+	return 0
+}
+
+type Authorization_Policy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Procedure     string                 `protobuf:"bytes,1,opt,name=procedure,proto3" json:"procedure,omitempty"`     // 完整的 procedure，例如 /user.v1.UserService/SignOut；以 /* 结尾表示整个服务
+	Roles         []string               `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`             // 具备其中任一角色即可
+	Permissions   []string               `protobuf:"bytes,3,rep,name=permissions,proto3" json:"permissions,omitempty"` // 必须具备全部权限
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Authorization_Policy) Reset() {
+	*x = Authorization_Policy{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Authorization_Policy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Authorization_Policy) ProtoMessage() {}
+
+func (x *Authorization_Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Authorization_Policy.ProtoReflect.Descriptor instead.
+func (*Authorization_Policy) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{4, 0}
+}
+
+func (x *Authorization_Policy) GetProcedure() string {
+	if x != nil {
+		return x.Procedure
+	}
+	return ""
+}
+
+func (x *Authorization_Policy) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *Authorization_Policy) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+type RateLimit_Rule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Procedure     string                 `protobuf:"bytes,1,opt,name=procedure,proto3" json:"procedure,omitempty"` // 完整的 procedure，例如 /user.v1.UserService/SignIn；以 /* 结尾表示整个服务
+	Limit         int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`        // 每个周期允许的请求数，为 0 时关闭该 procedure 的限流（包括默认规则）
+	Period        int64                  `protobuf:"varint,3,opt,name=period,proto3" json:"period,omitempty"`      // 周期（秒），默认 1
+	Burst         int64                  `protobuf:"varint,4,opt,name=burst,proto3" json:"burst,omitempty"`        // 允许的突发请求数，默认等于 limit
+	Key           string                 `protobuf:"bytes,5,opt,name=key,proto3" json:"key,omitempty"`             // 限流维度：ip（默认）、user、api_key；user 和 api_key 缺失时按 ip 限流
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateLimit_Rule) Reset() {
+	*x = RateLimit_Rule{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimit_Rule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimit_Rule) ProtoMessage() {}
+
+func (x *RateLimit_Rule) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimit_Rule.ProtoReflect.Descriptor instead.
+func (*RateLimit_Rule) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{5, 0}
+}
+
+func (x *RateLimit_Rule) GetProcedure() string {
+	if x != nil {
+		return x.Procedure
+	}
+	return ""
+}
+
+func (x *RateLimit_Rule) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *RateLimit_Rule) GetPeriod() int64 {
+	if x != nil {
+		return x.Period
+	}
+	return 0
+}
+
+func (x *RateLimit_Rule) GetBurst() int64 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+func (x *RateLimit_Rule) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type Mail_SMTP struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Host          string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Port          int32                  `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`        // 默认 587，服务器支持时使用 STARTTLS
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"` // 为空时不认证
+	Password      string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Mail_SMTP) Reset() {
+	*x = Mail_SMTP{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Mail_SMTP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Mail_SMTP) ProtoMessage() {}
+
+func (x *Mail_SMTP) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Mail_SMTP.ProtoReflect.Descriptor instead.
+func (*Mail_SMTP) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{6, 0}
+}
+
+func (x *Mail_SMTP) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *Mail_SMTP) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *Mail_SMTP) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Mail_SMTP) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type Discovery_Consul struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Addr   string                 `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
+	Scheme string                 `protobuf:"bytes,2,opt,name=scheme,proto3" json:"scheme,omitempty"`
+	// 已废弃：未配置 check.mode 时为 true 等价于 http 模式
+	HealthCheck bool `protobuf:"varint,3,opt,name=health_check,json=healthCheck,proto3" json:"health_check,omitempty"`
+	// 失败时只向 Consul 上报 warning 的依赖，其余依赖失败上报 critical；默认 elasticsearch 和 casdoor
+	DegradedChecks []string         `protobuf:"bytes,4,rep,name=degraded_checks,json=degradedChecks,proto3" json:"degraded_checks,omitempty"`
+	Check          *Discovery_Check `protobuf:"bytes,5,opt,name=check,proto3" json:"check,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Discovery_Consul) Reset() {
+	*x = Discovery_Consul{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Discovery_Consul) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Discovery_Consul) ProtoMessage() {}
+
+func (x *Discovery_Consul) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Discovery_Consul.ProtoReflect.Descriptor instead.
+func (*Discovery_Consul) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{8, 0}
+}
+
+func (x *Discovery_Consul) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+func (x *Discovery_Consul) GetScheme() string {
+	if x != nil {
+		return x.Scheme
+	}
+	return ""
+}
+
+func (x *Discovery_Consul) GetHealthCheck() bool {
+	if x != nil {
+		return x.HealthCheck
+	}
+	return false
+}
+
+func (x *Discovery_Consul) GetDegradedChecks() []string {
+	if x != nil {
+		return x.DegradedChecks
+	}
+	return nil
+}
+
+func (x *Discovery_Consul) GetCheck() *Discovery_Check {
+	if x != nil {
+		return x.Check
+	}
+	return nil
+}
+
+type Discovery_Check struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Mode                    string                 `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`                                                                         // ttl（默认，由本服务上报心跳）、http（Consul 请求 http_path）、grpc（Consul 调用 grpc.health.v1）
+	Interval                int64                  `protobuf:"varint,2,opt,name=interval,proto3" json:"interval,omitempty"`                                                                // 检查间隔（秒），默认 10；ttl 模式下 TTL 为间隔的 3 倍
+	Timeout                 int64                  `protobuf:"varint,3,opt,name=timeout,proto3" json:"timeout,omitempty"`                                                                  // http/grpc 检查超时（秒），默认 5
+	DeregisterCriticalAfter int64                  `protobuf:"varint,4,opt,name=deregister_critical_after,json=deregisterCriticalAfter,proto3" json:"deregister_critical_after,omitempty"` // 持续 critical 多久后自动注销（秒），默认 60
+	HttpPath                string                 `protobuf:"bytes,5,opt,name=http_path,json=httpPath,proto3" json:"http_path,omitempty"`                                                 // http 模式的检查路径，默认 /readyz
+	GrpcService             string                 `protobuf:"bytes,6,opt,name=grpc_service,json=grpcService,proto3" json:"grpc_service,omitempty"`                                        // grpc 模式检查的服务名，默认为空即整体状态
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *Discovery_Check) Reset() {
+	*x = Discovery_Check{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Discovery_Check) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Discovery_Check) ProtoMessage() {}
+
+func (x *Discovery_Check) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Discovery_Check.ProtoReflect.Descriptor instead.
+func (*Discovery_Check) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{8, 1}
+}
+
+func (x *Discovery_Check) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *Discovery_Check) GetInterval() int64 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+func (x *Discovery_Check) GetTimeout() int64 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *Discovery_Check) GetDeregisterCriticalAfter() int64 {
+	if x != nil {
+		return x.DeregisterCriticalAfter
+	}
+	return 0
+}
+
+func (x *Discovery_Check) GetHttpPath() string {
+	if x != nil {
+		return x.HttpPath
+	}
+	return ""
+}
+
+func (x *Discovery_Check) GetGrpcService() string {
+	if x != nil {
+		return x.GrpcService
+	}
+	return ""
+}
+
+// Service 一个服务的固定实例列表
+type Discovery_Service struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Addrs         []string               `protobuf:"bytes,3,rep,name=addrs,proto3" json:"addrs,omitempty"` // host:port
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Discovery_Service) Reset() {
+	*x = Discovery_Service{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Discovery_Service) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Discovery_Service) ProtoMessage() {}
+
+func (x *Discovery_Service) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Discovery_Service.ProtoReflect.Descriptor instead.
+func (*Discovery_Service) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{8, 2}
+}
+
+func (x *Discovery_Service) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Discovery_Service) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Discovery_Service) GetAddrs() []string {
+	if x != nil {
+		return x.Addrs
+	}
+	return nil
+}
+
+type Discovery_Static struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Services      []*Discovery_Service   `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Discovery_Static) Reset() {
+	*x = Discovery_Static{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Discovery_Static) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Discovery_Static) ProtoMessage() {}
+
+func (x *Discovery_Static) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Discovery_Static.ProtoReflect.Descriptor instead.
+func (*Discovery_Static) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{8, 3}
+}
+
+func (x *Discovery_Static) GetServices() []*Discovery_Service {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+// File 从 YAML/JSON 文件读取实例列表，格式与 Static 相同，文件变化后自动重新加载
+type Discovery_File struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	PollInterval  int64                  `protobuf:"varint,2,opt,name=poll_interval,json=pollInterval,proto3" json:"poll_interval,omitempty"` // 检查文件变化的间隔（秒），默认 5
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Discovery_File) Reset() {
+	*x = Discovery_File{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Discovery_File) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Discovery_File) ProtoMessage() {}
+
+func (x *Discovery_File) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Discovery_File.ProtoReflect.Descriptor instead.
+func (*Discovery_File) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{8, 4}
+}
+
+func (x *Discovery_File) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Discovery_File) GetPollInterval() int64 {
+	if x != nil {
+		return x.PollInterval
+	}
+	return 0
+}
+
+// Kubernetes 轮询 EndpointSlice 发现 Service name-version 的就绪实例
+// 当前实例由 kubelet 根据 /readyz 探针加入或移出 Endpoints，不需要主动注册
+type Discovery_Kubernetes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`                            // 默认为 service account 所在的命名空间
+	PortName      string                 `protobuf:"bytes,2,opt,name=port_name,json=portName,proto3" json:"port_name,omitempty"`              // 使用的端口名，默认使用第一个端口
+	PollInterval  int64                  `protobuf:"varint,3,opt,name=poll_interval,json=pollInterval,proto3" json:"poll_interval,omitempty"` // 轮询间隔（秒），默认 5
+	ApiServer     string                 `protobuf:"bytes,4,opt,name=api_server,json=apiServer,proto3" json:"api_server,omitempty"`           // API Server 地址，默认使用集群内的 KUBERNETES_SERVICE_HOST 和 KUBERNETES_SERVICE_PORT
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Discovery_Kubernetes) Reset() {
+	*x = Discovery_Kubernetes{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Discovery_Kubernetes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Discovery_Kubernetes) ProtoMessage() {}
+
+func (x *Discovery_Kubernetes) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Discovery_Kubernetes.ProtoReflect.Descriptor instead.
+func (*Discovery_Kubernetes) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{8, 5}
+}
+
+func (x *Discovery_Kubernetes) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *Discovery_Kubernetes) GetPortName() string {
+	if x != nil {
+		return x.PortName
+	}
+	return ""
+}
+
+func (x *Discovery_Kubernetes) GetPollInterval() int64 {
+	if x != nil {
+		return x.PollInterval
+	}
+	return 0
+}
+
+func (x *Discovery_Kubernetes) GetApiServer() string {
+	if x != nil {
+		return x.ApiServer
+	}
+	return ""
+}
+
+type Search_ElasticSearch struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Addresses []string               `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Username  string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Password  string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	// 用户索引名称，默认 users
+	UserIndex     string `protobuf:"bytes,4,opt,name=user_index,json=userIndex,proto3" json:"user_index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Search_ElasticSearch) Reset() {
+	*x = Search_ElasticSearch{}
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Search_ElasticSearch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Search_ElasticSearch) ProtoMessage() {}
+
+func (x *Search_ElasticSearch) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_conf_v1_conf_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Search_ElasticSearch.ProtoReflect.Descriptor instead.
+func (*Search_ElasticSearch) Descriptor() ([]byte, []int) {
+	return file_internal_conf_v1_conf_proto_rawDescGZIP(), []int{9, 0}
+}
+
+func (x *Search_ElasticSearch) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *Search_ElasticSearch) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Search_ElasticSearch) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *Search_ElasticSearch) GetUserIndex() string {
+	if x != nil {
+		return x.UserIndex
+	}
+	return ""
+}
+
+var File_internal_conf_v1_conf_proto protoreflect.FileDescriptor
+
+const file_internal_conf_v1_conf_proto_rawDesc = "" +
+	"\n" +
+	"\x1binternal/conf/v1/conf.proto\x12\aconf.v1\"\x8f\x03\n" +
+	"\tBootstrap\x12'\n" +
+	"\x06server\x18\x01 \x01(\v2\x0f.conf.v1.ServerR\x06server\x12!\n" +
+	"\x04data\x18\x02 \x01(\v2\r.conf.v1.DataR\x04data\x12!\n" +
+	"\x04auth\x18\x03 \x01(\v2\r.conf.v1.AuthR\x04auth\x12$\n" +
+	"\x05trace\x18\x04 \x01(\v2\x0e.conf.v1.TraceR\x05trace\x120\n" +
+	"\tdiscovery\x18\x05 \x01(\v2\x12.conf.v1.DiscoveryR\tdiscovery\x12'\n" +
+	"\x06search\x18\x06 \x01(\v2\x0f.conf.v1.SearchR\x06search\x12<\n" +
+	"\rauthorization\x18\a \x01(\v2\x16.conf.v1.AuthorizationR\rauthorization\x121\n" +
+	"\n" +
+	"rate_limit\x18\b \x01(\v2\x12.conf.v1.RateLimitR\trateLimit\x12!\n" +
+	"\x04mail\x18\t \x01(\v2\r.conf.v1.MailR\x04mail\"\xec\x02\n" +
+	"\x06Server\x12(\n" +
+	"\x04http\x18\x01 \x01(\v2\x14.conf.v1.Server.HTTPR\x04http\x12.\n" +
+	"\x06health\x18\x02 \x01(\v2\x16.conf.v1.Server.HealthR\x06health\x124\n" +
+	"\bshutdown\x18\x03 \x01(\v2\x18.conf.v1.Server.ShutdownR\bshutdown\x1a4\n" +
+	"\x04HTTP\x12\x12\n" +
+	"\x04addr\x18\x01 \x01(\tR\x04addr\x12\x18\n" +
+	"\atimeout\x18\x02 \x01(\x03R\atimeout\x1a>\n" +
+	"\x06Health\x12\x1a\n" +
+	"\binterval\x18\x01 \x01(\x03R\binterval\x12\x18\n" +
+	"\atimeout\x18\x02 \x01(\x03R\atimeout\x1a\\\n" +
+	"\bShutdown\x12+\n" +
+	"\x11propagation_delay\x18\x01 \x01(\x03R\x10propagationDelay\x12#\n" +
+	"\rdrain_timeout\x18\x02 \x01(\x03R\fdrainTimeout\"\xcf\t\n" +
+	"\x04Data\x122\n" +
+	"\bdatabase\x18\x01 \x01(\v2\x16.conf.v1.Data.DatabaseR\bdatabase\x12)\n" +
+	"\x05redis\x18\x02 \x01(\v2\x13.conf.v1.Data.RedisR\x05redis\x12,\n" +
+	"\x06outbox\x18\x03 \x01(\v2\x14.conf.v1.Data.OutboxR\x06outbox\x1a\x85\x02\n" +
+	"\bDatabase\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x12\n" +
+	"\x04port\x18\x02 \x01(\x05R\x04port\x12\x12\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\x12\x1a\n" +
+	"\bpassword\x18\x04 \x01(\tR\bpassword\x12\x17\n" +
+	"\adb_name\x18\x05 \x01(\tR\x06dbName\x12\x19\n" +
+	"\bssl_mode\x18\x06 \x01(\tR\asslMode\x12\x1a\n" +
+	"\btimezone\x18\a \x01(\tR\btimezone\x12.\n" +
+	"\x04pool\x18\b \x01(\v2\x1a.conf.v1.Data.DatabasePoolR\x04pool\x12!\n" +
+	"\fauto_migrate\x18\t \x01(\bR\vautoMigrate\x1a\xb7\x02\n" +
+	"\fDatabasePool\x12\x1b\n" +
+	"\tmax_conns\x18\x01 \x01(\x05R\bmaxConns\x12\x1b\n" +
+	"\tmin_conns\x18\x02 \x01(\x05R\bminConns\x12*\n" +
+	"\x11max_conn_lifetime\x18\x03 \x01(\x03R\x0fmaxConnLifetime\x12+\n" +
+	"\x12max_conn_idle_time\x18\x04 \x01(\x03R\x0fmaxConnIdleTime\x12.\n" +
+	"\x13health_check_period\x18\x05 \x01(\x03R\x11healthCheckPeriod\x12+\n" +
+	"\x11statement_timeout\x18\x06 \x01(\x03R\x10statementTimeout\x127\n" +
+	"\x18max_conn_lifetime_jitter\x18\a \x01(\x03R\x15maxConnLifetimeJitter\x1a\xa5\x02\n" +
+	"\x05Redis\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x12\n" +
+	"\x04port\x18\x02 \x01(\x05R\x04port\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x04 \x01(\tR\bpassword\x12\x0e\n" +
+	"\x02db\x18\x05 \x01(\x05R\x02db\x12!\n" +
+	"\fdial_timeout\x18\x06 \x01(\x03R\vdialTimeout\x12!\n" +
+	"\fread_timeout\x18\a \x01(\x03R\vreadTimeout\x12#\n" +
+	"\rwrite_timeout\x18\b \x01(\x03R\fwriteTimeout\x12\x1b\n" +
+	"\tpool_size\x18\t \x01(\x05R\bpoolSize\x12$\n" +
+	"\x0emin_idle_conns\x18\n" +
+	" \x01(\x05R\fminIdleConns\x1a\xcf\x01\n" +
+	"\x06Outbox\x12\x1a\n" +
+	"\bdisabled\x18\x01 \x01(\bR\bdisabled\x12#\n" +
+	"\rpoll_interval\x18\x02 \x01(\x03R\fpollInterval\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x03 \x01(\x05R\tbatchSize\x12!\n" +
+	"\fmax_attempts\x18\x04 \x01(\x05R\vmaxAttempts\x12!\n" +
+	"\fbase_backoff\x18\x05 \x01(\x03R\vbaseBackoff\x12\x1f\n" +
+	"\vmax_backoff\x18\x06 \x01(\x03R\n" +
+	"maxBackoff\"\xfa\a\n" +
+	"\x04Auth\x12\x1a\n" +
+	"\bendpoint\x18\x01 \x01(\tR\bendpoint\x12\x1b\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12#\n" +
+	"\rclient_secret\x18\x03 \x01(\tR\fclientSecret\x12+\n" +
+	"\x11organization_name\x18\x04 \x01(\tR\x10organizationName\x12)\n" +
+	"\x10application_name\x18\x05 \x01(\tR\x0fapplicationName\x12 \n" +
+	"\vcertificate\x18\x06 \x01(\tR\vcertificate\x12/\n" +
+	"\asession\x18\a \x01(\v2\x15.conf.v1.Auth.SessionR\asession\x12+\n" +
+	"\x11public_procedures\x18\b \x03(\tR\x10publicProcedures\x12/\n" +
+	"\alockout\x18\t \x01(\v2\x15.conf.v1.Auth.LockoutR\alockout\x12#\n" +
+	"\x03mfa\x18\n" +
+	" \x01(\v2\x11.conf.v1.Auth.MfaR\x03mfa\x12<\n" +
+	"\femail_tokens\x18\v \x01(\v2\x19.conf.v1.Auth.EmailTokensR\vemailTokens\x1a<\n" +
+	"\aSession\x12\x10\n" +
+	"\x03ttl\x18\x01 \x01(\x03R\x03ttl\x12\x1f\n" +
+	"\vrefresh_ttl\x18\x02 \x01(\x03R\n" +
+	"refreshTtl\x1a\xc9\x01\n" +
+	"\aLockout\x12\x1a\n" +
+	"\bdisabled\x18\x01 \x01(\bR\bdisabled\x12!\n" +
+	"\fmax_failures\x18\x02 \x01(\x05R\vmaxFailures\x12\x16\n" +
+	"\x06window\x18\x03 \x01(\x03R\x06window\x12#\n" +
+	"\rbase_duration\x18\x04 \x01(\x03R\fbaseDuration\x12!\n" +
+	"\fmax_duration\x18\x05 \x01(\x03R\vmaxDuration\x12\x1f\n" +
+	"\vreset_after\x18\x06 \x01(\x03R\n" +
+	"resetAfter\x1a\x90\x01\n" +
+	"\x03Mfa\x12%\n" +
+	"\x0eencryption_key\x18\x01 \x01(\tR\rencryptionKey\x12\x16\n" +
+	"\x06issuer\x18\x02 \x01(\tR\x06issuer\x12#\n" +
+	"\rchallenge_ttl\x18\x03 \x01(\x03R\fchallengeTtl\x12%\n" +
+	"\x0erecovery_codes\x18\x04 \x01(\x05R\rrecoveryCodes\x1a\x8a\x01\n" +
+	"\vEmailTokens\x12\"\n" +
+	"\rlink_base_url\x18\x01 \x01(\tR\vlinkBaseUrl\x12,\n" +
+	"\x12password_reset_ttl\x18\x02 \x01(\x03R\x10passwordResetTtl\x12)\n" +
+	"\x10verification_ttl\x18\x03 \x01(\x03R\x0fverificationTtl\"\xec\x01\n" +
+	"\rAuthorization\x129\n" +
+	"\bpolicies\x18\x01 \x03(\v2\x1d.conf.v1.Authorization.PolicyR\bpolicies\x12!\n" +
+	"\fdefault_deny\x18\x02 \x01(\bR\vdefaultDeny\x12\x1d\n" +
+	"\n" +
+	"consul_key\x18\x03 \x01(\tR\tconsulKey\x1a^\n" +
+	"\x06Policy\x12\x1c\n" +
+	"\tprocedure\x18\x01 \x01(\tR\tprocedure\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\x12 \n" +
+	"\vpermissions\x18\x03 \x03(\tR\vpermissions\"\x8c\x02\n" +
+	"\tRateLimit\x12-\n" +
+	"\x05rules\x18\x01 \x03(\v2\x17.conf.v1.RateLimit.RuleR\x05rules\x12$\n" +
+	"\x0eapi_key_header\x18\x02 \x01(\tR\fapiKeyHeader\x12.\n" +
+	"\x13trust_forwarded_for\x18\x03 \x01(\bR\x11trustForwardedFor\x1az\n" +
+	"\x04Rule\x12\x1c\n" +
+	"\tprocedure\x18\x01 \x01(\tR\tprocedure\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limit\x12\x16\n" +
+	"\x06period\x18\x03 \x01(\x03R\x06period\x12\x14\n" +
+	"\x05burst\x18\x04 \x01(\x03R\x05burst\x12\x10\n" +
+	"\x03key\x18\x05 \x01(\tR\x03key\"\xc2\x01\n" +
+	"\x04Mail\x12\x16\n" +
+	"\x06driver\x18\x01 \x01(\tR\x06driver\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12&\n" +
+	"\x04smtp\x18\x03 \x01(\v2\x12.conf.v1.Mail.SMTPR\x04smtp\x1af\n" +
+	"\x04SMTP\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x12\n" +
+	"\x04port\x18\x02 \x01(\x05R\x04port\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x04 \x01(\tR\bpassword\"?\n" +
+	"\x05Trace\x12\x1a\n" +
+	"\bendpoint\x18\x01 \x01(\tR\bendpoint\x12\x1a\n" +
+	"\binsecure\x18\x02 \x01(\bR\binsecure\"\xda\a\n" +
+	"\tDiscovery\x121\n" +
+	"\x06consul\x18\x01 \x01(\v2\x19.conf.v1.Discovery.ConsulR\x06consul\x12\x18\n" +
+	"\abackend\x18\x02 \x01(\tR\abackend\x121\n" +
+	"\x06static\x18\x03 \x01(\v2\x19.conf.v1.Discovery.StaticR\x06static\x12+\n" +
+	"\x04file\x18\x04 \x01(\v2\x17.conf.v1.Discovery.FileR\x04file\x12=\n" +
+	"\n" +
+	"kubernetes\x18\x05 \x01(\v2\x1d.conf.v1.Discovery.KubernetesR\n" +
+	"kubernetes\x1a\xb0\x01\n" +
+	"\x06Consul\x12\x12\n" +
+	"\x04addr\x18\x01 \x01(\tR\x04addr\x12\x16\n" +
+	"\x06scheme\x18\x02 \x01(\tR\x06scheme\x12!\n" +
+	"\fhealth_check\x18\x03 \x01(\bR\vhealthCheck\x12'\n" +
+	"\x0fdegraded_checks\x18\x04 \x03(\tR\x0edegradedChecks\x12.\n" +
+	"\x05check\x18\x05 \x01(\v2\x18.conf.v1.Discovery.CheckR\x05check\x1a\xcd\x01\n" +
+	"\x05Check\x12\x12\n" +
+	"\x04mode\x18\x01 \x01(\tR\x04mode\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\x03R\binterval\x12\x18\n" +
+	"\atimeout\x18\x03 \x01(\x03R\atimeout\x12:\n" +
+	"\x19deregister_critical_after\x18\x04 \x01(\x03R\x17deregisterCriticalAfter\x12\x1b\n" +
+	"\thttp_path\x18\x05 \x01(\tR\bhttpPath\x12!\n" +
+	"\fgrpc_service\x18\x06 \x01(\tR\vgrpcService\x1aM\n" +
+	"\aService\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x14\n" +
+	"\x05addrs\x18\x03 \x03(\tR\x05addrs\x1a@\n" +
+	"\x06Static\x126\n" +
+	"\bservices\x18\x01 \x03(\v2\x1a.conf.v1.Discovery.ServiceR\bservices\x1a?\n" +
+	"\x04File\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12#\n" +
+	"\rpoll_interval\x18\x02 \x01(\x03R\fpollInterval\x1a\x8b\x01\n" +
+	"\n" +
+	"Kubernetes\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x1b\n" +
+	"\tport_name\x18\x02 \x01(\tR\bportName\x12#\n" +
+	"\rpoll_interval\x18\x03 \x01(\x03R\fpollInterval\x12\x1d\n" +
+	"\n" +
+	"api_server\x18\x04 \x01(\tR\tapiServer\"\xd5\x01\n" +
+	"\x06Search\x12D\n" +
+	"\x0eelastic_search\x18\x01 \x01(\v2\x1d.conf.v1.Search.ElasticSearchR\relasticSearch\x1a\x84\x01\n" +
+	"\rElasticSearch\x12\x1c\n" +
+	"\taddresses\x18\x01 \x03(\tR\taddresses\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12\x1d\n" +
+	"\n" +
+	"user_index\x18\x04 \x01(\tR\tuserIndexB|\n" +
 	"\vcom.conf.v1B\tConfProtoP\x01Z%connect-go-example/gen/conf/v1;confv1\xa2\x02\x03CXX\xaa\x02\aConf.V1\xca\x02\aConf\\V1\xe2\x02\x13Conf\\V1\\GPBMetadata\xea\x02\bConf::V1b\x06proto3"
 
 var (
@@ -963,40 +2412,76 @@ func file_internal_conf_v1_conf_proto_rawDescGZIP() []byte {
 	return file_internal_conf_v1_conf_proto_rawDescData
 }
 
-var file_internal_conf_v1_conf_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_internal_conf_v1_conf_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_internal_conf_v1_conf_proto_goTypes = []any{
 	(*Bootstrap)(nil),            // 0: conf.v1.Bootstrap
 	(*Server)(nil),               // 1: conf.v1.Server
 	(*Data)(nil),                 // 2: conf.v1.Data
 	(*Auth)(nil),                 // 3: conf.v1.Auth
-	(*Trace)(nil),                // 4: conf.v1.Trace
-	(*Discovery)(nil),            // 5: conf.v1.Discovery
-	(*Search)(nil),               // 6: conf.v1.Search
-	(*Server_HTTP)(nil),          // 7: conf.v1.Server.HTTP
-	(*Data_Database)(nil),        // 8: conf.v1.Data.Database
-	(*Data_DatabasePool)(nil),    // 9: conf.v1.Data.DatabasePool
-	(*Data_Redis)(nil),           // 10: conf.v1.Data.Redis
-	(*Discovery_Consul)(nil),     // 11: conf.v1.Discovery.Consul
-	(*Search_ElasticSearch)(nil), // 12: conf.v1.Search.ElasticSearch
+	(*Authorization)(nil),        // 4: conf.v1.Authorization
+	(*RateLimit)(nil),            // 5: conf.v1.RateLimit
+	(*Mail)(nil),                 // 6: conf.v1.Mail
+	(*Trace)(nil),                // 7: conf.v1.Trace
+	(*Discovery)(nil),            // 8: conf.v1.Discovery
+	(*Search)(nil),               // 9: conf.v1.Search
+	(*Server_HTTP)(nil),          // 10: conf.v1.Server.HTTP
+	(*Server_Health)(nil),        // 11: conf.v1.Server.Health
+	(*Server_Shutdown)(nil),      // 12: conf.v1.Server.Shutdown
+	(*Data_Database)(nil),        // 13: conf.v1.Data.Database
+	(*Data_DatabasePool)(nil),    // 14: conf.v1.Data.DatabasePool
+	(*Data_Redis)(nil),           // 15: conf.v1.Data.Redis
+	(*Data_Outbox)(nil),          // 16: conf.v1.Data.Outbox
+	(*Auth_Session)(nil),         // 17: conf.v1.Auth.Session
+	(*Auth_Lockout)(nil),         // 18: conf.v1.Auth.Lockout
+	(*Auth_Mfa)(nil),             // 19: conf.v1.Auth.Mfa
+	(*Auth_EmailTokens)(nil),     // 20: conf.v1.Auth.EmailTokens
+	(*Authorization_Policy)(nil), // 21: conf.v1.Authorization.Policy
+	(*RateLimit_Rule)(nil),       // 22: conf.v1.RateLimit.Rule
+	(*Mail_SMTP)(nil),            // 23: conf.v1.Mail.SMTP
+	(*Discovery_Consul)(nil),     // 24: conf.v1.Discovery.Consul
+	(*Discovery_Check)(nil),      // 25: conf.v1.Discovery.Check
+	(*Discovery_Service)(nil),    // 26: conf.v1.Discovery.Service
+	(*Discovery_Static)(nil),     // 27: conf.v1.Discovery.Static
+	(*Discovery_File)(nil),       // 28: conf.v1.Discovery.File
+	(*Discovery_Kubernetes)(nil), // 29: conf.v1.Discovery.Kubernetes
+	(*Search_ElasticSearch)(nil), // 30: conf.v1.Search.ElasticSearch
 }
 var file_internal_conf_v1_conf_proto_depIdxs = []int32{
 	1,  // 0: conf.v1.Bootstrap.server:type_name -> conf.v1.Server
 	2,  // 1: conf.v1.Bootstrap.data:type_name -> conf.v1.Data
 	3,  // 2: conf.v1.Bootstrap.auth:type_name -> conf.v1.Auth
-	4,  // 3: conf.v1.Bootstrap.trace:type_name -> conf.v1.Trace
-	5,  // 4: conf.v1.Bootstrap.discovery:type_name -> conf.v1.Discovery
-	6,  // 5: conf.v1.Bootstrap.search:type_name -> conf.v1.Search
-	7,  // 6: conf.v1.Server.http:type_name -> conf.v1.Server.HTTP
-	8,  // 7: conf.v1.Data.database:type_name -> conf.v1.Data.Database
-	10, // 8: conf.v1.Data.redis:type_name -> conf.v1.Data.Redis
-	11, // 9: conf.v1.Discovery.consul:type_name -> conf.v1.Discovery.Consul
-	12, // 10: conf.v1.Search.elastic_search:type_name -> conf.v1.Search.ElasticSearch
-	9,  // 11: conf.v1.Data.Database.pool:type_name -> conf.v1.Data.DatabasePool
-	12, // [12:12] is the sub-list for method output_type
-	12, // [12:12] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	7,  // 3: conf.v1.Bootstrap.trace:type_name -> conf.v1.Trace
+	8,  // 4: conf.v1.Bootstrap.discovery:type_name -> conf.v1.Discovery
+	9,  // 5: conf.v1.Bootstrap.search:type_name -> conf.v1.Search
+	4,  // 6: conf.v1.Bootstrap.authorization:type_name -> conf.v1.Authorization
+	5,  // 7: conf.v1.Bootstrap.rate_limit:type_name -> conf.v1.RateLimit
+	6,  // 8: conf.v1.Bootstrap.mail:type_name -> conf.v1.Mail
+	10, // 9: conf.v1.Server.http:type_name -> conf.v1.Server.HTTP
+	11, // 10: conf.v1.Server.health:type_name -> conf.v1.Server.Health
+	12, // 11: conf.v1.Server.shutdown:type_name -> conf.v1.Server.Shutdown
+	13, // 12: conf.v1.Data.database:type_name -> conf.v1.Data.Database
+	15, // 13: conf.v1.Data.redis:type_name -> conf.v1.Data.Redis
+	16, // 14: conf.v1.Data.outbox:type_name -> conf.v1.Data.Outbox
+	17, // 15: conf.v1.Auth.session:type_name -> conf.v1.Auth.Session
+	18, // 16: conf.v1.Auth.lockout:type_name -> conf.v1.Auth.Lockout
+	19, // 17: conf.v1.Auth.mfa:type_name -> conf.v1.Auth.Mfa
+	20, // 18: conf.v1.Auth.email_tokens:type_name -> conf.v1.Auth.EmailTokens
+	21, // 19: conf.v1.Authorization.policies:type_name -> conf.v1.Authorization.Policy
+	22, // 20: conf.v1.RateLimit.rules:type_name -> conf.v1.RateLimit.Rule
+	23, // 21: conf.v1.Mail.smtp:type_name -> conf.v1.Mail.SMTP
+	24, // 22: conf.v1.Discovery.consul:type_name -> conf.v1.Discovery.Consul
+	27, // 23: conf.v1.Discovery.static:type_name -> conf.v1.Discovery.Static
+	28, // 24: conf.v1.Discovery.file:type_name -> conf.v1.Discovery.File
+	29, // 25: conf.v1.Discovery.kubernetes:type_name -> conf.v1.Discovery.Kubernetes
+	30, // 26: conf.v1.Search.elastic_search:type_name -> conf.v1.Search.ElasticSearch
+	14, // 27: conf.v1.Data.Database.pool:type_name -> conf.v1.Data.DatabasePool
+	25, // 28: conf.v1.Discovery.Consul.check:type_name -> conf.v1.Discovery.Check
+	26, // 29: conf.v1.Discovery.Static.services:type_name -> conf.v1.Discovery.Service
+	30, // [30:30] is the sub-list for method output_type
+	30, // [30:30] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_internal_conf_v1_conf_proto_init() }
@@ -1010,7 +2495,7 @@ func file_internal_conf_v1_conf_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_conf_v1_conf_proto_rawDesc), len(file_internal_conf_v1_conf_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
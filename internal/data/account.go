@@ -0,0 +1,156 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connect-go-example/internal/biz"
+	"connect-go-example/internal/data/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var _ biz.AccountRepo = (*accountRepo)(nil)
+
+type accountRepo struct {
+	db      beginner
+	queries models.Querier
+	l       *zap.Logger
+}
+
+func NewAccountRepo(data *Data, logger *zap.Logger) biz.AccountRepo {
+	return &accountRepo{
+		db:      data.db,
+		queries: models.New(data.db),
+		l:       logger,
+	}
+}
+
+func (r *accountRepo) ListUsersByVerifiedEmail(ctx context.Context, email string) ([]*biz.UserInfo, error) {
+	rows, err := r.queries.ListUsersByVerifiedEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("list users by email failed: %w", err)
+	}
+
+	users := make([]*biz.UserInfo, 0, len(rows))
+	for _, row := range rows {
+		users = append(users, &biz.UserInfo{
+			ID:            row.ID,
+			Username:      row.Username,
+			Email:         row.Email,
+			EmailVerified: true,
+		})
+	}
+	return users, nil
+}
+
+// CreateToken 在同一事务中作废旧令牌并写入新令牌的哈希
+func (r *accountRepo) CreateToken(ctx context.Context, user *biz.UserInfo, purpose biz.TokenPurpose, ttl time.Duration) (string, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = inTx(ctx, r.db, func(q models.Querier) error {
+		err := q.InvalidateUserTokens(ctx, models.InvalidateUserTokensParams{
+			UserID:  user.ID,
+			Purpose: string(purpose),
+		})
+		if err != nil {
+			return err
+		}
+		return q.InsertUserToken(ctx, models.InsertUserTokenParams{
+			UserID:    user.ID,
+			Purpose:   string(purpose),
+			TokenHash: hashToken(token),
+			Email:     user.Email,
+			ExpiresAt: time.Now().Add(ttl),
+		})
+	})
+	if err != nil {
+		return "", fmt.Errorf("create %s token failed: %w", purpose, err)
+	}
+	return token, nil
+}
+
+// ResetPassword 消费令牌和更新密码在同一事务中完成，更新失败时令牌仍然可用
+// 令牌消费成功后才生成新密码的哈希
+func (r *accountRepo) ResetPassword(ctx context.Context, token string, credential func() (biz.UserCredential, error)) (int64, error) {
+	var userID int64
+	err := inTx(ctx, r.db, func(q models.Querier) error {
+		row, err := consumeToken(ctx, q, token, biz.TokenPurposePasswordReset)
+		if err != nil {
+			return err
+		}
+		cred, err := credential()
+		if err != nil {
+			return err
+		}
+		rows, err := q.UpdateUserPassword(ctx, models.UpdateUserPasswordParams{
+			PasswordHash: cred.PasswordHash,
+			Salt:         cred.Salt,
+			ID:           row.UserID,
+			Email:        row.Email,
+		})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			// 用户已删除，或签发令牌后修改了邮箱
+			return biz.ErrInvalidToken
+		}
+		userID = row.UserID
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, biz.ErrInvalidToken) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("reset password failed: %w", err)
+	}
+	return userID, nil
+}
+
+func (r *accountRepo) VerifyEmail(ctx context.Context, token string) (int64, error) {
+	var userID int64
+	err := inTx(ctx, r.db, func(q models.Querier) error {
+		row, err := consumeToken(ctx, q, token, biz.TokenPurposeEmailVerification)
+		if err != nil {
+			return err
+		}
+		rows, err := q.VerifyUserEmail(ctx, models.VerifyUserEmailParams{
+			ID:    row.UserID,
+			Email: row.Email,
+		})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return biz.ErrInvalidToken
+		}
+		userID = row.UserID
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, biz.ErrInvalidToken) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("verify email failed: %w", err)
+	}
+	return userID, nil
+}
+
+// consumeToken 将令牌标记为已使用，令牌不存在、已使用或已过期时返回 ErrInvalidToken
+func consumeToken(ctx context.Context, q models.Querier, token string, purpose biz.TokenPurpose) (models.ConsumeUserTokenRow, error) {
+	row, err := q.ConsumeUserToken(ctx, models.ConsumeUserTokenParams{
+		TokenHash: hashToken(token),
+		Purpose:   string(purpose),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return row, biz.ErrInvalidToken
+	}
+	return row, err
+}
@@ -0,0 +1,148 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connect-go-example/internal/biz"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// captureString 匹配任意字符串参数并记录下来
+type captureString struct {
+	value string
+}
+
+func (c *captureString) Match(v any) bool {
+	c.value, _ = v.(string)
+	return true
+}
+
+// TestAccountRepo_CreateToken 作废旧令牌和写入新令牌在同一事务中完成，令牌只保存哈希
+func TestAccountRepo_CreateToken(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	tokenHash := &captureString{}
+	mock.ExpectBegin()
+	mock.ExpectExec("-- name: InvalidateUserTokens").
+		WithArgs(int64(1), "password_reset").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec("-- name: InsertUserToken").
+		WithArgs(int64(1), "password_reset", tokenHash, "alice@example.com", pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	repo := &accountRepo{db: mock, l: zap.NewNop()}
+	user := &biz.UserInfo{ID: 1, Username: "alice", Email: "alice@example.com"}
+	token, err := repo.CreateToken(context.Background(), user, biz.TokenPurposePasswordReset, time.Hour)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.Equal(t, hashToken(token), tokenHash.value)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccountRepo_ResetPassword(t *testing.T) {
+	token := "reset-token"
+	tests := []struct {
+		name       string
+		expect     func(mock pgxmock.PgxPoolIface)
+		wantHashed bool
+		wantErr    error
+	}{
+		{
+			name: "success",
+			expect: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("-- name: ConsumeUserToken").
+					WithArgs(hashToken(token), "password_reset").
+					WillReturnRows(pgxmock.NewRows([]string{"user_id", "email"}).AddRow(int64(1), "alice@example.com"))
+				mock.ExpectExec("-- name: UpdateUserPassword").
+					WithArgs("hash", "salt", int64(1), "alice@example.com").
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectCommit()
+			},
+			wantHashed: true,
+		},
+		{
+			name: "invalid token",
+			expect: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("-- name: ConsumeUserToken").
+					WithArgs(hashToken(token), "password_reset").
+					WillReturnRows(pgxmock.NewRows([]string{"user_id", "email"}))
+				mock.ExpectRollback()
+			},
+			wantErr: biz.ErrInvalidToken,
+		},
+		{
+			name: "email changed",
+			expect: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("-- name: ConsumeUserToken").
+					WithArgs(hashToken(token), "password_reset").
+					WillReturnRows(pgxmock.NewRows([]string{"user_id", "email"}).AddRow(int64(1), "old@example.com"))
+				mock.ExpectExec("-- name: UpdateUserPassword").
+					WithArgs("hash", "salt", int64(1), "old@example.com").
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+				mock.ExpectRollback()
+			},
+			wantHashed: true,
+			wantErr:    biz.ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+			tt.expect(mock)
+
+			repo := &accountRepo{db: mock, l: zap.NewNop()}
+			// 令牌无效时不计算新密码的哈希
+			hashed := false
+			userID, err := repo.ResetPassword(context.Background(), token, func() (biz.UserCredential, error) {
+				hashed = true
+				return biz.UserCredential{PasswordHash: "hash", Salt: "salt"}, nil
+			})
+			assert.Equal(t, tt.wantHashed, hashed)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), userID)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAccountRepo_VerifyEmail(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("-- name: ConsumeUserToken").
+		WithArgs(hashToken("verify-token"), "email_verification").
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "email"}).AddRow(int64(1), "alice@example.com"))
+	mock.ExpectExec("-- name: VerifyUserEmail").
+		WithArgs(int64(1), "alice@example.com").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+
+	repo := &accountRepo{db: mock, l: zap.NewNop()}
+	userID, err := repo.VerifyEmail(context.Background(), "verify-token")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), userID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,16 +2,24 @@ package data
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/data/models"
+	"connect-go-example/internal/pkg/health"
+	"connect-go-example/internal/pkg/meta"
 
 	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
 	"github.com/elastic/elastic-transport-go/v8/elastictransport"
 	"github.com/elastic/go-elasticsearch/v9"
 	"github.com/exaring/otelpgx"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/fx"
@@ -27,7 +35,18 @@ var Module = fx.Module("data",
 		NewAuth,
 		NewElasticSearch,
 		NewUserRepo,
+		NewSessionRepo,
+		NewMFARepo,
+		NewAccountRepo,
+		NewUserSearchRepo,
+		NewOutboxRelay,
+		NewMigrator,
+		NewMailer,
 	),
+	// 按需在启动时执行迁移，需要在其他依赖表结构的组件之前
+	fx.Invoke(autoMigrate),
+	// 启动发件箱投递任务
+	fx.Invoke(func(*OutboxRelay) {}),
 )
 
 // Data 包含所有数据源的客户端
@@ -36,6 +55,30 @@ type Data struct {
 	rdb  *redis.Client
 	auth *casdoorsdk.Client
 	es   *elasticsearch.TypedClient
+	// casdoorHealth 探测 Casdoor 使用的 HTTP 客户端，不与 http.DefaultClient 共享配置
+	casdoorHealth *http.Client
+}
+
+// beginner 可以开启事务的连接，*pgxpool.Pool 满足该接口
+type beginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// inTx 在同一个事务中执行 fn，fn 返回错误时回滚
+func inTx(ctx context.Context, db beginner, fn func(q models.Querier) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction failed: %w", err)
+	}
+
+	if err := fn(models.New(tx)); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 // NewData 是 Data 的构造函数
@@ -45,11 +88,20 @@ func NewData(db *pgxpool.Pool, rdb *redis.Client, auth *casdoorsdk.Client, es *e
 		rdb:  rdb,
 		auth: auth,
 		es:   es,
+		casdoorHealth: &http.Client{
+			Timeout: casdoorHealthTimeout,
+		},
 	}
 }
 
+// casdoorHealthTimeout 探测 Casdoor 的超时时间，防止未设置截止时间的调用方被挂起
+const casdoorHealthTimeout = 5 * time.Second
+
+// maxApplicationNameLen Postgres application_name 的最大长度（NAMEDATALEN - 1）
+const maxApplicationNameLen = 63
+
 // NewDB 创建数据库连接池
-func NewDB(lc fx.Lifecycle, cfg *conf.Bootstrap, logger *zap.Logger) (*pgxpool.Pool, error) {
+func NewDB(lc fx.Lifecycle, cfg *conf.Bootstrap, appInfo meta.AppInfo, logger *zap.Logger) (*pgxpool.Pool, error) {
 	dbCfg := cfg.Data.Database // 从 Config 中获取 Data 配置
 
 	connString := fmt.Sprintf("postgresql://%s:%s@%s:%d/%s?sslmode=%s&timezone=%s",
@@ -67,6 +119,9 @@ func NewDB(lc fx.Lifecycle, cfg *conf.Bootstrap, logger *zap.Logger) (*pgxpool.P
 		return nil, fmt.Errorf("parse database config failed: %v", err)
 	}
 
+	// 连接池配置
+	applyPoolConfig(poolCfg, dbCfg.Pool, appInfo)
+
 	// 链路追踪配置
 	poolCfg.ConnConfig.Tracer = otelpgx.NewTracer()
 
@@ -86,7 +141,10 @@ func NewDB(lc fx.Lifecycle, cfg *conf.Bootstrap, logger *zap.Logger) (*pgxpool.P
 		return nil, fmt.Errorf("database ping failed: %v", err)
 	}
 
-	logger.Info(fmt.Sprintf("Database connected successfully to %s", dbCfg.Host))
+	logger.Info(fmt.Sprintf("Database connected successfully to %s", dbCfg.Host),
+		zap.Int32("max_conns", poolCfg.MaxConns),
+		zap.Int32("min_conns", poolCfg.MinConns),
+	)
 
 	// 注册关闭钩子
 	lc.Append(fx.Hook{
@@ -100,6 +158,47 @@ func NewDB(lc fx.Lifecycle, cfg *conf.Bootstrap, logger *zap.Logger) (*pgxpool.P
 	return pool, nil
 }
 
+// applyPoolConfig 将 DatabasePool 配置应用到连接池，未配置（0）的项保留 pgx 的默认值
+func applyPoolConfig(poolCfg *pgxpool.Config, pool *conf.Data_DatabasePool, appInfo meta.AppInfo) {
+	if v := pool.GetMaxConns(); v > 0 {
+		poolCfg.MaxConns = v
+	}
+	if v := pool.GetMinConns(); v > 0 {
+		poolCfg.MinConns = v
+		// 未配置 max_conns 时，默认的最大连接数可能小于 min_conns
+		poolCfg.MaxConns = max(poolCfg.MaxConns, v)
+	}
+	if v := pool.GetMaxConnLifetime(); v > 0 {
+		poolCfg.MaxConnLifetime = time.Duration(v) * time.Second
+	}
+	if v := pool.GetMaxConnLifetimeJitter(); v > 0 {
+		poolCfg.MaxConnLifetimeJitter = time.Duration(v) * time.Second
+	}
+	if v := pool.GetMaxConnIdleTime(); v > 0 {
+		poolCfg.MaxConnIdleTime = time.Duration(v) * time.Second
+	}
+	if v := pool.GetHealthCheckPeriod(); v > 0 {
+		poolCfg.HealthCheckPeriod = time.Duration(v) * time.Second
+	}
+
+	// 以下参数在建立连接时作为运行时参数发送给服务端
+	params := poolCfg.ConnConfig.RuntimeParams
+	if v := pool.GetStatementTimeout(); v > 0 {
+		params["statement_timeout"] = strconv.FormatInt(v, 10)
+	}
+	// 便于在 pg_stat_activity 中区分连接来源
+	if _, ok := params["application_name"]; !ok && appInfo.Name != "" {
+		name := appInfo.Name
+		if appInfo.Version != "" {
+			name += "/" + appInfo.Version
+		}
+		if len(name) > maxApplicationNameLen {
+			name = name[:maxApplicationNameLen]
+		}
+		params["application_name"] = name
+	}
+}
+
 // NewCache 创建 Redis 客户端
 func NewCache(lc fx.Lifecycle, cfg *conf.Bootstrap, logger *zap.Logger) (*redis.Client, error) {
 	redisCfg := cfg.Data.Redis // 从 Config 中获取 Redis 配置
@@ -158,11 +257,11 @@ func NewAuth(lc fx.Lifecycle, conf *conf.Bootstrap, logger *zap.Logger) *casdoor
 }
 
 // NewElasticSearch https://www.elastic.co/docs/reference/elasticsearch/clients/go/examples
-func NewElasticSearch(lc fx.Lifecycle, conf *conf.Bootstrap, logger *zap.Logger) *elasticsearch.TypedClient {
+func NewElasticSearch(lc fx.Lifecycle, conf *conf.Bootstrap, logger *zap.Logger) (*elasticsearch.TypedClient, error) {
 	cfg := elasticsearch.Config{
 		Addresses: conf.Search.ElasticSearch.Addresses,
-		// Username:  "Username",
-		// Password:  "Password",
+		Username:  conf.Search.ElasticSearch.Username,
+		Password:  conf.Search.ElasticSearch.Password,
 		// CloudID:   "",
 		// APIKey:    "",
 		Logger: &elastictransport.ColorLogger{Output: os.Stdout},
@@ -170,24 +269,55 @@ func NewElasticSearch(lc fx.Lifecycle, conf *conf.Bootstrap, logger *zap.Logger)
 
 	es, err := elasticsearch.NewTypedClient(cfg)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("create elasticsearch client failed: %w", err)
 	}
 	logger.Info(elasticsearch.Version)
 	// logger.Info(es.Info())
 	// logger.Info(es.Transport.(*elastictransport.Client).URLs())
 	logger.Info("elastic search server running")
-	return es
+	return es, nil
+}
+
+// HealthProbes 返回各个外部依赖的健康探测，由 health.Checker 周期性执行
+// Elasticsearch 和 Casdoor 只影响搜索和第三方登录，标记为 Optional，不决定整体是否就绪
+func (d *Data) HealthProbes() []health.Probe {
+	return []health.Probe{
+		{Name: "postgres", Check: d.db.Ping},
+		{Name: "redis", Check: func(ctx context.Context) error {
+			return d.rdb.Ping(ctx).Err()
+		}},
+		{Name: "elasticsearch", Check: d.pingElasticSearch, Optional: true},
+		{Name: "casdoor", Check: d.pingCasdoor, Optional: true},
+	}
+}
+
+func (d *Data) pingElasticSearch(ctx context.Context) error {
+	ok, err := d.es.Ping().IsSuccess(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("ping returned non-2xx status")
+	}
+	return nil
 }
 
-// HealthCheck 健康检查
-func (d *Data) HealthCheck(ctx context.Context) error {
-	if err := d.db.Ping(ctx); err != nil {
-		return fmt.Errorf("database health check failed: %v", err)
+// pingCasdoor 请求 Casdoor 的 /api/health 接口
+func (d *Data) pingCasdoor(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.auth.GetUrl("health", nil), nil)
+	if err != nil {
+		return err
 	}
 
-	if err := d.rdb.Ping(ctx).Err(); err != nil {
-		return fmt.Errorf("redis health check failed: %v", err)
+	resp, err := d.casdoorHealth.Do(req)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
 
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
 	return nil
 }
@@ -0,0 +1,104 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connect-go-example/internal/biz"
+	"connect-go-example/internal/data/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 登录失败计数的 Redis key，按用户名区分
+const (
+	loginFailuresKeyPrefix = "login:failures:" // 窗口内的失败次数
+	loginLockedKeyPrefix   = "login:locked:"   // 存在即处于锁定状态，TTL 为剩余锁定时间
+	loginLockoutsKeyPrefix = "login:lockouts:" // 已锁定的次数，用于计算指数增长的锁定时长
+)
+
+// recordFailureScript 原子地累加失败次数，达到阈值时清零并锁定
+// ARGV: 最大失败次数、窗口、首次锁定时长、锁定时长上限、锁定次数的保留时间（毫秒）
+// 返回本次触发的锁定时长（毫秒），未锁定时为 0
+var recordFailureScript = redis.NewScript(`
+local failures = redis.call("INCR", KEYS[1])
+if failures == 1 then
+  redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if failures < tonumber(ARGV[1]) then
+  return 0
+end
+
+redis.call("DEL", KEYS[1])
+local lockouts = redis.call("INCR", KEYS[3])
+redis.call("PEXPIRE", KEYS[3], ARGV[5])
+local duration = math.floor(math.min(tonumber(ARGV[3]) * 2 ^ (lockouts - 1), tonumber(ARGV[4])))
+redis.call("SET", KEYS[2], lockouts, "PX", duration)
+return duration
+`)
+
+func lockoutKeys(username string) []string {
+	return []string{
+		loginFailuresKeyPrefix + username,
+		loginLockedKeyPrefix + username,
+		loginLockoutsKeyPrefix + username,
+	}
+}
+
+func (u userRepo) LockedFor(ctx context.Context, username string) (time.Duration, error) {
+	ttl, err := u.rdb.PTTL(ctx, loginLockedKeyPrefix+username).Result()
+	if err != nil {
+		return 0, fmt.Errorf("get lockout failed: %w", err)
+	}
+	// key 不存在时 PTTL 返回负值
+	return max(ttl, 0), nil
+}
+
+func (u userRepo) RecordLoginFailure(ctx context.Context, username string, policy biz.LockoutPolicy) (time.Duration, error) {
+	duration, err := recordFailureScript.Run(ctx, u.rdb, lockoutKeys(username),
+		policy.MaxFailures,
+		policy.Window.Milliseconds(),
+		policy.BaseDuration.Milliseconds(),
+		policy.MaxDuration.Milliseconds(),
+		policy.ResetAfter.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("record login failure failed: %w", err)
+	}
+	return time.Duration(duration) * time.Millisecond, nil
+}
+
+func (u userRepo) ResetLoginFailures(ctx context.Context, username string) error {
+	// 锁定次数保留到过期，短时间内再次被锁定时仍然按指数增长
+	if err := u.rdb.Del(ctx, loginFailuresKeyPrefix+username).Err(); err != nil {
+		return fmt.Errorf("reset login failures failed: %w", err)
+	}
+	return nil
+}
+
+func (u userRepo) UnlockUser(ctx context.Context, username string) error {
+	if err := u.rdb.Del(ctx, lockoutKeys(username)...).Err(); err != nil {
+		return fmt.Errorf("unlock user failed: %w", err)
+	}
+	return nil
+}
+
+func (u userRepo) RecordLoginAttempt(ctx context.Context, attempt biz.LoginAttempt) error {
+	var userID *int64
+	if attempt.UserID != 0 {
+		userID = &attempt.UserID
+	}
+	err := u.queries.InsertLoginAttempt(ctx, models.InsertLoginAttemptParams{
+		Username:     attempt.Username,
+		UserID:       userID,
+		Ip:           attempt.IP,
+		ForwardedFor: attempt.ForwardedFor,
+		UserAgent:    attempt.UserAgent,
+		Result:       string(attempt.Result),
+	})
+	if err != nil {
+		return fmt.Errorf("insert login attempt failed: %w", err)
+	}
+	return nil
+}
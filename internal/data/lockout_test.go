@@ -0,0 +1,149 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connect-go-example/internal/biz"
+	"connect-go-example/internal/data/models"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+var testLockoutPolicy = biz.LockoutPolicy{
+	MaxFailures:  3,
+	Window:       time.Minute,
+	BaseDuration: time.Minute,
+	MaxDuration:  3 * time.Minute,
+	ResetAfter:   time.Hour,
+}
+
+// LockoutRepoTestSuite 测试登录失败计数，使用 miniredis 代替真实 Redis
+type LockoutRepoTestSuite struct {
+	suite.Suite
+	mr   *miniredis.Miniredis
+	repo *userRepo
+	ctx  context.Context
+}
+
+func (suite *LockoutRepoTestSuite) SetupTest() {
+	suite.mr = miniredis.RunT(suite.T())
+	suite.repo = &userRepo{
+		rdb: redis.NewClient(&redis.Options{Addr: suite.mr.Addr()}),
+		l:   zap.NewNop(),
+	}
+	suite.ctx = context.Background()
+}
+
+// fail 连续失败 n 次，返回最后一次的锁定时长
+func (suite *LockoutRepoTestSuite) fail(n int) time.Duration {
+	var lockedFor time.Duration
+	for range n {
+		var err error
+		lockedFor, err = suite.repo.RecordLoginFailure(suite.ctx, "alice", testLockoutPolicy)
+		require.NoError(suite.T(), err)
+	}
+	return lockedFor
+}
+
+func (suite *LockoutRepoTestSuite) lockedFor() time.Duration {
+	remaining, err := suite.repo.LockedFor(suite.ctx, "alice")
+	require.NoError(suite.T(), err)
+	return remaining
+}
+
+func (suite *LockoutRepoTestSuite) TestLocksAtMaxFailures() {
+	assert.Zero(suite.T(), suite.fail(2))
+	assert.Zero(suite.T(), suite.lockedFor())
+
+	assert.Equal(suite.T(), time.Minute, suite.fail(1))
+	assert.Equal(suite.T(), time.Minute, suite.lockedFor())
+
+	// 锁定后失败计数清零
+	assert.False(suite.T(), suite.mr.Exists(loginFailuresKeyPrefix+"alice"))
+}
+
+func (suite *LockoutRepoTestSuite) TestFailuresExpireAfterWindow() {
+	suite.fail(2)
+	suite.mr.FastForward(time.Minute)
+
+	assert.Zero(suite.T(), suite.fail(2))
+}
+
+func (suite *LockoutRepoTestSuite) TestLockDurationGrows() {
+	assert.Equal(suite.T(), time.Minute, suite.fail(3))
+	suite.mr.FastForward(time.Minute)
+	assert.Zero(suite.T(), suite.lockedFor())
+
+	assert.Equal(suite.T(), 2*time.Minute, suite.fail(3))
+	suite.mr.FastForward(2 * time.Minute)
+
+	// 不超过上限
+	assert.Equal(suite.T(), 3*time.Minute, suite.fail(3))
+
+	// 超过 ResetAfter 没有再次锁定时恢复为首次锁定时长
+	suite.mr.FastForward(time.Hour)
+	assert.Equal(suite.T(), time.Minute, suite.fail(3))
+}
+
+func (suite *LockoutRepoTestSuite) TestResetKeepsLockoutCount() {
+	suite.fail(3)
+	suite.mr.FastForward(time.Minute)
+	suite.fail(2)
+
+	require.NoError(suite.T(), suite.repo.ResetLoginFailures(suite.ctx, "alice"))
+	assert.Zero(suite.T(), suite.fail(2))
+	assert.Equal(suite.T(), 2*time.Minute, suite.fail(1))
+}
+
+func (suite *LockoutRepoTestSuite) TestUnlockUser() {
+	suite.fail(3)
+	require.NoError(suite.T(), suite.repo.UnlockUser(suite.ctx, "alice"))
+
+	assert.Zero(suite.T(), suite.lockedFor())
+	// 锁定次数同时清除
+	assert.Equal(suite.T(), time.Minute, suite.fail(3))
+}
+
+// 运行测试套件
+func TestLockoutRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(LockoutRepoTestSuite))
+}
+
+func TestUserRepo_RecordLoginAttempt(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	userID := int64(7)
+	mock.ExpectExec("-- name: InsertLoginAttempt").
+		WithArgs("alice", &userID, "192.0.2.1", "", "curl/8.0", "invalid_password").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec("-- name: InsertLoginAttempt").
+		WithArgs("mallory", (*int64)(nil), "192.0.2.2", "203.0.113.9", "", "unknown_user").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	repo := &userRepo{queries: models.New(mock), l: zap.NewNop()}
+	require.NoError(t, repo.RecordLoginAttempt(context.Background(), biz.LoginAttempt{
+		Username:  "alice",
+		UserID:    7,
+		IP:        "192.0.2.1",
+		UserAgent: "curl/8.0",
+		Result:    biz.LoginResultInvalidPassword,
+	}))
+	require.NoError(t, repo.RecordLoginAttempt(context.Background(), biz.LoginAttempt{
+		Username:     "mallory",
+		IP:           "192.0.2.2",
+		ForwardedFor: "203.0.113.9",
+		Result:       biz.LoginResultUnknownUser,
+	}))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,143 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/mail"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	// mailQueueSize 等待发送的邮件上限，队列满时丢弃新的邮件
+	mailQueueSize = 256
+	// mailSendTimeout 发送一封邮件的超时时间
+	mailSendTimeout = 30 * time.Second
+)
+
+// ErrMailQueueFull 邮件队列已满，邮件被丢弃
+var ErrMailQueueFull = errors.New("mail queue is full")
+
+// NewMailer 按 mail 配置创建投递方式，并包装为随应用启动和停止的发送队列
+func NewMailer(lc fx.Lifecycle, cfg *conf.Bootstrap, logger *zap.Logger) (mail.Mailer, error) {
+	next, err := mail.New(cfg.GetMail(), logger)
+	if err != nil {
+		return nil, err
+	}
+	mailer, err := newQueuedMailer(next, mailQueueSize, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			mailer.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return mailer.Stop(ctx)
+		},
+	})
+
+	return mailer, nil
+}
+
+// QueuedMailer 将邮件放入队列后立即返回，由后台任务逐封交给下一层 Mailer 发送
+// 请求不必等待 SMTP 往返，耗时也不会因为是否发出邮件而不同
+type QueuedMailer struct {
+	next  mail.Mailer
+	queue chan mail.Message
+	l     *zap.Logger
+
+	// dropped 因队列已满或停止时尚未发送而丢弃的邮件数
+	dropped metric.Int64Counter
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newQueuedMailer(next mail.Mailer, size int, logger *zap.Logger) (*QueuedMailer, error) {
+	meter := otel.GetMeterProvider().Meter("github.com/sunmery/ecommerce/backend/data")
+	dropped, err := meter.Int64Counter(
+		"mail.dropped",
+		metric.WithDescription("Number of emails dropped before delivery"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init counter: %w", err)
+	}
+
+	return &QueuedMailer{
+		next:    next,
+		queue:   make(chan mail.Message, size),
+		l:       logger,
+		dropped: dropped,
+	}, nil
+}
+
+// Send 将邮件放入队列，队列满时丢弃邮件并返回 ErrMailQueueFull
+func (m *QueuedMailer) Send(ctx context.Context, msg mail.Message) error {
+	select {
+	case m.queue <- msg:
+		return nil
+	default:
+		m.dropped.Add(ctx, 1)
+		m.l.Warn("Mail queue is full, dropping message",
+			zap.String("subject", msg.Subject),
+			zap.Int("recipients", len(msg.To)),
+		)
+		return ErrMailQueueFull
+	}
+}
+
+// Start 启动发送邮件的后台任务
+func (m *QueuedMailer) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.done = make(chan struct{})
+
+	go m.run(ctx)
+}
+
+// Stop 停止后台任务并等待当前邮件发送完成，队列中尚未发送的邮件被丢弃
+func (m *QueuedMailer) Stop(ctx context.Context) error {
+	if m.cancel == nil {
+		return nil
+	}
+	m.cancel()
+
+	select {
+	case <-m.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if n := len(m.queue); n > 0 {
+		m.dropped.Add(ctx, int64(n))
+		m.l.Warn("Mailer stopped, dropping queued messages", zap.Int("count", n))
+	}
+	return nil
+}
+
+func (m *QueuedMailer) run(ctx context.Context) {
+	defer close(m.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-m.queue:
+			sendCtx, cancel := context.WithTimeout(ctx, mailSendTimeout)
+			if err := m.next.Send(sendCtx, msg); err != nil {
+				m.l.Error("Failed to send mail", zap.String("subject", msg.Subject), zap.Error(err))
+			}
+			cancel()
+		}
+	}
+}
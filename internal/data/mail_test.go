@@ -0,0 +1,32 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connect-go-example/internal/pkg/mail"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestQueuedMailer(t *testing.T) {
+	next := mail.NewMemoryMailer()
+	mailer, err := newQueuedMailer(next, 2, zap.NewNop())
+	require.NoError(t, err)
+
+	// 后台任务启动前邮件只进入队列，队列满时丢弃并返回错误
+	msg := mail.Message{To: []string{"alice@example.com"}, Subject: "Reset your password"}
+	require.NoError(t, mailer.Send(context.Background(), msg))
+	require.NoError(t, mailer.Send(context.Background(), msg))
+	assert.ErrorIs(t, mailer.Send(context.Background(), msg), ErrMailQueueFull)
+	assert.Empty(t, next.Messages())
+
+	mailer.Start()
+	assert.Eventually(t, func() bool { return len(next.Messages()) == 2 }, 5*time.Second, time.Millisecond)
+
+	require.NoError(t, mailer.Stop(context.Background()))
+	assert.NoError(t, mailer.Stop(context.Background()))
+}
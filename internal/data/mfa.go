@@ -0,0 +1,259 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"connect-go-example/internal/biz"
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/data/models"
+	"connect-go-example/internal/pkg/secretbox"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// mfaChallengeKeyPrefix 登录挑战的 Redis key，HASH 记录 user_id、username、roles、attempts，TTL 为挑战有效期
+const mfaChallengeKeyPrefix = "mfa_challenge:"
+
+// challengeFailureScript 累加挑战的失败次数，达到上限时删除挑战
+// 挑战已过期时不做任何操作，避免 HINCRBY 重新创建一个没有 TTL 的 key
+var challengeFailureScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+  return 0
+end
+local attempts = redis.call("HINCRBY", KEYS[1], "attempts", 1)
+if attempts >= tonumber(ARGV[1]) then
+  redis.call("DEL", KEYS[1])
+end
+return attempts
+`)
+
+var _ biz.MFARepo = (*mfaRepo)(nil)
+
+type mfaRepo struct {
+	db      beginner
+	queries models.Querier
+	rdb     *redis.Client
+	box     *secretbox.Box // 未配置加密密钥时为 nil，此时无法读写 TOTP 密钥
+	l       *zap.Logger
+}
+
+func NewMFARepo(data *Data, cfg *conf.Bootstrap, logger *zap.Logger) (biz.MFARepo, error) {
+	var box *secretbox.Box
+	if encoded := cfg.GetAuth().GetMfa().GetEncryptionKey(); encoded != "" {
+		key, err := secretbox.DecodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("mfa encryption key: %w", err)
+		}
+		if box, err = secretbox.New(key); err != nil {
+			return nil, fmt.Errorf("mfa encryption key: %w", err)
+		}
+	} else {
+		logger.Warn("MFA encryption key is not configured, TOTP enrollment is disabled")
+	}
+
+	return &mfaRepo{
+		db:      data.db,
+		queries: models.New(data.db),
+		rdb:     data.rdb,
+		box:     box,
+		l:       logger,
+	}, nil
+}
+
+// secretAD 加密密钥时的附加数据，将密文绑定到用户，无法被复制到其他用户的记录上
+func secretAD(userID int64) []byte {
+	return []byte("user_mfa:" + strconv.FormatInt(userID, 10))
+}
+
+func (r *mfaRepo) GetMFA(ctx context.Context, userID int64) (*biz.MFA, error) {
+	row, err := r.queries.GetUserMFA(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, biz.ErrMFANotEnrolled
+		}
+		return nil, fmt.Errorf("get user mfa failed: %w", err)
+	}
+	if r.box == nil {
+		return nil, biz.ErrMFAUnavailable
+	}
+
+	secret, err := r.box.Open(row.Secret, secretAD(userID))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt totp secret failed: %w", err)
+	}
+	return &biz.MFA{
+		UserID:       userID,
+		Secret:       secret,
+		Enabled:      row.ConfirmedAt.Valid,
+		LastUsedStep: row.LastUsedStep,
+	}, nil
+}
+
+func (r *mfaRepo) SavePendingMFA(ctx context.Context, userID int64, secret []byte) error {
+	if r.box == nil {
+		return biz.ErrMFAUnavailable
+	}
+
+	sealed, err := r.box.Seal(secret, secretAD(userID))
+	if err != nil {
+		return err
+	}
+	rows, err := r.queries.UpsertPendingUserMFA(ctx, models.UpsertPendingUserMFAParams{
+		UserID: userID,
+		Secret: sealed,
+	})
+	if err != nil {
+		return fmt.Errorf("save user mfa failed: %w", err)
+	}
+	if rows == 0 {
+		return biz.ErrMFAAlreadyEnabled
+	}
+	return nil
+}
+
+// EnableMFA 在同一事务中确认绑定并写入恢复码
+func (r *mfaRepo) EnableMFA(ctx context.Context, userID int64, step int64, recoveryCodes []string) error {
+	err := inTx(ctx, r.db, func(q models.Querier) error {
+		rows, err := q.ConfirmUserMFA(ctx, models.ConfirmUserMFAParams{
+			LastUsedStep: step,
+			UserID:       userID,
+		})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return biz.ErrMFAAlreadyEnabled
+		}
+		return replaceRecoveryCodes(ctx, q, userID, recoveryCodes)
+	})
+	if err != nil {
+		if errors.Is(err, biz.ErrMFAAlreadyEnabled) {
+			return err
+		}
+		return fmt.Errorf("enable user mfa failed: %w", err)
+	}
+	return nil
+}
+
+func (r *mfaRepo) UseTOTPStep(ctx context.Context, userID int64, step int64) (bool, error) {
+	rows, err := r.queries.UseUserMFAStep(ctx, models.UseUserMFAStepParams{
+		LastUsedStep: step,
+		UserID:       userID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("use totp step failed: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func (r *mfaRepo) ReplaceRecoveryCodes(ctx context.Context, userID int64, recoveryCodes []string) error {
+	err := inTx(ctx, r.db, func(q models.Querier) error {
+		return replaceRecoveryCodes(ctx, q, userID, recoveryCodes)
+	})
+	if err != nil {
+		return fmt.Errorf("replace recovery codes failed: %w", err)
+	}
+	return nil
+}
+
+// replaceRecoveryCodes 恢复码只保存哈希；恢复码本身有足够的熵，不需要加盐的慢哈希
+func replaceRecoveryCodes(ctx context.Context, q models.Querier, userID int64, recoveryCodes []string) error {
+	if err := q.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return err
+	}
+	for _, code := range recoveryCodes {
+		err := q.InsertRecoveryCode(ctx, models.InsertRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: hashToken(code),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *mfaRepo) RedeemRecoveryCode(ctx context.Context, userID int64, recoveryCode string) (bool, error) {
+	rows, err := r.queries.RedeemRecoveryCode(ctx, models.RedeemRecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: hashToken(recoveryCode),
+	})
+	if err != nil {
+		return false, fmt.Errorf("redeem recovery code failed: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func (r *mfaRepo) CreateChallenge(ctx context.Context, user *biz.UserInfo, ttl time.Duration) (*biz.MFAChallenge, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	key := mfaChallengeKeyPrefix + hashToken(token)
+	_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			"user_id", strconv.FormatInt(user.ID, 10),
+			"username", user.Username,
+			"roles", joinRoles(user.Roles),
+			"attempts", 0,
+		)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("store mfa challenge failed: %w", err)
+	}
+
+	return &biz.MFAChallenge{
+		Token:     token,
+		UserID:    user.ID,
+		Username:  user.Username,
+		Roles:     user.Roles,
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+func (r *mfaRepo) GetChallenge(ctx context.Context, token string) (*biz.MFAChallenge, error) {
+	values, err := r.rdb.HGetAll(ctx, mfaChallengeKeyPrefix+hashToken(token)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("get mfa challenge failed: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, biz.ErrInvalidMFAChallenge
+	}
+
+	userID, _ := strconv.ParseInt(values["user_id"], 10, 64)
+	return &biz.MFAChallenge{
+		Token:    token,
+		UserID:   userID,
+		Username: values["username"],
+		Roles:    splitRoles(values["roles"]),
+	}, nil
+}
+
+func (r *mfaRepo) RecordChallengeFailure(ctx context.Context, token string, maxAttempts int) error {
+	err := challengeFailureScript.Run(ctx, r.rdb, []string{mfaChallengeKeyPrefix + hashToken(token)}, maxAttempts).Err()
+	if err != nil {
+		return fmt.Errorf("record mfa challenge failure failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteChallenge 删除挑战；并发请求中只有一个能删除成功，其余返回 ErrInvalidMFAChallenge
+func (r *mfaRepo) DeleteChallenge(ctx context.Context, token string) error {
+	deleted, err := r.rdb.Del(ctx, mfaChallengeKeyPrefix+hashToken(token)).Result()
+	if err != nil {
+		return fmt.Errorf("delete mfa challenge failed: %w", err)
+	}
+	if deleted == 0 {
+		return biz.ErrInvalidMFAChallenge
+	}
+	return nil
+}
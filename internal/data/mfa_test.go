@@ -0,0 +1,195 @@
+package data
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"connect-go-example/internal/biz"
+	"connect-go-example/internal/data/models"
+	"connect-go-example/internal/pkg/secretbox"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+// captureArg 匹配任意参数并记录下来，用于取出写入数据库的密文
+type captureArg struct {
+	value []byte
+}
+
+func (c *captureArg) Match(v any) bool {
+	c.value, _ = v.([]byte)
+	return true
+}
+
+func newTestBox(t *testing.T) *secretbox.Box {
+	box, err := secretbox.New(bytes.Repeat([]byte{7}, secretbox.KeySize))
+	require.NoError(t, err)
+	return box
+}
+
+func TestMFARepo_SecretEncrypted(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	secret := []byte("12345678901234567890")
+	sealed := &captureArg{}
+	mock.ExpectExec("-- name: UpsertPendingUserMFA").
+		WithArgs(int64(1), sealed).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	repo := &mfaRepo{queries: models.New(mock), box: newTestBox(t), l: zap.NewNop()}
+	require.NoError(t, repo.SavePendingMFA(context.Background(), 1, secret))
+	assert.NotContains(t, string(sealed.value), string(secret))
+
+	columns := []string{"user_id", "secret", "confirmed_at", "last_used_step"}
+	mock.ExpectQuery("-- name: GetUserMFA").
+		WithArgs(int64(1)).
+		WillReturnRows(pgxmock.NewRows(columns).
+			AddRow(int64(1), sealed.value, pgtype.Timestamptz{Time: time.Now(), Valid: true}, int64(42)))
+	mfa, err := repo.GetMFA(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, secret, mfa.Secret)
+	assert.True(t, mfa.Enabled)
+	assert.Equal(t, int64(42), mfa.LastUsedStep)
+
+	// 密文绑定到用户，复制到其他用户的记录上无法解密
+	mock.ExpectQuery("-- name: GetUserMFA").
+		WithArgs(int64(2)).
+		WillReturnRows(pgxmock.NewRows(columns).
+			AddRow(int64(2), sealed.value, pgtype.Timestamptz{}, int64(0)))
+	_, err = repo.GetMFA(context.Background(), 2)
+	assert.ErrorIs(t, err, secretbox.ErrInvalidCiphertext)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFARepo_Unavailable(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := &mfaRepo{queries: models.New(mock), l: zap.NewNop()}
+	assert.ErrorIs(t, repo.SavePendingMFA(context.Background(), 1, []byte("secret")), biz.ErrMFAUnavailable)
+
+	mock.ExpectQuery("-- name: GetUserMFA").
+		WithArgs(int64(1)).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "secret", "confirmed_at", "last_used_step"}))
+	_, err = repo.GetMFA(context.Background(), 1)
+	assert.ErrorIs(t, err, biz.ErrMFANotEnrolled)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestMFARepo_EnableMFA 确认绑定和写入恢复码在同一事务中完成，恢复码只保存哈希
+func TestMFARepo_EnableMFA(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("-- name: ConfirmUserMFA").
+		WithArgs(int64(100), int64(1)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec("-- name: DeleteRecoveryCodes").
+		WithArgs(int64(1)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	for _, code := range []string{"aaaaabbbbb", "cccccddddd"} {
+		mock.ExpectExec("-- name: InsertRecoveryCode").
+			WithArgs(int64(1), hashToken(code)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	}
+	mock.ExpectCommit()
+
+	repo := &mfaRepo{db: mock, l: zap.NewNop()}
+	require.NoError(t, repo.EnableMFA(context.Background(), 1, 100, []string{"aaaaabbbbb", "cccccddddd"}))
+
+	// 已经开启时回滚
+	mock.ExpectBegin()
+	mock.ExpectExec("-- name: ConfirmUserMFA").
+		WithArgs(int64(101), int64(1)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectRollback()
+	assert.ErrorIs(t, repo.EnableMFA(context.Background(), 1, 101, []string{"eeeeefffff"}), biz.ErrMFAAlreadyEnabled)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// MFAChallengeTestSuite 测试登录挑战，使用 miniredis 代替真实 Redis
+type MFAChallengeTestSuite struct {
+	suite.Suite
+	mr   *miniredis.Miniredis
+	repo *mfaRepo
+	ctx  context.Context
+}
+
+func (suite *MFAChallengeTestSuite) SetupTest() {
+	suite.mr = miniredis.RunT(suite.T())
+	suite.repo = &mfaRepo{
+		rdb: redis.NewClient(&redis.Options{Addr: suite.mr.Addr()}),
+		l:   zap.NewNop(),
+	}
+	suite.ctx = context.Background()
+}
+
+func (suite *MFAChallengeTestSuite) create() *biz.MFAChallenge {
+	challenge, err := suite.repo.CreateChallenge(suite.ctx, &biz.UserInfo{ID: 7, Username: "alice", Roles: []string{"admin"}}, time.Minute)
+	require.NoError(suite.T(), err)
+	return challenge
+}
+
+func (suite *MFAChallengeTestSuite) TestCreateAndGet() {
+	challenge := suite.create()
+
+	got, err := suite.repo.GetChallenge(suite.ctx, challenge.Token)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(7), got.UserID)
+	assert.Equal(suite.T(), "alice", got.Username)
+	assert.Equal(suite.T(), []string{"admin"}, got.Roles)
+
+	// 令牌只以哈希形式存储
+	assert.False(suite.T(), suite.mr.Exists(mfaChallengeKeyPrefix+challenge.Token))
+
+	suite.mr.FastForward(time.Minute)
+	_, err = suite.repo.GetChallenge(suite.ctx, challenge.Token)
+	assert.ErrorIs(suite.T(), err, biz.ErrInvalidMFAChallenge)
+}
+
+func (suite *MFAChallengeTestSuite) TestFailuresExhaustChallenge() {
+	challenge := suite.create()
+
+	for range 2 {
+		require.NoError(suite.T(), suite.repo.RecordChallengeFailure(suite.ctx, challenge.Token, 3))
+	}
+	_, err := suite.repo.GetChallenge(suite.ctx, challenge.Token)
+	require.NoError(suite.T(), err)
+
+	require.NoError(suite.T(), suite.repo.RecordChallengeFailure(suite.ctx, challenge.Token, 3))
+	_, err = suite.repo.GetChallenge(suite.ctx, challenge.Token)
+	assert.ErrorIs(suite.T(), err, biz.ErrInvalidMFAChallenge)
+
+	// 挑战不存在时不会重新创建 key
+	require.NoError(suite.T(), suite.repo.RecordChallengeFailure(suite.ctx, challenge.Token, 3))
+	assert.Empty(suite.T(), suite.mr.Keys())
+}
+
+func (suite *MFAChallengeTestSuite) TestDeleteOnce() {
+	challenge := suite.create()
+
+	require.NoError(suite.T(), suite.repo.DeleteChallenge(suite.ctx, challenge.Token))
+	assert.ErrorIs(suite.T(), suite.repo.DeleteChallenge(suite.ctx, challenge.Token), biz.ErrInvalidMFAChallenge)
+}
+
+// 运行测试套件
+func TestMFAChallengeTestSuite(t *testing.T) {
+	suite.Run(t, new(MFAChallengeTestSuite))
+}
@@ -0,0 +1,312 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/data/migrations"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// migrationLockKey 迁移使用的 advisory lock 键，多个副本同时启动时串行执行迁移
+const migrationLockKey int64 = 0x6d696772617465 // "migrate"
+
+const (
+	createMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations
+(
+    version    BIGINT PRIMARY KEY,
+    name       TEXT                      NOT NULL,
+    applied_at timestamptz DEFAULT now() NOT NULL
+)`
+	lockMigrations   = `SELECT pg_advisory_xact_lock($1)`
+	migrationApplied = `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`
+	insertMigration  = `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`
+	deleteMigration  = `DELETE FROM schema_migrations WHERE version = $1`
+	latestMigration  = `SELECT version FROM schema_migrations ORDER BY version DESC LIMIT 1`
+	listMigrations   = `SELECT version, applied_at FROM schema_migrations ORDER BY version`
+)
+
+var (
+	ErrNoMigrationToRollback = errors.New("no migration to roll back")
+	ErrMissingDownMigration  = errors.New("down migration not found")
+)
+
+// Migration 一个版本的迁移脚本
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus 迁移的应用状态
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator 执行内嵌的数据库迁移
+// 每个迁移在独立的事务中执行，并在事务内持有 advisory lock、重新检查版本，多个副本并发执行也不会重复应用
+type Migrator struct {
+	db         beginner
+	migrations []Migration
+	l          *zap.Logger
+}
+
+func NewMigrator(db *pgxpool.Pool, logger *zap.Logger) (*Migrator, error) {
+	return newMigrator(db, migrations.FS, logger)
+}
+
+func newMigrator(db beginner, fsys fs.FS, logger *zap.Logger) (*Migrator, error) {
+	ms, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: ms,
+		l:          logger,
+	}, nil
+}
+
+// loadMigrations 读取 <版本号>_<名称>.up.sql / .down.sql，按版本号升序返回
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, file := range files {
+		base, direction, ok := strings.Cut(strings.TrimSuffix(path.Base(file), ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("invalid migration file name %q", file)
+		}
+		versionStr, name, _ := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(versionStr, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", file)
+		}
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	ms := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		ms = append(ms, *m)
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Version < ms[j].Version })
+	return ms, nil
+}
+
+// Up 依次应用所有未应用的迁移，返回本次应用的数量
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, migration := range m.migrations {
+		ok, err := m.apply(ctx, migration)
+		if err != nil {
+			return applied, fmt.Errorf("apply migration %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		if ok {
+			applied++
+			m.l.Info("Migration applied", zap.Int64("version", migration.Version), zap.String("name", migration.Name))
+		}
+	}
+	return applied, nil
+}
+
+// Down 回滚最近应用的一个迁移，返回被回滚的迁移
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	var rolledBack *Migration
+	err := m.locked(ctx, func(tx pgx.Tx) error {
+		var version int64
+		if err := tx.QueryRow(ctx, latestMigration).Scan(&version); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrNoMigrationToRollback
+			}
+			return err
+		}
+
+		migration := m.find(version)
+		if migration == nil || migration.Down == "" {
+			return fmt.Errorf("%w: version %d", ErrMissingDownMigration, version)
+		}
+		if _, err := tx.Exec(ctx, migration.Down); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, deleteMigration, version); err != nil {
+			return err
+		}
+		rolledBack = migration
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.l.Info("Migration rolled back", zap.Int64("version", rolledBack.Version), zap.String("name", rolledBack.Name))
+	return rolledBack, nil
+}
+
+// Status 返回所有内嵌迁移的应用状态
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]time.Time)
+	err := m.locked(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, listMigrations)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var (
+				version   int64
+				appliedAt time.Time
+			)
+			if err := rows.Scan(&version, &appliedAt); err != nil {
+				return err
+			}
+			applied[version] = appliedAt
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedAt, ok := applied[migration.Version]
+		statuses = append(statuses, MigrationStatus{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+	return statuses, nil
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	// 并发执行 CREATE TABLE IF NOT EXISTS 仍可能冲突，同样需要加锁
+	err := m.locked(ctx, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, createMigrationsTable)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("create schema_migrations table failed: %w", err)
+	}
+	return nil
+}
+
+// apply 在持有锁的事务中应用一个迁移，已应用时返回 false
+func (m *Migrator) apply(ctx context.Context, migration Migration) (bool, error) {
+	applied := false
+	err := m.locked(ctx, func(tx pgx.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(ctx, migrationApplied, migration.Version).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+
+		if _, err := tx.Exec(ctx, migration.Up); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, insertMigration, migration.Version, migration.Name); err != nil {
+			return err
+		}
+		applied = true
+		return nil
+	})
+	return applied, err
+}
+
+// locked 在事务中执行 fn，事务开始时获取 advisory lock，提交或回滚时自动释放
+func (m *Migrator) locked(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction failed: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, lockMigrations, migrationLockKey); err != nil {
+		return errors.Join(err, tx.Rollback(ctx))
+	}
+	if err := fn(tx); err != nil {
+		return errors.Join(err, tx.Rollback(ctx))
+	}
+	return tx.Commit(ctx)
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// autoMigrate 配置了 auto_migrate 时在启动阶段执行迁移
+func autoMigrate(lc fx.Lifecycle, cfg *conf.Bootstrap, migrator *Migrator, logger *zap.Logger) {
+	if !cfg.GetData().GetDatabase().GetAutoMigrate() {
+		return
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			applied, err := migrator.Up(ctx)
+			if err != nil {
+				return err
+			}
+			logger.Info("Database migrated", zap.Int("applied", applied))
+			return nil
+		},
+	})
+}
@@ -0,0 +1,133 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"connect-go-example/internal/data/migrations"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	ms, err := loadMigrations(migrations.FS)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, ms)
+	for i, m := range ms {
+		assert.NotEmpty(t, m.Up, m.Name)
+		assert.NotEmpty(t, m.Down, m.Name)
+		assert.NotContains(t, m.Up, "CREATE DATABASE", m.Name)
+		if i > 0 {
+			assert.Greater(t, m.Version, ms[i-1].Version)
+		}
+	}
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"bad direction", fstest.MapFS{"0001_users.sideways.sql": {}}},
+		{"bad version", fstest.MapFS{"abc_users.up.sql": {Data: []byte("SELECT 1")}}},
+		{"missing up", fstest.MapFS{"0001_users.down.sql": {Data: []byte("SELECT 1")}}},
+		{"conflicting names", fstest.MapFS{
+			"0001_users.up.sql":    {Data: []byte("SELECT 1")},
+			"0001_people.down.sql": {Data: []byte("SELECT 1")},
+		}},
+	}
+	for _, tt := range tests {
+		_, err := loadMigrations(tt.fsys)
+		assert.Error(t, err, tt.name)
+	}
+}
+
+func newTestMigrator(t *testing.T) (*Migrator, pgxmock.PgxPoolIface) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(mock.Close)
+
+	m, err := newMigrator(mock, fstest.MapFS{
+		"0001_users.up.sql":    {Data: []byte("CREATE TABLE users (id INT)")},
+		"0001_users.down.sql":  {Data: []byte("DROP TABLE users")},
+		"0002_orders.up.sql":   {Data: []byte("CREATE TABLE orders (id INT)")},
+		"0002_orders.down.sql": {Data: []byte("DROP TABLE orders")},
+	}, zap.NewNop())
+	require.NoError(t, err)
+	return m, mock
+}
+
+// expectLocked 每个事务都先获取 advisory lock
+func expectLocked(mock pgxmock.PgxPoolIface) {
+	mock.ExpectBegin()
+	mock.ExpectExec("pg_advisory_xact_lock").WithArgs(migrationLockKey).WillReturnResult(pgxmock.NewResult("SELECT", 1))
+}
+
+func TestMigrator_Up(t *testing.T) {
+	m, mock := newTestMigrator(t)
+
+	expectLocked(mock)
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mock.ExpectCommit()
+
+	// 0001 已由其他副本应用，直接跳过
+	expectLocked(mock)
+	mock.ExpectQuery("SELECT EXISTS").WithArgs(int64(1)).WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectCommit()
+
+	expectLocked(mock)
+	mock.ExpectQuery("SELECT EXISTS").WithArgs(int64(2)).WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("CREATE TABLE orders").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(int64(2), "orders").WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	applied, err := m.Up(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_UpRollsBackOnError(t *testing.T) {
+	m, mock := newTestMigrator(t)
+
+	expectLocked(mock)
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mock.ExpectCommit()
+
+	expectLocked(mock)
+	mock.ExpectQuery("SELECT EXISTS").WithArgs(int64(1)).WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("CREATE TABLE users").WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	applied, err := m.Up(context.Background())
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 0, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	m, mock := newTestMigrator(t)
+
+	expectLocked(mock)
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mock.ExpectCommit()
+
+	expectLocked(mock)
+	mock.ExpectQuery("SELECT version FROM schema_migrations").WillReturnRows(pgxmock.NewRows([]string{"version"}).AddRow(int64(2)))
+	mock.ExpectExec("DROP TABLE orders").WillReturnResult(pgxmock.NewResult("DROP TABLE", 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WithArgs(int64(2)).WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectCommit()
+
+	migration, err := m.Down(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), migration.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
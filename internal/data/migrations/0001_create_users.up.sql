@@ -0,0 +1,15 @@
+CREATE TABLE users
+(
+    id            SERIAL PRIMARY KEY,
+    username      VARCHAR(255) UNIQUE       NOT NULL, -- 关联用户ID
+    password_hash VARCHAR(255)              NOT NULL, -- 加密后密码
+    salt          VARCHAR(255)              NOT NULL, -- 盐值
+    nickname      VARCHAR(255) DEFAULT ''   NOT NULL, -- 昵称
+    email         VARCHAR(255) DEFAULT ''   NOT NULL, -- 邮箱
+    avatar        VARCHAR(1024) DEFAULT ''  NOT NULL, -- 头像地址
+    created_at    timestamptz DEFAULT now() NOT NULL, -- Unix时间戳，避免时区问题
+    updated_at    timestamptz DEFAULT now() NOT NULL,
+    deleted_at    timestamptz                         -- 软删除时间，为空表示未删除
+);
+COMMENT
+    ON TABLE users IS '用户表';
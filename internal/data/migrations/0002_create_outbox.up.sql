@@ -0,0 +1,17 @@
+CREATE TABLE outbox
+(
+    id              BIGSERIAL PRIMARY KEY,
+    aggregate_type  VARCHAR(64)                   NOT NULL, -- 聚合类型，例如 user
+    aggregate_id    BIGINT                        NOT NULL, -- 聚合 ID，同一聚合的事件按 id 顺序投递
+    event_type      VARCHAR(64)                   NOT NULL, -- 事件类型，例如 user.upserted
+    payload         JSONB                         NOT NULL,
+    status          VARCHAR(16) DEFAULT 'pending' NOT NULL, -- pending: 待投递 done: 已投递 dead: 超过重试次数
+    attempts        INT         DEFAULT 0         NOT NULL, -- 已失败次数
+    last_error      TEXT,
+    next_attempt_at timestamptz DEFAULT now()     NOT NULL, -- 下次可投递时间，用于退避重试
+    created_at      timestamptz DEFAULT now()     NOT NULL,
+    processed_at    timestamptz
+);
+CREATE INDEX outbox_pending_idx ON outbox (aggregate_type, aggregate_id, id) WHERE status = 'pending';
+COMMENT
+    ON TABLE outbox IS '事务性发件箱，与业务数据在同一事务中写入，由后台任务投递到外部系统';
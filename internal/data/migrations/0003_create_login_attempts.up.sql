@@ -0,0 +1,15 @@
+CREATE TABLE login_attempts
+(
+    id            BIGSERIAL PRIMARY KEY,
+    username      VARCHAR(255)              NOT NULL, -- 尝试登录的用户名，可能不存在
+    user_id       INT REFERENCES users (id),          -- 用户存在时的 ID
+    ip            VARCHAR(64)               NOT NULL, -- 直连的对端 IP
+    forwarded_for TEXT        DEFAULT ''    NOT NULL, -- X-Forwarded-For 原始值，位于反向代理之后时用于追溯真实来源
+    user_agent    TEXT        DEFAULT ''    NOT NULL,
+    result        VARCHAR(32)               NOT NULL, -- success: 成功 invalid_password: 密码错误 unknown_user: 用户不存在 locked: 账号已锁定
+    created_at    timestamptz DEFAULT now() NOT NULL
+);
+CREATE INDEX login_attempts_username_idx ON login_attempts (username, created_at);
+CREATE INDEX login_attempts_ip_idx ON login_attempts (ip, created_at);
+COMMENT
+    ON TABLE login_attempts IS '本地账号登录记录，用于审计撞库等异常登录';
@@ -0,0 +1,23 @@
+CREATE TABLE user_mfa
+(
+    user_id        INT PRIMARY KEY REFERENCES users (id),
+    secret         BYTEA                     NOT NULL, -- AES-256-GCM 加密后的 TOTP 密钥
+    confirmed_at   timestamptz,                        -- 确认绑定的时间，为 NULL 表示尚未开启
+    last_used_step BIGINT      DEFAULT 0     NOT NULL, -- 最近一次通过校验的时间步，防止验证码被重放
+    created_at     timestamptz DEFAULT now() NOT NULL,
+    updated_at     timestamptz DEFAULT now() NOT NULL
+);
+COMMENT
+    ON TABLE user_mfa IS '用户的 TOTP 多因素认证密钥';
+
+CREATE TABLE mfa_recovery_codes
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INT                       NOT NULL REFERENCES users (id),
+    code_hash  VARCHAR(64)               NOT NULL, -- 恢复码的 SHA-256
+    used_at    timestamptz,                        -- 使用时间，为 NULL 表示未使用
+    created_at timestamptz DEFAULT now() NOT NULL
+);
+CREATE UNIQUE INDEX mfa_recovery_codes_user_code_idx ON mfa_recovery_codes (user_id, code_hash);
+COMMENT
+    ON TABLE mfa_recovery_codes IS 'MFA 一次性恢复码';
@@ -0,0 +1,17 @@
+ALTER TABLE users
+    ADD COLUMN email_verified_at timestamptz; -- 邮箱验证时间，为 NULL 表示未验证；修改邮箱后清空
+
+CREATE TABLE user_tokens
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INT                       NOT NULL REFERENCES users (id),
+    purpose    VARCHAR(32)               NOT NULL, -- password_reset、email_verification
+    token_hash VARCHAR(64) UNIQUE        NOT NULL, -- 令牌的 SHA-256
+    email      VARCHAR(255)              NOT NULL, -- 签发时的收件邮箱，用户修改邮箱后令牌失效
+    expires_at timestamptz               NOT NULL,
+    used_at    timestamptz,                        -- 使用或作废的时间，为 NULL 表示未使用
+    created_at timestamptz DEFAULT now() NOT NULL
+);
+CREATE INDEX user_tokens_user_purpose_idx ON user_tokens (user_id, purpose);
+COMMENT
+    ON TABLE user_tokens IS '重置密码和验证邮箱的一次性令牌';
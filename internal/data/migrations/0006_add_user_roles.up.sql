@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD COLUMN roles TEXT[] DEFAULT '{}' NOT NULL; -- 本地账号的角色，登录时写入会话，例如 {admin}
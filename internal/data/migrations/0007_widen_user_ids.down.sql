@@ -0,0 +1,12 @@
+ALTER TABLE user_tokens
+    ALTER COLUMN user_id TYPE INT;
+ALTER TABLE mfa_recovery_codes
+    ALTER COLUMN user_id TYPE INT;
+ALTER TABLE user_mfa
+    ALTER COLUMN user_id TYPE INT;
+ALTER TABLE login_attempts
+    ALTER COLUMN user_id TYPE INT;
+
+ALTER SEQUENCE users_id_seq AS INT;
+ALTER TABLE users
+    ALTER COLUMN id TYPE INT;
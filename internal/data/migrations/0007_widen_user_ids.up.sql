@@ -0,0 +1,13 @@
+-- users.id 与 API 中的 int64 保持一致，避免超过 INT 范围的 ID 被截断
+ALTER TABLE users
+    ALTER COLUMN id TYPE BIGINT;
+ALTER SEQUENCE users_id_seq AS BIGINT;
+
+ALTER TABLE login_attempts
+    ALTER COLUMN user_id TYPE BIGINT;
+ALTER TABLE user_mfa
+    ALTER COLUMN user_id TYPE BIGINT;
+ALTER TABLE mfa_recovery_codes
+    ALTER COLUMN user_id TYPE BIGINT;
+ALTER TABLE user_tokens
+    ALTER COLUMN user_id TYPE BIGINT;
@@ -0,0 +1,4 @@
+-- 已删除用户与现有用户同名时无法恢复全表唯一约束，需要先清理这些记录
+ALTER TABLE users
+    ADD CONSTRAINT users_username_key UNIQUE (username);
+DROP INDEX IF EXISTS users_username_active_idx;
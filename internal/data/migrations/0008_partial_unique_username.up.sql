@@ -0,0 +1,4 @@
+-- 用户名只在未删除的用户中唯一，软删除后可以重新注册同名账号
+CREATE UNIQUE INDEX users_username_active_idx ON users (username) WHERE deleted_at IS NULL;
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_username_key;
@@ -0,0 +1,12 @@
+// Package migrations 内嵌数据库迁移脚本
+//
+// 文件名格式为 <版本号>_<名称>.up.sql / <版本号>_<名称>.down.sql，版本号递增且不可修改已发布的脚本。
+// 迁移在已存在的数据库中执行，数据库本身需要预先创建：
+//
+//	CREATE DATABASE connect_example;
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
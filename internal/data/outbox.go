@@ -0,0 +1,318 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"connect-go-example/internal/biz"
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/data/models"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	outboxAggregateUser = "user"
+
+	outboxEventUserUpserted = "user.upserted" // payload 为 userDocument
+	outboxEventUserDeleted  = "user.deleted"  // payload 为 {"id": <id>}
+)
+
+// 投递任务的默认配置
+const (
+	defaultOutboxPollInterval = time.Second
+	defaultOutboxBatchSize    = 100
+	defaultOutboxMaxAttempts  = 10
+	defaultOutboxBaseBackoff  = time.Second
+	defaultOutboxMaxBackoff   = 5 * time.Minute
+)
+
+// insertUserUpserted 写入用户新增或更新事件，payload 是完整的索引文档
+func insertUserUpserted(ctx context.Context, q models.Querier, user *biz.UserInfo) error {
+	return insertOutboxEvent(ctx, q, user.ID, outboxEventUserUpserted, userDocument{
+		ID:        user.ID,
+		Username:  user.Username,
+		Nickname:  user.Nickname,
+		Email:     user.Email,
+		Avatar:    user.Avatar,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	})
+}
+
+// insertUserDeleted 写入用户删除事件
+func insertUserDeleted(ctx context.Context, q models.Querier, id int64) error {
+	return insertOutboxEvent(ctx, q, id, outboxEventUserDeleted, map[string]int64{"id": id})
+}
+
+func insertOutboxEvent(ctx context.Context, q models.Querier, aggregateID int64, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode outbox payload failed: %w", err)
+	}
+
+	return q.InsertOutboxEvent(ctx, models.InsertOutboxEventParams{
+		AggregateType: outboxAggregateUser,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       data,
+	})
+}
+
+// outboxPublisher 将发件箱事件投递到外部系统
+type outboxPublisher interface {
+	Publish(ctx context.Context, event models.Outbox) error
+}
+
+// OutboxRelay 后台轮询发件箱，将事件投递到 Elasticsearch
+// 同一聚合的事件按写入顺序投递；失败后按指数退避重试，超过最大次数后标记为死信
+type OutboxRelay struct {
+	db        beginner
+	publisher outboxPublisher
+	l         *zap.Logger
+
+	pollInterval time.Duration
+	batchSize    int32
+	maxAttempts  int32
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+
+	// processingLag 事件从写入到投递成功的耗时
+	processingLag metric.Float64Histogram
+	// pendingAge 最早一条待投递事件的等待时长（秒），由 Float64ObservableGauge 读取
+	pendingAge atomic.Uint64
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewOutboxRelay 创建投递任务，随应用启动和停止
+func NewOutboxRelay(lc fx.Lifecycle, data *Data, cfg *conf.Bootstrap, logger *zap.Logger) (*OutboxRelay, error) {
+	relay, err := newOutboxRelay(
+		data.db,
+		newUserSearchRepo(data.es, userIndexName(cfg), logger),
+		cfg.GetData().GetOutbox(),
+		logger,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.GetData().GetOutbox().GetDisabled() {
+		logger.Info("Outbox relay disabled")
+		return relay, nil
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			relay.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return relay.Stop(ctx)
+		},
+	})
+
+	return relay, nil
+}
+
+func newOutboxRelay(db beginner, publisher outboxPublisher, cfg *conf.Data_Outbox, logger *zap.Logger) (*OutboxRelay, error) {
+	r := &OutboxRelay{
+		db:           db,
+		publisher:    publisher,
+		l:            logger,
+		pollInterval: defaultOutboxPollInterval,
+		batchSize:    defaultOutboxBatchSize,
+		maxAttempts:  defaultOutboxMaxAttempts,
+		baseBackoff:  defaultOutboxBaseBackoff,
+		maxBackoff:   defaultOutboxMaxBackoff,
+	}
+	if v := cfg.GetPollInterval(); v > 0 {
+		r.pollInterval = time.Duration(v) * time.Second
+	}
+	if v := cfg.GetBatchSize(); v > 0 {
+		r.batchSize = v
+	}
+	if v := cfg.GetMaxAttempts(); v > 0 {
+		r.maxAttempts = v
+	}
+	if v := cfg.GetBaseBackoff(); v > 0 {
+		r.baseBackoff = time.Duration(v) * time.Second
+	}
+	if v := cfg.GetMaxBackoff(); v > 0 {
+		r.maxBackoff = time.Duration(v) * time.Second
+	}
+
+	meter := otel.GetMeterProvider().Meter("github.com/sunmery/ecommerce/backend/data")
+
+	lag, err := meter.Float64Histogram(
+		"outbox.processing.lag",
+		metric.WithDescription("Time between an outbox event being written and being published"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init histogram: %w", err)
+	}
+	r.processingLag = lag
+
+	_, err = meter.Float64ObservableGauge(
+		"outbox.pending.age",
+		metric.WithDescription("Age of the oldest pending outbox event"),
+		metric.WithUnit("s"),
+		metric.WithFloat64Callback(func(ctx context.Context, o metric.Float64Observer) error {
+			o.Observe(math.Float64frombits(r.pendingAge.Load()))
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init gauge: %w", err)
+	}
+
+	return r, nil
+}
+
+// Start 启动后台投递循环
+func (r *OutboxRelay) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.run(ctx)
+	r.l.Info("Outbox relay started", zap.Duration("poll_interval", r.pollInterval))
+}
+
+// Stop 停止投递循环并等待当前批次结束
+func (r *OutboxRelay) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+
+	select {
+	case <-r.done:
+		r.l.Info("Outbox relay stopped")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *OutboxRelay) run(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.relayOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.l.Error("Outbox relay failed", zap.Error(err))
+		}
+
+		// 本轮有事件时立即继续，直到发件箱清空
+		if n > 0 && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relayOnce 在一个事务中领取并投递一批事件，返回领取的事件数
+func (r *OutboxRelay) relayOnce(ctx context.Context) (int, error) {
+	var n int
+	err := inTx(ctx, r.db, func(q models.Querier) error {
+		var err error
+		n, err = r.processBatch(ctx, q)
+		return err
+	})
+	return n, err
+}
+
+func (r *OutboxRelay) processBatch(ctx context.Context, q models.Querier) (int, error) {
+	events, err := q.ClaimOutboxEvents(ctx, r.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("claim outbox events failed: %w", err)
+	}
+
+	for _, event := range events {
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			if err := r.fail(ctx, q, event, err); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		if err := q.MarkOutboxEventDone(ctx, event.ID); err != nil {
+			return 0, fmt.Errorf("mark outbox event %d done failed: %w", event.ID, err)
+		}
+		r.processingLag.Record(ctx, time.Since(event.CreatedAt).Seconds(),
+			metric.WithAttributes(attribute.String("event_type", event.EventType)),
+		)
+	}
+
+	age, err := q.GetOutboxPendingAge(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get outbox pending age failed: %w", err)
+	}
+	r.pendingAge.Store(math.Float64bits(age))
+
+	return len(events), nil
+}
+
+// fail 记录一次投递失败，达到最大尝试次数时标记为死信
+func (r *OutboxRelay) fail(ctx context.Context, q models.Querier, event models.Outbox, cause error) error {
+	attempts := event.Attempts + 1
+	fields := []zap.Field{
+		zap.Int64("id", event.ID),
+		zap.String("event_type", event.EventType),
+		zap.Int64("aggregate_id", event.AggregateID),
+		zap.Int32("attempts", attempts),
+		zap.Error(cause),
+	}
+
+	if attempts >= r.maxAttempts {
+		r.l.Error("Outbox event moved to dead letter", fields...)
+		err := q.MarkOutboxEventDead(ctx, models.MarkOutboxEventDeadParams{
+			LastError: cause.Error(),
+			ID:        event.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("mark outbox event %d dead failed: %w", event.ID, err)
+		}
+		return nil
+	}
+
+	backoff := r.backoff(attempts)
+	r.l.Warn("Outbox event publish failed, will retry", append(fields, zap.Duration("backoff", backoff))...)
+	err := q.MarkOutboxEventRetry(ctx, models.MarkOutboxEventRetryParams{
+		LastError:     cause.Error(),
+		NextAttemptAt: time.Now().Add(backoff),
+		ID:            event.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("mark outbox event %d retry failed: %w", event.ID, err)
+	}
+	return nil
+}
+
+// backoff 第 attempts 次失败后的退避时间：baseBackoff * 2^(attempts-1)，不超过 maxBackoff
+func (r *OutboxRelay) backoff(attempts int32) time.Duration {
+	d := r.baseBackoff
+	for i := int32(1); i < attempts && d < r.maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, r.maxBackoff)
+}
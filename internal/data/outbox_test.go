@@ -0,0 +1,256 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"connect-go-example/internal/biz"
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/data/models"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+// fakeOutboxQuerier 是基于内存的发件箱，只实现投递任务用到的查询
+type fakeOutboxQuerier struct {
+	models.Querier
+	events map[int64]*models.Outbox
+}
+
+func (q *fakeOutboxQuerier) add(id, aggregateID int64, eventType string) {
+	q.events[id] = &models.Outbox{
+		ID:            id,
+		AggregateType: outboxAggregateUser,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Status:        "pending",
+		CreatedAt:     time.Now(),
+	}
+}
+
+func (q *fakeOutboxQuerier) ClaimOutboxEvents(ctx context.Context, batchSize int32) ([]models.Outbox, error) {
+	ids := make([]int64, 0, len(q.events))
+	for id := range q.events {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var claimed []models.Outbox
+	blocked := map[int64]bool{}
+	for _, id := range ids {
+		event := q.events[id]
+		if event.Status != "pending" {
+			continue
+		}
+		// 同一聚合只取最早的待投递事件
+		if blocked[event.AggregateID] {
+			continue
+		}
+		blocked[event.AggregateID] = true
+		if event.NextAttemptAt.After(time.Now()) || len(claimed) >= int(batchSize) {
+			continue
+		}
+		claimed = append(claimed, *event)
+	}
+	return claimed, nil
+}
+
+func (q *fakeOutboxQuerier) MarkOutboxEventDone(ctx context.Context, id int64) error {
+	q.events[id].Status = "done"
+	return nil
+}
+
+func (q *fakeOutboxQuerier) MarkOutboxEventRetry(ctx context.Context, arg models.MarkOutboxEventRetryParams) error {
+	event := q.events[arg.ID]
+	event.Attempts++
+	event.LastError = &arg.LastError
+	event.NextAttemptAt = arg.NextAttemptAt
+	return nil
+}
+
+func (q *fakeOutboxQuerier) MarkOutboxEventDead(ctx context.Context, arg models.MarkOutboxEventDeadParams) error {
+	event := q.events[arg.ID]
+	event.Status = "dead"
+	event.Attempts++
+	event.LastError = &arg.LastError
+	return nil
+}
+
+func (q *fakeOutboxQuerier) GetOutboxPendingAge(ctx context.Context) (float64, error) {
+	return 0, nil
+}
+
+// fakePublisher 记录投递顺序，failures 指定每个事件需要失败的次数
+type fakePublisher struct {
+	published []int64
+	failures  map[int64]int
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, event models.Outbox) error {
+	if p.failures[event.ID] > 0 {
+		p.failures[event.ID]--
+		return errors.New("elasticsearch unavailable")
+	}
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+// OutboxRelayTestSuite 是 OutboxRelay 的测试套件
+type OutboxRelayTestSuite struct {
+	suite.Suite
+	q         *fakeOutboxQuerier
+	publisher *fakePublisher
+	relay     *OutboxRelay
+}
+
+func (suite *OutboxRelayTestSuite) SetupTest() {
+	suite.q = &fakeOutboxQuerier{events: map[int64]*models.Outbox{}}
+	suite.publisher = &fakePublisher{failures: map[int64]int{}}
+
+	relay, err := newOutboxRelay(nil, suite.publisher, &conf.Data_Outbox{MaxAttempts: 3}, zap.NewNop())
+	require.NoError(suite.T(), err)
+	// 配置的最小单位是秒，测试中直接缩短退避时间
+	relay.baseBackoff = time.Millisecond
+	relay.maxBackoff = time.Millisecond
+	suite.relay = relay
+}
+
+// drain 反复投递直到没有可领取的事件，等待退避时间过去
+func (suite *OutboxRelayTestSuite) drain() {
+	for range 10 {
+		time.Sleep(2 * time.Millisecond)
+		if _, err := suite.relay.processBatch(context.Background(), suite.q); err != nil {
+			suite.T().Fatal(err)
+		}
+	}
+}
+
+func (suite *OutboxRelayTestSuite) TestOrderedPerAggregate() {
+	suite.q.add(1, 100, outboxEventUserUpserted)
+	suite.q.add(2, 200, outboxEventUserUpserted)
+	suite.q.add(3, 100, outboxEventUserDeleted)
+
+	n, err := suite.relay.processBatch(context.Background(), suite.q)
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, n)
+	assert.Equal(suite.T(), []int64{1, 2}, suite.publisher.published)
+
+	suite.drain()
+	assert.Equal(suite.T(), []int64{1, 2, 3}, suite.publisher.published)
+}
+
+func (suite *OutboxRelayTestSuite) TestRetryBlocksLaterEvents() {
+	suite.q.add(1, 100, outboxEventUserUpserted)
+	suite.q.add(2, 100, outboxEventUserUpserted)
+	suite.publisher.failures[1] = 1
+
+	_, err := suite.relay.processBatch(context.Background(), suite.q)
+	require.NoError(suite.T(), err)
+
+	assert.Empty(suite.T(), suite.publisher.published)
+	assert.Equal(suite.T(), int32(1), suite.q.events[1].Attempts)
+	assert.Equal(suite.T(), "elasticsearch unavailable", *suite.q.events[1].LastError)
+
+	suite.drain()
+	assert.Equal(suite.T(), []int64{1, 2}, suite.publisher.published)
+}
+
+func (suite *OutboxRelayTestSuite) TestDeadLetter() {
+	suite.q.add(1, 100, outboxEventUserUpserted)
+	suite.q.add(2, 100, outboxEventUserDeleted)
+	suite.publisher.failures[1] = 10
+
+	suite.drain()
+
+	assert.Equal(suite.T(), "dead", suite.q.events[1].Status)
+	assert.Equal(suite.T(), int32(3), suite.q.events[1].Attempts)
+	// 死信不再阻塞同一聚合的后续事件
+	assert.Equal(suite.T(), []int64{2}, suite.publisher.published)
+}
+
+func (suite *OutboxRelayTestSuite) TestConfigInSeconds() {
+	relay, err := newOutboxRelay(nil, suite.publisher, &conf.Data_Outbox{
+		PollInterval: 2,
+		BaseBackoff:  3,
+		MaxBackoff:   60,
+	}, zap.NewNop())
+	require.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), 2*time.Second, relay.pollInterval)
+	assert.Equal(suite.T(), 3*time.Second, relay.baseBackoff)
+	assert.Equal(suite.T(), time.Minute, relay.maxBackoff)
+}
+
+func (suite *OutboxRelayTestSuite) TestBackoff() {
+	suite.relay.baseBackoff = time.Second
+	suite.relay.maxBackoff = 5 * time.Second
+
+	assert.Equal(suite.T(), time.Second, suite.relay.backoff(1))
+	assert.Equal(suite.T(), 2*time.Second, suite.relay.backoff(2))
+	assert.Equal(suite.T(), 4*time.Second, suite.relay.backoff(3))
+	assert.Equal(suite.T(), 5*time.Second, suite.relay.backoff(4))
+	assert.Equal(suite.T(), 5*time.Second, suite.relay.backoff(30))
+}
+
+// 运行测试套件
+func TestOutboxRelayTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxRelayTestSuite))
+}
+
+// TestUserRepo_CreateUserWritesOutbox 用户和发件箱事件在同一事务中写入
+func TestUserRepo_CreateUserWritesOutbox(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery("-- name: CreateUser").
+		WithArgs("alice", "hash", "salt").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "username", "nickname", "email", "avatar", "created_at", "updated_at", "email_verified_at"}).
+			AddRow(int64(1), "alice", "", "", "", now, now, pgtype.Timestamptz{}))
+	mock.ExpectExec("-- name: InsertOutboxEvent").
+		WithArgs(outboxAggregateUser, int64(1), outboxEventUserUpserted, pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	repo := &userRepo{db: mock, l: zap.NewNop()}
+	user, err := repo.CreateUser(context.Background(), "alice", biz.UserCredential{PasswordHash: "hash", Salt: "salt"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), user.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUserRepo_CreateUserRollsBack 发件箱写入失败时回滚用户的创建
+func TestUserRepo_CreateUserRollsBack(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery("-- name: CreateUser").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "username", "nickname", "email", "avatar", "created_at", "updated_at", "email_verified_at"}).
+			AddRow(int64(1), "alice", "", "", "", now, now, pgtype.Timestamptz{}))
+	mock.ExpectExec("-- name: InsertOutboxEvent").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnError(errors.New("disk full"))
+	mock.ExpectRollback()
+
+	repo := &userRepo{db: mock, l: zap.NewNop()}
+	_, err = repo.CreateUser(context.Background(), "alice", biz.UserCredential{PasswordHash: "hash", Salt: "salt"})
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -6,9 +6,190 @@ RETURNING *;
 -- name: CreateUser :one
 INSERT INTO users (username, password_hash, salt)
 VALUES ($1, $2, $3)
-RETURNING id, username, password_hash, salt, created_at, updated_at;
+RETURNING id, username, nickname, email, avatar, created_at, updated_at, email_verified_at;
 
 -- name: GetUserByName :one
-SELECT username, salt, id, password_hash
+SELECT username, salt, id, password_hash, roles
 FROM users
-WHERE username = @username;
+WHERE username = @username
+  AND deleted_at IS NULL;
+
+-- name: GetUser :one
+SELECT id, username, nickname, email, avatar, created_at, updated_at, email_verified_at
+FROM users
+WHERE id = @id
+  AND deleted_at IS NULL;
+
+-- name: UpdateUser :one
+-- 参数为 NULL 的字段保持原值，用于按 FieldMask 部分更新；邮箱变化时清除验证状态
+UPDATE users
+SET nickname          = COALESCE(sqlc.narg(nickname), nickname),
+    email             = COALESCE(sqlc.narg(email), email),
+    avatar            = COALESCE(sqlc.narg(avatar), avatar),
+    email_verified_at = CASE WHEN email = COALESCE(sqlc.narg(email), email) THEN email_verified_at END,
+    updated_at        = now()
+WHERE id = @id
+  AND deleted_at IS NULL
+RETURNING id, username, nickname, email, avatar, created_at, updated_at, email_verified_at;
+
+-- name: DeleteUser :execrows
+UPDATE users
+SET deleted_at = now(),
+    updated_at = now()
+WHERE id = @id
+  AND deleted_at IS NULL;
+
+-- name: ListUsers :many
+-- 基于主键的游标分页，after_id 为上一页最后一条记录的 ID
+SELECT id, username, nickname, email, avatar, created_at, updated_at, email_verified_at
+FROM users
+WHERE id > @after_id
+  AND deleted_at IS NULL
+ORDER BY id
+LIMIT @page_size;
+
+-- name: ListUsersByVerifiedEmail :many
+SELECT id, username, email
+FROM users
+WHERE email = @email
+  AND email_verified_at IS NOT NULL
+  AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: UpdateUserPassword :execrows
+-- email 必须与签发令牌时的邮箱一致，令牌签发后修改过邮箱时影响行数为 0
+UPDATE users
+SET password_hash = @password_hash,
+    salt          = @salt,
+    updated_at    = now()
+WHERE id = @id
+  AND email = @email
+  AND deleted_at IS NULL;
+
+-- name: VerifyUserEmail :execrows
+-- 验证状态不属于搜索索引中的资料，不更新 updated_at
+UPDATE users
+SET email_verified_at = now()
+WHERE id = @id
+  AND email = @email
+  AND deleted_at IS NULL;
+
+-- name: InsertLoginAttempt :exec
+INSERT INTO login_attempts (username, user_id, ip, forwarded_for, user_agent, result)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetUserMFA :one
+SELECT user_id, secret, confirmed_at, last_used_step
+FROM user_mfa
+WHERE user_id = @user_id;
+
+-- name: UpsertPendingUserMFA :execrows
+-- 写入待确认的密钥，已开启 MFA 时不覆盖（影响行数为 0）
+INSERT INTO user_mfa (user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+    SET secret         = excluded.secret,
+        last_used_step = 0,
+        updated_at     = now()
+WHERE user_mfa.confirmed_at IS NULL;
+
+-- name: ConfirmUserMFA :execrows
+UPDATE user_mfa
+SET confirmed_at   = now(),
+    last_used_step = @last_used_step,
+    updated_at     = now()
+WHERE user_id = @user_id
+  AND confirmed_at IS NULL;
+
+-- name: UseUserMFAStep :execrows
+-- 只接受比上次更新的时间步，影响行数为 0 表示验证码已被使用
+UPDATE user_mfa
+SET last_used_step = @last_used_step,
+    updated_at     = now()
+WHERE user_id = @user_id
+  AND confirmed_at IS NOT NULL
+  AND last_used_step < @last_used_step;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE
+FROM mfa_recovery_codes
+WHERE user_id = @user_id;
+
+-- name: InsertRecoveryCode :exec
+INSERT INTO mfa_recovery_codes (user_id, code_hash)
+VALUES ($1, $2);
+
+-- name: RedeemRecoveryCode :execrows
+UPDATE mfa_recovery_codes
+SET used_at = now()
+WHERE user_id = @user_id
+  AND code_hash = @code_hash
+  AND used_at IS NULL;
+
+-- name: InvalidateUserTokens :exec
+-- 作废用户尚未使用的同类令牌，只有最新签发的令牌有效
+UPDATE user_tokens
+SET used_at = now()
+WHERE user_id = @user_id
+  AND purpose = @purpose
+  AND used_at IS NULL;
+
+-- name: InsertUserToken :exec
+INSERT INTO user_tokens (user_id, purpose, token_hash, email, expires_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ConsumeUserToken :one
+-- 标记令牌已使用并返回所属用户；令牌不存在、已使用或已过期时没有返回行
+UPDATE user_tokens
+SET used_at = now()
+WHERE token_hash = @token_hash
+  AND purpose = @purpose
+  AND used_at IS NULL
+  AND expires_at > now()
+RETURNING user_id, email;
+
+-- name: InsertOutboxEvent :exec
+INSERT INTO outbox (aggregate_type, aggregate_id, event_type, payload)
+VALUES ($1, $2, $3, $4);
+
+-- name: ClaimOutboxEvents :many
+-- 取出到期的待投递事件并加锁；同一聚合只取最早的一条，保证按顺序投递
+SELECT o.id, o.aggregate_type, o.aggregate_id, o.event_type, o.payload, o.status, o.attempts, o.last_error, o.next_attempt_at, o.created_at, o.processed_at
+FROM outbox o
+WHERE o.status = 'pending'
+  AND o.next_attempt_at <= now()
+  AND NOT EXISTS (SELECT 1
+                  FROM outbox p
+                  WHERE p.aggregate_type = o.aggregate_type
+                    AND p.aggregate_id = o.aggregate_id
+                    AND p.status = 'pending'
+                    AND p.id < o.id)
+ORDER BY o.id
+LIMIT @batch_size FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxEventDone :exec
+UPDATE outbox
+SET status       = 'done',
+    processed_at = now()
+WHERE id = @id;
+
+-- name: MarkOutboxEventRetry :exec
+UPDATE outbox
+SET attempts        = attempts + 1,
+    last_error      = @last_error::text,
+    next_attempt_at = @next_attempt_at
+WHERE id = @id;
+
+-- name: MarkOutboxEventDead :exec
+UPDATE outbox
+SET status       = 'dead',
+    attempts     = attempts + 1,
+    last_error   = @last_error::text,
+    processed_at = now()
+WHERE id = @id;
+
+-- name: GetOutboxPendingAge :one
+-- 最早一条待投递事件的等待时长（秒），没有待投递事件时为 0
+SELECT COALESCE(EXTRACT(EPOCH FROM now() - MIN(created_at)), 0)::float8 AS age_seconds
+FROM outbox
+WHERE status = 'pending';
@@ -0,0 +1,288 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"connect-go-example/internal/biz"
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/data/models"
+
+	"github.com/elastic/go-elasticsearch/v9"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types/enums/textquerytype"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// defaultUserIndex 未配置 user_index 时使用的索引名称
+const defaultUserIndex = "users"
+
+// 后台创建索引失败时的重试间隔
+const (
+	defaultEnsureIndexBaseBackoff = time.Second
+	defaultEnsureIndexMaxBackoff  = time.Minute
+)
+
+// searchFields 参与搜索和高亮的字段
+var searchFields = []string{"username", "nickname", "email"}
+
+var _ biz.UserSearchRepo = (*userSearchRepo)(nil)
+
+// userDocument 用户在 Elasticsearch 中的文档结构
+type userDocument struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	Nickname  string    `json:"nickname"`
+	Email     string    `json:"email"`
+	Avatar    string    `json:"avatar"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type userSearchRepo struct {
+	es    *elasticsearch.TypedClient
+	index string
+	l     *zap.Logger
+
+	// ready 为 true 表示已确认索引存在，此前搜索返回 ErrSearchUnavailable
+	ready       atomic.Bool
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewUserSearchRepo 创建用户检索仓库，启动后在后台确保索引及其 mapping 存在
+// Elasticsearch 不可用时不阻塞启动，只有搜索接口暂时返回 Unavailable
+func NewUserSearchRepo(lc fx.Lifecycle, data *Data, cfg *conf.Bootstrap, logger *zap.Logger) biz.UserSearchRepo {
+	repo := newUserSearchRepo(data.es, userIndexName(cfg), logger)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			repo.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return repo.Stop(ctx)
+		},
+	})
+
+	return repo
+}
+
+// userIndexName 返回配置的用户索引名称
+func userIndexName(cfg *conf.Bootstrap) string {
+	if index := cfg.GetSearch().GetElasticSearch().GetUserIndex(); index != "" {
+		return index
+	}
+	return defaultUserIndex
+}
+
+func newUserSearchRepo(es *elasticsearch.TypedClient, index string, logger *zap.Logger) *userSearchRepo {
+	return &userSearchRepo{
+		es:          es,
+		index:       index,
+		l:           logger,
+		baseBackoff: defaultEnsureIndexBaseBackoff,
+		maxBackoff:  defaultEnsureIndexMaxBackoff,
+	}
+}
+
+// Start 在后台创建索引，失败时按指数退避重试直到成功
+func (r *userSearchRepo) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.run(ctx)
+}
+
+// Stop 停止重试并等待当前请求结束
+func (r *userSearchRepo) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *userSearchRepo) run(ctx context.Context) {
+	defer close(r.done)
+
+	backoff := r.baseBackoff
+	for {
+		err := r.ensureIndex(ctx)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		r.l.Warn("Ensure Elasticsearch index failed, will retry",
+			zap.String("index", r.index), zap.Duration("backoff", backoff), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, r.maxBackoff)
+	}
+}
+
+// ensureIndex 索引不存在时按 userMapping 创建，成功后将仓库标记为就绪
+func (r *userSearchRepo) ensureIndex(ctx context.Context) error {
+	if r.ready.Load() {
+		return nil
+	}
+
+	exists, err := r.es.Indices.Exists(r.index).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("check index %s failed: %w", r.index, err)
+	}
+	if !exists {
+		if _, err := r.es.Indices.Create(r.index).Mappings(userMapping()).Do(ctx); err != nil {
+			return fmt.Errorf("create index %s failed: %w", r.index, err)
+		}
+		r.l.Info("Elasticsearch index created", zap.String("index", r.index))
+	}
+
+	r.ready.Store(true)
+	return nil
+}
+
+// userMapping 用户索引的 mapping
+// 搜索字段使用 search_as_you_type，同时支持前缀匹配和普通全文匹配
+func userMapping() *types.TypeMapping {
+	mapping := types.NewTypeMapping()
+	mapping.Properties = map[string]types.Property{
+		"id":         types.NewLongNumberProperty(),
+		"username":   types.NewSearchAsYouTypeProperty(),
+		"nickname":   types.NewSearchAsYouTypeProperty(),
+		"email":      types.NewSearchAsYouTypeProperty(),
+		"avatar":     types.NewKeywordProperty(),
+		"created_at": types.NewDateProperty(),
+		"updated_at": types.NewDateProperty(),
+	}
+	return mapping
+}
+
+// SearchUsers 组合前缀匹配（bool_prefix）和模糊匹配（fuzziness AUTO），任一命中即可
+func (r *userSearchRepo) SearchUsers(ctx context.Context, query string, offset, limit int) ([]*biz.UserSearchHit, int64, error) {
+	if !r.ready.Load() {
+		return nil, 0, biz.ErrSearchUnavailable
+	}
+
+	var prefixFields []string
+	for _, field := range searchFields {
+		prefixFields = append(prefixFields, field, field+"._2gram", field+"._3gram")
+	}
+
+	highlight := types.NewHighlight()
+	for _, field := range searchFields {
+		highlight.Fields = append(highlight.Fields, map[string]types.HighlightField{field: *types.NewHighlightField()})
+	}
+
+	res, err := r.es.Search().Index(r.index).Request(&search.Request{
+		Query: &types.Query{
+			Bool: &types.BoolQuery{
+				Should: []types.Query{
+					{MultiMatch: &types.MultiMatchQuery{
+						Query:  query,
+						Type:   &textquerytype.Boolprefix,
+						Fields: prefixFields,
+					}},
+					{MultiMatch: &types.MultiMatchQuery{
+						Query:     query,
+						Fields:    []string{"username^2", "nickname", "email"},
+						Fuzziness: "AUTO",
+					}},
+				},
+				MinimumShouldMatch: 1,
+			},
+		},
+		Highlight:      highlight,
+		From:           &offset,
+		Size:           &limit,
+		TrackTotalHits: true,
+	}).Do(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("search users failed: %w", err)
+	}
+
+	hits := make([]*biz.UserSearchHit, 0, len(res.Hits.Hits))
+	for _, hit := range res.Hits.Hits {
+		var doc userDocument
+		if err := json.Unmarshal(hit.Source_, &doc); err != nil {
+			return nil, 0, fmt.Errorf("decode user document failed: %w", err)
+		}
+
+		// 文档缺少 id 字段时退回到文档 _id
+		if doc.ID == 0 && hit.Id_ != nil {
+			doc.ID, _ = strconv.ParseInt(*hit.Id_, 10, 64)
+		}
+
+		var score float64
+		if hit.Score_ != nil {
+			score = float64(*hit.Score_)
+		}
+		hits = append(hits, &biz.UserSearchHit{
+			User: &biz.UserInfo{
+				ID:        doc.ID,
+				Username:  doc.Username,
+				Nickname:  doc.Nickname,
+				Email:     doc.Email,
+				Avatar:    doc.Avatar,
+				CreatedAt: doc.CreatedAt,
+				UpdatedAt: doc.UpdatedAt,
+			},
+			Score:      score,
+			Highlights: hit.Highlight,
+		})
+	}
+
+	var total int64
+	if res.Hits.Total != nil {
+		total = res.Hits.Total.Value
+	}
+	return hits, total, nil
+}
+
+// Publish 将用户相关的发件箱事件同步到索引，实现 outboxPublisher
+// 先确保索引存在，避免写入文档时 Elasticsearch 按动态 mapping 自动创建索引
+func (r *userSearchRepo) Publish(ctx context.Context, event models.Outbox) error {
+	if err := r.ensureIndex(ctx); err != nil {
+		return err
+	}
+
+	id := strconv.FormatInt(event.AggregateID, 10)
+
+	switch event.EventType {
+	case outboxEventUserUpserted:
+		var doc userDocument
+		if err := json.Unmarshal(event.Payload, &doc); err != nil {
+			return fmt.Errorf("decode user document failed: %w", err)
+		}
+		if _, err := r.es.Index(r.index).Id(id).Document(doc).Do(ctx); err != nil {
+			return fmt.Errorf("index user %s failed: %w", id, err)
+		}
+	case outboxEventUserDeleted:
+		// 文档不存在（404）时客户端不会返回错误
+		if _, err := r.es.Delete(r.index, id).Do(ctx); err != nil {
+			return fmt.Errorf("delete user %s from index failed: %w", id, err)
+		}
+	default:
+		return fmt.Errorf("unknown event type %q", event.EventType)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"connect-go-example/internal/biz"
+	"connect-go-example/internal/data/models"
+
+	"github.com/elastic/go-elasticsearch/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+// fakeElasticsearch 是 Elasticsearch HTTP API 的本地替身，记录收到的请求
+type fakeElasticsearch struct {
+	server *httptest.Server
+
+	mu          sync.Mutex
+	indexExists bool
+	mapping     map[string]any
+	lastSearch  map[string]any
+	searchBody  string
+	// unavailable 为接下来多少个请求返回 503
+	unavailable int
+	// indexedBeforeCreate 写入文档时索引是否还不存在
+	indexedBeforeCreate bool
+	documents           map[string]map[string]any
+}
+
+func newFakeElasticsearch(t *testing.T, index string) *fakeElasticsearch {
+	f := &fakeElasticsearch{documents: map[string]map[string]any{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+index, func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		switch r.Method {
+		case http.MethodHead:
+			if !f.indexExists {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case http.MethodPut:
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			f.mapping, _ = body["mappings"].(map[string]any)
+			f.indexExists = true
+			_, _ = io.WriteString(w, `{"acknowledged":true,"shards_acknowledged":true,"index":"`+index+`"}`)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/"+index+"/_search", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		f.lastSearch = nil
+		_ = json.NewDecoder(r.Body).Decode(&f.lastSearch)
+		_, _ = io.WriteString(w, f.searchBody)
+	})
+	mux.HandleFunc("PUT /"+index+"/_doc/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if !f.indexExists {
+			f.indexedBeforeCreate = true
+		}
+		var doc map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&doc)
+		f.documents[r.PathValue("id")] = doc
+		_, _ = io.WriteString(w, `{"_index":"`+index+`","_id":"`+r.PathValue("id")+`","_version":1,"result":"created","_shards":{"total":1,"successful":1,"failed":0},"_seq_no":0,"_primary_term":1}`)
+	})
+
+	// 客户端会校验响应中的产品头
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		f.mu.Lock()
+		unavailable := f.unavailable > 0
+		if unavailable {
+			f.unavailable--
+		}
+		f.mu.Unlock()
+		if unavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+// UserSearchRepoTestSuite 是 userSearchRepo 的测试套件
+type UserSearchRepoTestSuite struct {
+	suite.Suite
+	es   *fakeElasticsearch
+	repo *userSearchRepo
+}
+
+func (suite *UserSearchRepoTestSuite) SetupTest() {
+	suite.es = newFakeElasticsearch(suite.T(), defaultUserIndex)
+
+	client, err := elasticsearch.NewTypedClient(elasticsearch.Config{
+		Addresses: []string{suite.es.server.URL},
+	})
+	require.NoError(suite.T(), err)
+
+	suite.repo = newUserSearchRepo(client, defaultUserIndex, zap.NewNop())
+}
+
+func (suite *UserSearchRepoTestSuite) TestEnsureIndex_Creates() {
+	require.NoError(suite.T(), suite.repo.ensureIndex(context.Background()))
+
+	require.True(suite.T(), suite.es.indexExists)
+	assert.True(suite.T(), suite.repo.ready.Load())
+	properties := suite.es.mapping["properties"].(map[string]any)
+	username := properties["username"].(map[string]any)
+	assert.Equal(suite.T(), "search_as_you_type", username["type"])
+}
+
+func (suite *UserSearchRepoTestSuite) TestEnsureIndex_AlreadyExists() {
+	suite.es.indexExists = true
+
+	require.NoError(suite.T(), suite.repo.ensureIndex(context.Background()))
+
+	assert.Nil(suite.T(), suite.es.mapping)
+}
+
+func (suite *UserSearchRepoTestSuite) TestStart_RetriesUntilIndexCreated() {
+	// Elasticsearch 暂时不可用时不阻塞启动，在后台重试
+	suite.es.unavailable = 2
+	suite.repo.baseBackoff = time.Millisecond
+	suite.repo.Start()
+	defer suite.repo.Stop(context.Background())
+
+	assert.Eventually(suite.T(), suite.repo.ready.Load, 5*time.Second, time.Millisecond)
+	suite.es.mu.Lock()
+	defer suite.es.mu.Unlock()
+	assert.True(suite.T(), suite.es.indexExists)
+}
+
+func (suite *UserSearchRepoTestSuite) TestSearchUsers_IndexNotReady() {
+	_, _, err := suite.repo.SearchUsers(context.Background(), "ali", 0, 10)
+
+	assert.ErrorIs(suite.T(), err, biz.ErrSearchUnavailable)
+	assert.Nil(suite.T(), suite.es.lastSearch)
+}
+
+func (suite *UserSearchRepoTestSuite) TestPublish_CreatesIndexFirst() {
+	err := suite.repo.Publish(context.Background(), models.Outbox{
+		AggregateID: 7,
+		EventType:   outboxEventUserUpserted,
+		Payload:     []byte(`{"id":7,"username":"alice"}`),
+	})
+
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), suite.es.indexExists)
+	assert.False(suite.T(), suite.es.indexedBeforeCreate)
+	assert.Equal(suite.T(), "alice", suite.es.documents["7"]["username"])
+}
+
+func (suite *UserSearchRepoTestSuite) TestSearchUsers() {
+	suite.repo.ready.Store(true)
+	suite.es.searchBody = `{
+		"took": 1,
+		"timed_out": false,
+		"_shards": {"total": 1, "successful": 1, "skipped": 0, "failed": 0},
+		"hits": {
+			"total": {"value": 3, "relation": "eq"},
+			"max_score": 2.5,
+			"hits": [{
+				"_index": "users",
+				"_id": "7",
+				"_score": 2.5,
+				"_source": {"id": 7, "username": "alice", "nickname": "Alice", "email": "alice@example.com"},
+				"highlight": {"username": ["<em>ali</em>ce"]}
+			}, {
+				"_index": "users",
+				"_id": "9",
+				"_score": 1.2,
+				"_source": {"username": "alicia"}
+			}]
+		}
+	}`
+
+	hits, total, err := suite.repo.SearchUsers(context.Background(), "ali", 2, 2)
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(3), total)
+	require.Len(suite.T(), hits, 2)
+	assert.Equal(suite.T(), int64(7), hits[0].User.ID)
+	assert.Equal(suite.T(), "Alice", hits[0].User.Nickname)
+	assert.Equal(suite.T(), 2.5, hits[0].Score)
+	assert.Equal(suite.T(), []string{"<em>ali</em>ce"}, hits[0].Highlights["username"])
+	assert.Equal(suite.T(), int64(9), hits[1].User.ID)
+
+	req := suite.es.lastSearch
+	assert.EqualValues(suite.T(), 2, req["from"])
+	assert.EqualValues(suite.T(), 2, req["size"])
+	should := req["query"].(map[string]any)["bool"].(map[string]any)["should"].([]any)
+	require.Len(suite.T(), should, 2)
+	prefix := should[0].(map[string]any)["multi_match"].(map[string]any)
+	assert.Equal(suite.T(), "bool_prefix", prefix["type"])
+	fuzzy := should[1].(map[string]any)["multi_match"].(map[string]any)
+	assert.Equal(suite.T(), "AUTO", fuzzy["fuzziness"])
+}
+
+func (suite *UserSearchRepoTestSuite) TestSearchUsers_Error() {
+	suite.repo.ready.Store(true)
+	suite.es.server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = io.WriteString(w, `{"error":{"type":"search_phase_execution_exception","reason":"boom"},"status":500}`)
+	})
+
+	_, _, err := suite.repo.SearchUsers(context.Background(), "ali", 0, 10)
+
+	assert.Error(suite.T(), err)
+}
+
+// 运行测试套件
+func TestUserSearchRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(UserSearchRepoTestSuite))
+}
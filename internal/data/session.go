@@ -0,0 +1,289 @@
+package data
+
+import (
+	"connect-go-example/internal/biz"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	conf "connect-go-example/internal/conf/v1"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Redis 键设计：
+//
+//	session:<sha256(sid)>        HASH  会话，TTL = 会话有效期
+//	refresh:<sha256(token)>      HASH  刷新令牌，used 字段记录使用次数，TTL = 刷新令牌有效期
+//	session_family:<family>      HASH  会话族当前有效的 sid/refresh 哈希
+//	user_sessions:<user_id>      SET   用户的所有会话族，用于退出所有设备
+//
+// 令牌只以哈希形式落盘，Redis 泄露时无法直接冒用
+const (
+	sessionKeyPrefix       = "session:"
+	refreshKeyPrefix       = "refresh:"
+	sessionFamilyKeyPrefix = "session_family:"
+	userSessionsKeyPrefix  = "user_sessions:"
+
+	defaultSessionTTL = time.Hour
+	defaultRefreshTTL = 7 * 24 * time.Hour
+)
+
+// refreshScript 原子地消费刷新令牌
+// 返回 {0} 表示令牌不存在；{1, family} 表示首次使用；{2, family} 表示重复使用
+var refreshScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return {0}
+end
+local used = redis.call('HINCRBY', KEYS[1], 'used', 1)
+local family = redis.call('HGET', KEYS[1], 'family')
+if used > 1 then
+	return {2, family}
+end
+return {1, family}
+`)
+
+var _ biz.SessionRepo = (*sessionRepo)(nil)
+
+type sessionRepo struct {
+	rdb        *redis.Client
+	ttl        time.Duration
+	refreshTTL time.Duration
+	l          *zap.Logger
+}
+
+func NewSessionRepo(data *Data, cfg *conf.Bootstrap, logger *zap.Logger) biz.SessionRepo {
+	ttl, refreshTTL := defaultSessionTTL, defaultRefreshTTL
+	if s := cfg.GetAuth().GetSession(); s != nil {
+		if s.Ttl > 0 {
+			ttl = time.Duration(s.Ttl) * time.Second
+		}
+		if s.RefreshTtl > 0 {
+			refreshTTL = time.Duration(s.RefreshTtl) * time.Second
+		}
+	}
+
+	return &sessionRepo{
+		rdb:        data.rdb,
+		ttl:        ttl,
+		refreshTTL: refreshTTL,
+		l:          logger,
+	}
+}
+
+func (r *sessionRepo) CreateSession(ctx context.Context, user *biz.UserInfo) (*biz.Session, error) {
+	return r.issue(ctx, uuid.New().String(), user.ID, user.Username, user.Roles)
+}
+
+func (r *sessionRepo) GetSession(ctx context.Context, sessionID string) (*biz.Session, error) {
+	values, err := r.rdb.HGetAll(ctx, sessionKeyPrefix+hashToken(sessionID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("get session failed: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, biz.ErrSessionNotFound
+	}
+
+	userID, _ := strconv.ParseInt(values["user_id"], 10, 64)
+	expiresAt, _ := strconv.ParseInt(values["expires_at"], 10, 64)
+
+	return &biz.Session{
+		ID:        sessionID,
+		FamilyID:  values["family"],
+		UserID:    userID,
+		Username:  values["username"],
+		Roles:     splitRoles(values["roles"]),
+		ExpiresAt: time.Unix(expiresAt, 0),
+	}, nil
+}
+
+func (r *sessionRepo) RefreshSession(ctx context.Context, refreshToken string) (*biz.Session, error) {
+	res, err := refreshScript.Run(ctx, r.rdb, []string{refreshKeyPrefix + hashToken(refreshToken)}).Slice()
+	if err != nil {
+		return nil, fmt.Errorf("consume refresh token failed: %w", err)
+	}
+
+	status, _ := res[0].(int64)
+	switch status {
+	case 0:
+		return nil, biz.ErrInvalidRefreshToken
+	case 2:
+		family, _ := res[1].(string)
+		r.l.Warn("Refresh token reused, revoking session family", zap.String("family", family))
+		if err := r.revokeFamily(ctx, family); err != nil {
+			return nil, err
+		}
+		return nil, biz.ErrRefreshTokenReused
+	}
+
+	family, _ := res[1].(string)
+	current, err := r.rdb.HGetAll(ctx, sessionFamilyKeyPrefix+family).Result()
+	if err != nil {
+		return nil, fmt.Errorf("get session family failed: %w", err)
+	}
+	if len(current) == 0 {
+		// 会话族已被吊销（例如用户退出登录）
+		return nil, biz.ErrInvalidRefreshToken
+	}
+
+	// 旧会话立即失效，旧刷新令牌保留到过期以便检测重放
+	if err := r.rdb.Del(ctx, sessionKeyPrefix+current["sid"]).Err(); err != nil {
+		return nil, fmt.Errorf("delete old session failed: %w", err)
+	}
+
+	userID, _ := strconv.ParseInt(current["user_id"], 10, 64)
+	return r.issue(ctx, family, userID, current["username"], splitRoles(current["roles"]))
+}
+
+func (r *sessionRepo) RevokeSession(ctx context.Context, sessionID string) error {
+	session, err := r.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	return r.revokeFamily(ctx, session.FamilyID)
+}
+
+func (r *sessionRepo) RevokeUserSessions(ctx context.Context, userID int64) error {
+	key := userSessionsKeyPrefix + strconv.FormatInt(userID, 10)
+	families, err := r.rdb.SMembers(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("list user sessions failed: %w", err)
+	}
+
+	for _, family := range families {
+		if err := r.revokeFamily(ctx, family); err != nil {
+			return err
+		}
+	}
+
+	return r.rdb.Del(ctx, key).Err()
+}
+
+// issue 在会话族中签发新的会话 ID 和刷新令牌
+func (r *sessionRepo) issue(ctx context.Context, family string, userID int64, username string, roles []string) (*biz.Session, error) {
+	sessionID, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &biz.Session{
+		ID:               sessionID,
+		RefreshToken:     refreshToken,
+		FamilyID:         family,
+		UserID:           userID,
+		Username:         username,
+		Roles:            roles,
+		ExpiresAt:        now.Add(r.ttl),
+		RefreshExpiresAt: now.Add(r.refreshTTL),
+	}
+
+	sidHash, rtHash := hashToken(sessionID), hashToken(refreshToken)
+	uid := strconv.FormatInt(userID, 10)
+	userKey := userSessionsKeyPrefix + uid
+
+	_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, sessionKeyPrefix+sidHash,
+			"user_id", uid,
+			"username", username,
+			"roles", joinRoles(roles),
+			"family", family,
+			"expires_at", session.ExpiresAt.Unix(),
+		)
+		pipe.Expire(ctx, sessionKeyPrefix+sidHash, r.ttl)
+
+		pipe.HSet(ctx, refreshKeyPrefix+rtHash,
+			"user_id", uid,
+			"family", family,
+			"used", 0,
+		)
+		pipe.Expire(ctx, refreshKeyPrefix+rtHash, r.refreshTTL)
+
+		pipe.HSet(ctx, sessionFamilyKeyPrefix+family,
+			"user_id", uid,
+			"username", username,
+			"roles", joinRoles(roles),
+			"sid", sidHash,
+			"rt", rtHash,
+		)
+		pipe.Expire(ctx, sessionFamilyKeyPrefix+family, r.refreshTTL)
+
+		pipe.SAdd(ctx, userKey, family)
+		pipe.Expire(ctx, userKey, r.refreshTTL)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("store session failed: %w", err)
+	}
+
+	return session, nil
+}
+
+// revokeFamily 删除会话族当前的会话和刷新令牌
+func (r *sessionRepo) revokeFamily(ctx context.Context, family string) error {
+	if family == "" {
+		return errors.New("empty session family")
+	}
+
+	current, err := r.rdb.HGetAll(ctx, sessionFamilyKeyPrefix+family).Result()
+	if err != nil {
+		return fmt.Errorf("get session family failed: %w", err)
+	}
+	if len(current) == 0 {
+		return nil
+	}
+
+	_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx,
+			sessionKeyPrefix+current["sid"],
+			refreshKeyPrefix+current["rt"],
+			sessionFamilyKeyPrefix+family,
+		)
+		pipe.SRem(ctx, userSessionsKeyPrefix+current["user_id"], family)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("revoke session family failed: %w", err)
+	}
+
+	return nil
+}
+
+// joinRoles 角色以逗号分隔保存在 Redis HASH 中
+func joinRoles(roles []string) string {
+	return strings.Join(roles, ",")
+}
+
+func splitRoles(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// newToken 生成 256 位随机的不透明令牌
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate token failed: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
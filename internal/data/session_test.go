@@ -0,0 +1,146 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connect-go-example/internal/biz"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+// SessionRepoTestSuite 是 sessionRepo 的测试套件，使用 miniredis 代替真实 Redis
+type SessionRepoTestSuite struct {
+	suite.Suite
+	mr   *miniredis.Miniredis
+	repo *sessionRepo
+	ctx  context.Context
+}
+
+func (suite *SessionRepoTestSuite) SetupTest() {
+	suite.mr = miniredis.RunT(suite.T())
+	suite.repo = &sessionRepo{
+		rdb:        redis.NewClient(&redis.Options{Addr: suite.mr.Addr()}),
+		ttl:        time.Minute,
+		refreshTTL: time.Hour,
+		l:          zap.NewNop(),
+	}
+	suite.ctx = context.Background()
+}
+
+func (suite *SessionRepoTestSuite) createSession(userID int64) *biz.Session {
+	session, err := suite.repo.CreateSession(suite.ctx, &biz.UserInfo{ID: userID, Username: "alice", Roles: []string{"admin", "editor"}})
+	require.NoError(suite.T(), err)
+	return session
+}
+
+func (suite *SessionRepoTestSuite) TestCreateAndGetSession() {
+	session := suite.createSession(1)
+
+	got, err := suite.repo.GetSession(suite.ctx, session.ID)
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), got.UserID)
+	assert.Equal(suite.T(), "alice", got.Username)
+	assert.Equal(suite.T(), []string{"admin", "editor"}, got.Roles)
+	assert.Equal(suite.T(), session.FamilyID, got.FamilyID)
+
+	// 令牌只以哈希形式存储
+	for _, key := range suite.mr.Keys() {
+		assert.NotContains(suite.T(), key, session.ID)
+		assert.NotContains(suite.T(), key, session.RefreshToken)
+	}
+}
+
+func (suite *SessionRepoTestSuite) TestGetSession_Expired() {
+	session := suite.createSession(1)
+	suite.mr.FastForward(2 * time.Minute)
+
+	_, err := suite.repo.GetSession(suite.ctx, session.ID)
+
+	assert.ErrorIs(suite.T(), err, biz.ErrSessionNotFound)
+}
+
+func (suite *SessionRepoTestSuite) TestRefreshSession_Rotates() {
+	session := suite.createSession(1)
+
+	rotated, err := suite.repo.RefreshSession(suite.ctx, session.RefreshToken)
+
+	require.NoError(suite.T(), err)
+	assert.NotEqual(suite.T(), session.ID, rotated.ID)
+	assert.NotEqual(suite.T(), session.RefreshToken, rotated.RefreshToken)
+	assert.Equal(suite.T(), session.FamilyID, rotated.FamilyID)
+	assert.Equal(suite.T(), session.Roles, rotated.Roles)
+
+	// 旧会话立即失效
+	_, err = suite.repo.GetSession(suite.ctx, session.ID)
+	assert.ErrorIs(suite.T(), err, biz.ErrSessionNotFound)
+
+	got, err := suite.repo.GetSession(suite.ctx, rotated.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"admin", "editor"}, got.Roles)
+}
+
+func (suite *SessionRepoTestSuite) TestRefreshSession_ReuseRevokesFamily() {
+	session := suite.createSession(1)
+	rotated, err := suite.repo.RefreshSession(suite.ctx, session.RefreshToken)
+	require.NoError(suite.T(), err)
+
+	// 重放已使用过的刷新令牌
+	_, err = suite.repo.RefreshSession(suite.ctx, session.RefreshToken)
+	assert.ErrorIs(suite.T(), err, biz.ErrRefreshTokenReused)
+
+	// 整个会话族都被吊销
+	_, err = suite.repo.GetSession(suite.ctx, rotated.ID)
+	assert.ErrorIs(suite.T(), err, biz.ErrSessionNotFound)
+	_, err = suite.repo.RefreshSession(suite.ctx, rotated.RefreshToken)
+	assert.ErrorIs(suite.T(), err, biz.ErrInvalidRefreshToken)
+}
+
+func (suite *SessionRepoTestSuite) TestRefreshSession_UnknownToken() {
+	_, err := suite.repo.RefreshSession(suite.ctx, "unknown")
+
+	assert.ErrorIs(suite.T(), err, biz.ErrInvalidRefreshToken)
+}
+
+func (suite *SessionRepoTestSuite) TestRevokeSession() {
+	session := suite.createSession(1)
+	other := suite.createSession(1)
+
+	require.NoError(suite.T(), suite.repo.RevokeSession(suite.ctx, session.ID))
+
+	_, err := suite.repo.GetSession(suite.ctx, session.ID)
+	assert.ErrorIs(suite.T(), err, biz.ErrSessionNotFound)
+	_, err = suite.repo.RefreshSession(suite.ctx, session.RefreshToken)
+	assert.ErrorIs(suite.T(), err, biz.ErrInvalidRefreshToken)
+
+	// 其他设备上的会话不受影响
+	_, err = suite.repo.GetSession(suite.ctx, other.ID)
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *SessionRepoTestSuite) TestRevokeUserSessions() {
+	first := suite.createSession(1)
+	second := suite.createSession(1)
+	otherUser := suite.createSession(2)
+
+	require.NoError(suite.T(), suite.repo.RevokeUserSessions(suite.ctx, 1))
+
+	for _, s := range []*biz.Session{first, second} {
+		_, err := suite.repo.GetSession(suite.ctx, s.ID)
+		assert.ErrorIs(suite.T(), err, biz.ErrSessionNotFound)
+	}
+	_, err := suite.repo.GetSession(suite.ctx, otherUser.ID)
+	assert.NoError(suite.T(), err)
+}
+
+// 运行测试套件
+func TestSessionRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionRepoTestSuite))
+}
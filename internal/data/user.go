@@ -2,43 +2,199 @@ package data
 
 import (
 	"connect-go-example/internal/biz"
-	// "connect-go-example/internal/data/models"
+	"connect-go-example/internal/data/models"
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
 
 	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
+// pgUniqueViolation Postgres 唯一约束冲突的错误码
+const pgUniqueViolation = "23505"
+
 var _ biz.UserRepo = (*userRepo)(nil)
 
 type userRepo struct {
-	// queries *models.Queries
-	rdb  *redis.Client
-	auth *casdoorsdk.Client
-	l    *zap.Logger
+	db      beginner
+	queries models.Querier
+	rdb     *redis.Client
+	auth    *casdoorsdk.Client
+	l       *zap.Logger
 }
 
 func NewUserRepo(data *Data, logger *zap.Logger) biz.UserRepo {
 	return &userRepo{
-		// queries: models.New(data.db),
-		rdb:  data.rdb,
-		auth: data.auth,
-		l:    logger,
+		db:      data.db,
+		queries: models.New(data.db),
+		rdb:     data.rdb,
+		auth:    data.auth,
+		l:       logger,
 	}
 }
 
+// SignIn 使用授权码向 Casdoor 换取 token，并校验其中的 JWT 声明
 func (u userRepo) SignIn(ctx context.Context, req biz.SignInRequest) (*biz.SignInResponse, error) {
 	if u.auth == nil {
 		return nil, errors.New("auth client is nil")
 	}
-	// token, err := u.auth.GetOAuthToken(req.Code, req.State)
-	// if err != nil {
-	// 	return nil, err
-	// }
+	token, err := u.auth.GetOAuthToken(req.Code, req.State)
+	if err != nil {
+		return nil, fmt.Errorf("get oauth token failed: %w", err)
+	}
+
+	claims, err := u.auth.ParseJwtToken(token.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("parse jwt token failed: %w", err)
+	}
+	u.l.Info("User signed in",
+		zap.String("owner", claims.Owner),
+		zap.String("name", claims.Name),
+	)
+
+	// 原样返回 state，客户端据此校验回调与发起的登录请求是否一致
 	return &biz.SignInResponse{
-		// State: "ok",
-		// Data:  token.AccessToken,
+		State: req.State,
+		Data:  token.AccessToken,
 	}, nil
 }
+
+// CreateUser 创建用户，并在同一事务中写入同步搜索索引的发件箱事件
+func (u userRepo) CreateUser(ctx context.Context, username string, cred biz.UserCredential) (*biz.UserInfo, error) {
+	var user *biz.UserInfo
+	err := inTx(ctx, u.db, func(q models.Querier) error {
+		row, err := q.CreateUser(ctx, models.CreateUserParams{
+			Username:     username,
+			PasswordHash: cred.PasswordHash,
+			Salt:         cred.Salt,
+		})
+		if err != nil {
+			return err
+		}
+		user = toUserInfo(models.GetUserRow(row))
+		return insertUserUpserted(ctx, q, user)
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return nil, biz.ErrUserAlreadyExists.WithMetadata("username", username)
+		}
+		return nil, fmt.Errorf("create user failed: %w", err)
+	}
+
+	return user, nil
+}
+
+func (u userRepo) GetUserCredential(ctx context.Context, username string) (*biz.UserInfo, *biz.UserCredential, error) {
+	row, err := u.queries.GetUserByName(ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, biz.ErrUserNotFound
+		}
+		return nil, nil, fmt.Errorf("get user by name failed: %w", err)
+	}
+
+	user := &biz.UserInfo{
+		ID:       row.ID,
+		Username: row.Username,
+		Roles:    row.Roles,
+	}
+	cred := &biz.UserCredential{
+		PasswordHash: row.PasswordHash,
+		Salt:         row.Salt,
+	}
+	return user, cred, nil
+}
+
+func (u userRepo) GetUser(ctx context.Context, id int64) (*biz.UserInfo, error) {
+	user, err := u.queries.GetUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, biz.ErrUserNotFound.WithMetadata("id", strconv.FormatInt(id, 10))
+		}
+		return nil, fmt.Errorf("get user failed: %w", err)
+	}
+
+	return toUserInfo(user), nil
+}
+
+func (u userRepo) UpdateUser(ctx context.Context, req biz.UpdateUserRequest) (*biz.UserInfo, error) {
+	var user *biz.UserInfo
+	err := inTx(ctx, u.db, func(q models.Querier) error {
+		row, err := q.UpdateUser(ctx, models.UpdateUserParams{
+			Nickname: req.Nickname,
+			Email:    req.Email,
+			Avatar:   req.Avatar,
+			ID:       req.ID,
+		})
+		if err != nil {
+			return err
+		}
+		user = toUserInfo(models.GetUserRow(row))
+		return insertUserUpserted(ctx, q, user)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, biz.ErrUserNotFound.WithMetadata("id", strconv.FormatInt(req.ID, 10))
+		}
+		return nil, fmt.Errorf("update user failed: %w", err)
+	}
+
+	return user, nil
+}
+
+func (u userRepo) DeleteUser(ctx context.Context, id int64) error {
+	err := inTx(ctx, u.db, func(q models.Querier) error {
+		rows, err := q.DeleteUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return biz.ErrUserNotFound.WithMetadata("id", strconv.FormatInt(id, 10))
+		}
+		return insertUserDeleted(ctx, q, id)
+	})
+	if err != nil {
+		if errors.Is(err, biz.ErrUserNotFound) {
+			return err
+		}
+		return fmt.Errorf("delete user failed: %w", err)
+	}
+	return nil
+}
+
+func (u userRepo) ListUsers(ctx context.Context, afterID int64, limit int) ([]*biz.UserInfo, error) {
+	rows, err := u.queries.ListUsers(ctx, models.ListUsersParams{
+		AfterID:  afterID,
+		PageSize: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list users failed: %w", err)
+	}
+
+	users := make([]*biz.UserInfo, 0, len(rows))
+	for _, row := range rows {
+		users = append(users, toUserInfo(models.GetUserRow(row)))
+	}
+	return users, nil
+}
+
+// toUserInfo 将查询结果转换为业务层用户模型
+// 各查询返回的列相同，生成的 Row 结构体可以直接相互转换
+func toUserInfo(row models.GetUserRow) *biz.UserInfo {
+	return &biz.UserInfo{
+		ID:            row.ID,
+		Username:      row.Username,
+		Nickname:      row.Nickname,
+		Email:         row.Email,
+		EmailVerified: row.EmailVerifiedAt.Valid,
+		Avatar:        row.Avatar,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	}
+}
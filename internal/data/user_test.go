@@ -0,0 +1,195 @@
+package data
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connect-go-example/internal/biz"
+	"connect-go-example/internal/data/models"
+
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+const (
+	testClientID     = "test-client-id"
+	testClientSecret = "test-client-secret"
+	testAuthCode     = "test-code"
+)
+
+// fakeCasdoor 是 Casdoor token 接口的本地替身
+type fakeCasdoor struct {
+	server      *httptest.Server
+	key         *rsa.PrivateKey
+	certificate string
+	// accessToken 为空时，token 接口使用 key 签发一个合法的 JWT
+	accessToken string
+}
+
+func newFakeCasdoor(t *testing.T) *fakeCasdoor {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "casdoor-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	f := &fakeCasdoor{
+		key:         key,
+		certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("code") != testAuthCode || r.PostForm.Get("client_id") != testClientID {
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "error: invalid code"})
+			return
+		}
+		token := f.accessToken
+		if token == "" {
+			token = f.sign(t, "admin")
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  token,
+			"token_type":    "Bearer",
+			"refresh_token": "refresh-token",
+			"expires_in":    3600,
+		})
+	})
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+// sign 使用测试私钥签发一个 Casdoor 格式的 JWT
+func (f *fakeCasdoor) sign(t *testing.T, name string) string {
+	claims := casdoorsdk.Claims{
+		User: casdoorsdk.User{
+			Owner: "built-in",
+			Name:  name,
+		},
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   name,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(f.key)
+	require.NoError(t, err)
+	return token
+}
+
+func (f *fakeCasdoor) client(certificate string) *casdoorsdk.Client {
+	return casdoorsdk.NewClient(f.server.URL, testClientID, testClientSecret, certificate, "built-in", "app")
+}
+
+// UserRepoTestSuite 是 userRepo 的测试套件
+type UserRepoTestSuite struct {
+	suite.Suite
+	casdoor *fakeCasdoor
+}
+
+func (suite *UserRepoTestSuite) SetupTest() {
+	suite.casdoor = newFakeCasdoor(suite.T())
+}
+
+func (suite *UserRepoTestSuite) newRepo(auth *casdoorsdk.Client) *userRepo {
+	return &userRepo{auth: auth, l: zap.NewNop()}
+}
+
+func (suite *UserRepoTestSuite) TestSignIn_Success() {
+	repo := suite.newRepo(suite.casdoor.client(suite.casdoor.certificate))
+
+	res, err := repo.SignIn(context.Background(), biz.SignInRequest{Code: testAuthCode, State: "state"})
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "state", res.State)
+
+	claims, err := suite.casdoor.client(suite.casdoor.certificate).ParseJwtToken(res.Data)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "admin", claims.Name)
+}
+
+func (suite *UserRepoTestSuite) TestSignIn_InvalidCode() {
+	repo := suite.newRepo(suite.casdoor.client(suite.casdoor.certificate))
+
+	_, err := repo.SignIn(context.Background(), biz.SignInRequest{Code: "wrong", State: "state"})
+
+	assert.Error(suite.T(), err)
+}
+
+func (suite *UserRepoTestSuite) TestSignIn_InvalidSignature() {
+	// 使用另一对密钥生成的证书，签名校验应失败
+	other := newFakeCasdoor(suite.T())
+	repo := suite.newRepo(suite.casdoor.client(other.certificate))
+
+	_, err := repo.SignIn(context.Background(), biz.SignInRequest{Code: testAuthCode, State: "state"})
+
+	assert.ErrorContains(suite.T(), err, "parse jwt token failed")
+}
+
+func (suite *UserRepoTestSuite) TestSignIn_MalformedToken() {
+	suite.casdoor.accessToken = "not-a-jwt"
+	repo := suite.newRepo(suite.casdoor.client(suite.casdoor.certificate))
+
+	_, err := repo.SignIn(context.Background(), biz.SignInRequest{Code: testAuthCode, State: "state"})
+
+	assert.ErrorContains(suite.T(), err, "parse jwt token failed")
+}
+
+func (suite *UserRepoTestSuite) TestSignIn_NilAuthClient() {
+	repo := suite.newRepo(nil)
+
+	_, err := repo.SignIn(context.Background(), biz.SignInRequest{Code: testAuthCode})
+
+	assert.EqualError(suite.T(), err, "auth client is nil")
+}
+
+// 运行测试套件
+func TestUserRepo_GetUserCredential(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectQuery("-- name: GetUserByName").
+		WithArgs("alice").
+		WillReturnRows(pgxmock.NewRows([]string{"username", "salt", "id", "password_hash", "roles"}).
+			AddRow("alice", "salt", int64(1), "hash", []string{"admin"}))
+
+	repo := &userRepo{queries: models.New(mock), l: zap.NewNop()}
+	user, cred, err := repo.GetUserCredential(context.Background(), "alice")
+
+	require.NoError(t, err)
+	assert.Equal(t, &biz.UserInfo{ID: 1, Username: "alice", Roles: []string{"admin"}}, user)
+	assert.Equal(t, "hash", cred.PasswordHash)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(UserRepoTestSuite))
+}
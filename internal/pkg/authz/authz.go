@@ -0,0 +1,94 @@
+package authz
+
+import (
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	confv1 "connect-go-example/internal/conf/v1"
+)
+
+// Decision 一次授权判定的结果
+type Decision struct {
+	Allowed bool
+	// Policy 命中的策略（procedure 或服务通配），未命中时为空
+	Policy string
+	// RequiredRoles 策略要求的角色（任一）
+	RequiredRoles []string
+	// MissingPermissions 调用方缺少的权限
+	MissingPermissions []string
+}
+
+// policySet 编译后的策略表，替换时整体原子交换
+type policySet struct {
+	exact       map[string]*confv1.Authorization_Policy
+	services    map[string]*confv1.Authorization_Policy // key 为 /pkg.Service/
+	defaultDeny bool
+}
+
+// Evaluator 根据 procedure 对应的策略判断调用方是否有权限
+// 可以在运行时通过 Update 热更新策略，并发安全
+type Evaluator struct {
+	policies atomic.Pointer[policySet]
+}
+
+func NewEvaluator(cfg *confv1.Authorization) *Evaluator {
+	e := &Evaluator{}
+	e.Update(cfg)
+	return e
+}
+
+// Update 替换当前的策略表
+func (e *Evaluator) Update(cfg *confv1.Authorization) {
+	set := &policySet{
+		exact:       make(map[string]*confv1.Authorization_Policy),
+		services:    make(map[string]*confv1.Authorization_Policy),
+		defaultDeny: cfg.GetDefaultDeny(),
+	}
+	for _, policy := range cfg.GetPolicies() {
+		if policy == nil || policy.Procedure == "" {
+			continue
+		}
+		if service, ok := strings.CutSuffix(policy.Procedure, "*"); ok {
+			set.services[service] = policy
+			continue
+		}
+		set.exact[policy.Procedure] = policy
+	}
+	e.policies.Store(set)
+}
+
+// Evaluate 判断拥有 roles 和 permissions 的调用方能否调用 procedure
+// 精确匹配优先于服务通配；策略要求的角色满足其一即可，权限必须全部具备
+func (e *Evaluator) Evaluate(procedure string, roles, permissions []string) Decision {
+	set := e.policies.Load()
+
+	policy, ok := set.exact[procedure]
+	if !ok {
+		if idx := strings.LastIndex(procedure, "/"); idx >= 0 {
+			policy, ok = set.services[procedure[:idx+1]]
+		}
+	}
+	if !ok {
+		return Decision{Allowed: !set.defaultDeny}
+	}
+
+	decision := Decision{
+		Allowed:       true,
+		Policy:        policy.Procedure,
+		RequiredRoles: policy.Roles,
+	}
+
+	hasRole := func(role string) bool { return slices.Contains(roles, role) }
+	if len(policy.Roles) > 0 && !slices.ContainsFunc(policy.Roles, hasRole) {
+		decision.Allowed = false
+	}
+	for _, permission := range policy.Permissions {
+		if !slices.Contains(permissions, permission) {
+			decision.Allowed = false
+			decision.MissingPermissions = append(decision.MissingPermissions, permission)
+		}
+	}
+
+	return decision
+}
@@ -0,0 +1,122 @@
+package authz
+
+import (
+	"testing"
+
+	confv1 "connect-go-example/internal/conf/v1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	signOut  = "/user.v1.UserService/SignOut"
+	register = "/user.v1.UserService/Register"
+	other    = "/order.v1.OrderService/Create"
+)
+
+func testPolicies(defaultDeny bool) *confv1.Authorization {
+	return &confv1.Authorization{
+		DefaultDeny: defaultDeny,
+		Policies: []*confv1.Authorization_Policy{
+			{Procedure: signOut, Roles: []string{"user", "admin"}},
+			{Procedure: "/user.v1.UserService/*", Roles: []string{"admin"}},
+			{Procedure: "/audit.v1.AuditService/List", Permissions: []string{"audit:read", "audit:list"}},
+		},
+	}
+}
+
+func TestEvaluator_Evaluate(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaultDeny bool
+		procedure   string
+		roles       []string
+		permissions []string
+		want        Decision
+	}{
+		{
+			name:      "exact match with any role",
+			procedure: signOut,
+			roles:     []string{"guest", "admin"},
+			want:      Decision{Allowed: true, Policy: signOut, RequiredRoles: []string{"user", "admin"}},
+		},
+		{
+			name:      "exact match without role",
+			procedure: signOut,
+			roles:     []string{"guest"},
+			want:      Decision{Allowed: false, Policy: signOut, RequiredRoles: []string{"user", "admin"}},
+		},
+		{
+			name:      "exact match takes precedence over wildcard",
+			procedure: signOut,
+			roles:     []string{"user"},
+			want:      Decision{Allowed: true, Policy: signOut, RequiredRoles: []string{"user", "admin"}},
+		},
+		{
+			name:      "service wildcard allowed",
+			procedure: register,
+			roles:     []string{"admin"},
+			want:      Decision{Allowed: true, Policy: "/user.v1.UserService/*", RequiredRoles: []string{"admin"}},
+		},
+		{
+			name:      "service wildcard denied",
+			procedure: register,
+			roles:     []string{"user"},
+			want:      Decision{Allowed: false, Policy: "/user.v1.UserService/*", RequiredRoles: []string{"admin"}},
+		},
+		{
+			name:        "all permissions present",
+			procedure:   "/audit.v1.AuditService/List",
+			permissions: []string{"audit:list", "audit:read"},
+			want:        Decision{Allowed: true, Policy: "/audit.v1.AuditService/List"},
+		},
+		{
+			name:        "missing permission",
+			procedure:   "/audit.v1.AuditService/List",
+			permissions: []string{"audit:read"},
+			want: Decision{
+				Allowed:            false,
+				Policy:             "/audit.v1.AuditService/List",
+				MissingPermissions: []string{"audit:list"},
+			},
+		},
+		{
+			name:      "no policy allows by default",
+			procedure: other,
+			want:      Decision{Allowed: true},
+		},
+		{
+			name:        "no policy with default deny",
+			defaultDeny: true,
+			procedure:   other,
+			roles:       []string{"admin"},
+			want:        Decision{Allowed: false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEvaluator(testPolicies(tt.defaultDeny))
+
+			got := e.Evaluate(tt.procedure, tt.roles, tt.permissions)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvaluator_NilConfig(t *testing.T) {
+	e := NewEvaluator(nil)
+
+	assert.True(t, e.Evaluate(signOut, nil, nil).Allowed)
+}
+
+func TestEvaluator_Update(t *testing.T) {
+	e := NewEvaluator(nil)
+	assert.True(t, e.Evaluate(signOut, nil, nil).Allowed)
+
+	e.Update(testPolicies(false))
+	assert.False(t, e.Evaluate(signOut, nil, nil).Allowed)
+
+	e.Update(&confv1.Authorization{})
+	assert.True(t, e.Evaluate(signOut, nil, nil).Allowed)
+}
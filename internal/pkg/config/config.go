@@ -2,9 +2,14 @@ package config
 
 import (
 	"fmt"
+	"net/mail"
+	"net/url"
 	"os"
+	"strings"
+	"sync"
 
 	confv1 "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/secretbox"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
@@ -31,34 +36,77 @@ var (
 	)
 )
 
+var (
+	listenersMu sync.Mutex
+	listeners   []func(*confv1.Bootstrap)
+)
+
+// OnChange 注册配置变更回调，配置中心的配置更新并解码成功后依次调用
+func OnChange(fn func(*confv1.Bootstrap)) {
+	listenersMu.Lock()
+	defer listenersMu.Unlock()
+	listeners = append(listeners, fn)
+}
+
 // updateConfig 更新全局配置
 func updateConfig(newConfig map[string]interface{}) {
-	// 使用viper解析配置
+	// 解码到Bootstrap结构体
+	newBootstrap := &confv1.Bootstrap{}
+	if err := Decode(newConfig, newBootstrap); err != nil {
+		fmt.Printf("Error: Unable to decode new config into struct: %v\n", err)
+		return
+	}
+
+	// 更新全局配置
+	conf = newBootstrap
+
+	// 通知订阅者
+	listenersMu.Lock()
+	fns := append([]func(*confv1.Bootstrap){}, listeners...)
+	listenersMu.Unlock()
+	for _, fn := range fns {
+		fn(newBootstrap)
+	}
+}
+
+// Decode 将配置中心返回的 map 解码到 Protobuf 生成的配置结构体
+func Decode(m map[string]interface{}, out interface{}) error {
+	// 使用viper统一键的格式
 	v := viper.New()
-	for k, value := range newConfig {
+	for k, value := range m {
 		v.Set(k, value)
 	}
 
-	// 解码到Bootstrap结构体
-	newBootstrap := &confv1.Bootstrap{}
-	m := v.AllSettings()
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		Metadata: nil,
 		TagName:  "json", // 明确告诉 mapstructure 使用 json tag（Protobuf 结构体自带）
-		Result:   newBootstrap,
+		Result:   out,
 	})
 	if err != nil {
-		fmt.Printf("Error: Failed to create decoder when updating config: %v\n", err)
-		return
+		return fmt.Errorf("create decoder failed: %w", err)
 	}
 
-	if err := decoder.Decode(m); err != nil {
-		fmt.Printf("Error: Unable to decode new config into struct: %v\n", err)
-		return
+	return decoder.Decode(v.AllSettings())
+}
+
+// WatchKey 从配置中心加载 path 对应的配置并持续监听，首次加载和后续变更都会调用 onChange
+func WatchKey(path string, onChange func(map[string]interface{})) error {
+	consulClient, err := InitConsul(&ConsulConfig{
+		Addr:  os.Getenv("CONFIG_CENTER"),
+		Token: os.Getenv("CONFIG_CENTER_TOKEN"),
+	})
+	if err != nil {
+		return err
 	}
 
-	// 更新全局配置
-	conf = newBootstrap
+	initial, err := GetConfigFromConsul(consulClient, path)
+	if err != nil {
+		return err
+	}
+	onChange(initial)
+
+	WatchConsulConfig(consulClient, path, onChange)
+	return nil
 }
 
 // Init 初始化配置加载，只从consul配置中心获取，并启动配置监听
@@ -189,16 +237,37 @@ func ValidateConfig(conf *confv1.Bootstrap) error {
 	if conf.Server == nil || conf.Server.Http == nil {
 		return fmt.Errorf("server configuration is required")
 	}
+	if health := conf.Server.GetHealth(); health.GetInterval() < 0 || health.GetTimeout() < 0 {
+		return fmt.Errorf("server health: interval and timeout must not be negative")
+	}
+	if shutdown := conf.Server.GetShutdown(); shutdown.GetPropagationDelay() < 0 || shutdown.GetDrainTimeout() < 0 {
+		return fmt.Errorf("server shutdown: propagation_delay and drain_timeout must not be negative")
+	}
 
 	// 验证数据库配置
 	if conf.Data == nil {
 		return fmt.Errorf("database configuration is required")
 	}
+	if err := validateDatabasePool(conf.Data.GetDatabase().GetPool()); err != nil {
+		return err
+	}
+	if err := validateOutbox(conf.Data.GetOutbox()); err != nil {
+		return err
+	}
 
 	// 验证安全配置
 	if conf.Auth == nil {
 		return fmt.Errorf("auth configuration is required")
 	}
+	if err := validateLockout(conf.Auth.GetLockout()); err != nil {
+		return err
+	}
+	if err := validateMFA(conf.Auth.GetMfa()); err != nil {
+		return err
+	}
+	if err := validateEmailTokens(conf.Auth.GetEmailTokens()); err != nil {
+		return err
+	}
 
 	// 验证链路追踪配置
 	if conf.Trace == nil {
@@ -209,6 +278,186 @@ func ValidateConfig(conf *confv1.Bootstrap) error {
 	if conf.Discovery == nil {
 		return fmt.Errorf("discovery configuration is required")
 	}
+	if err := validateConsulCheck(conf.Discovery.GetConsul().GetCheck()); err != nil {
+		return err
+	}
+
+	// 验证限流配置
+	if err := validateRateLimit(conf.GetRateLimit()); err != nil {
+		return err
+	}
+
+	// 验证邮件配置
+	if err := validateMail(conf.GetMail()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateMFA 校验 MFA 配置，加密密钥为空时不能绑定 MFA
+func validateMFA(mfa *confv1.Auth_Mfa) error {
+	if mfa == nil {
+		return nil
+	}
+
+	if mfa.EncryptionKey != "" {
+		if _, err := secretbox.DecodeKey(mfa.EncryptionKey); err != nil {
+			return fmt.Errorf("auth mfa: encryption_key must be a base64 encoded 32-byte key: %w", err)
+		}
+	}
+	if mfa.ChallengeTtl < 0 || mfa.RecoveryCodes < 0 {
+		return fmt.Errorf("auth mfa: challenge_ttl and recovery_codes must not be negative")
+	}
+
+	return nil
+}
+
+// validateEmailTokens 校验重置密码和验证邮箱的令牌配置，0 表示使用默认有效期
+func validateEmailTokens(tokens *confv1.Auth_EmailTokens) error {
+	if tokens == nil {
+		return nil
+	}
+
+	if tokens.PasswordResetTtl < 0 || tokens.VerificationTtl < 0 {
+		return fmt.Errorf("auth email_tokens: password_reset_ttl and verification_ttl must not be negative")
+	}
+	if tokens.LinkBaseUrl != "" {
+		u, err := url.Parse(tokens.LinkBaseUrl)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("auth email_tokens: link_base_url %q must be an absolute http or https URL", tokens.LinkBaseUrl)
+		}
+	}
+
+	return nil
+}
+
+// validateMail 校验邮件配置，未配置时只写日志
+func validateMail(m *confv1.Mail) error {
+	switch m.GetDriver() {
+	case "", "log", "memory":
+		return nil
+	case "smtp":
+	default:
+		return fmt.Errorf("mail: unsupported driver %q, must be log, smtp or memory", m.GetDriver())
+	}
+
+	if m.GetSmtp().GetHost() == "" {
+		return fmt.Errorf("mail: smtp.host is required for the smtp driver")
+	}
+	if port := m.GetSmtp().GetPort(); port < 0 || port > 65535 {
+		return fmt.Errorf("mail: smtp.port %d is out of range", port)
+	}
+	if _, err := mail.ParseAddress(m.GetFrom()); err != nil {
+		return fmt.Errorf("mail: from %q is not a valid address: %w", m.GetFrom(), err)
+	}
+
+	return nil
+}
+
+// validateLockout 校验账号锁定配置，0 表示使用默认值
+func validateLockout(lockout *confv1.Auth_Lockout) error {
+	if lockout == nil {
+		return nil
+	}
+
+	if lockout.MaxFailures < 0 || lockout.Window < 0 || lockout.BaseDuration < 0 || lockout.MaxDuration < 0 || lockout.ResetAfter < 0 {
+		return fmt.Errorf("auth lockout: max_failures, window, base_duration, max_duration and reset_after must not be negative")
+	}
+	if lockout.MaxDuration > 0 && lockout.BaseDuration > lockout.MaxDuration {
+		return fmt.Errorf("auth lockout: base_duration (%d) must not be greater than max_duration (%d)", lockout.BaseDuration, lockout.MaxDuration)
+	}
+
+	return nil
+}
+
+// validateOutbox 校验发件箱投递配置，0 表示使用默认值
+func validateOutbox(outbox *confv1.Data_Outbox) error {
+	if outbox == nil {
+		return nil
+	}
+
+	if outbox.PollInterval < 0 || outbox.BatchSize < 0 || outbox.MaxAttempts < 0 || outbox.BaseBackoff < 0 || outbox.MaxBackoff < 0 {
+		return fmt.Errorf("data outbox: poll_interval, batch_size, max_attempts, base_backoff and max_backoff must not be negative")
+	}
+	if outbox.MaxBackoff > 0 && outbox.BaseBackoff > outbox.MaxBackoff {
+		return fmt.Errorf("data outbox: base_backoff (%d) must not be greater than max_backoff (%d)", outbox.BaseBackoff, outbox.MaxBackoff)
+	}
+
+	return nil
+}
+
+// validateRateLimit 校验限流规则，0 和空值表示使用默认值，limit 为 0 表示关闭该 procedure 的限流
+func validateRateLimit(rateLimit *confv1.RateLimit) error {
+	for _, rule := range rateLimit.GetRules() {
+		if rule.GetProcedure() == "" {
+			return fmt.Errorf("rate limit: procedure is required")
+		}
+		if rule.GetLimit() < 0 || rule.GetPeriod() < 0 || rule.GetBurst() < 0 {
+			return fmt.Errorf("rate limit %s: limit, period and burst must not be negative", rule.GetProcedure())
+		}
+		switch rule.GetKey() {
+		case "", "ip", "user", "api_key":
+		default:
+			return fmt.Errorf("rate limit %s: unsupported key %q, expected ip, user or api_key", rule.GetProcedure(), rule.GetKey())
+		}
+	}
+
+	return nil
+}
+
+// validateConsulCheck 校验 Consul 健康检查配置，0 和空值表示使用默认值
+func validateConsulCheck(check *confv1.Discovery_Check) error {
+	if check == nil {
+		return nil
+	}
+
+	switch check.Mode {
+	case "", "ttl", "http", "grpc":
+	default:
+		return fmt.Errorf("consul check: unsupported mode %q, expected ttl, http or grpc", check.Mode)
+	}
+	if check.Interval < 0 || check.Timeout < 0 || check.DeregisterCriticalAfter < 0 {
+		return fmt.Errorf("consul check: interval, timeout and deregister_critical_after must not be negative")
+	}
+	if check.HttpPath != "" && !strings.HasPrefix(check.HttpPath, "/") {
+		return fmt.Errorf("consul check: http_path must start with /")
+	}
+
+	return nil
+}
+
+// validateDatabasePool 校验连接池配置，0 表示使用默认值
+func validateDatabasePool(pool *confv1.Data_DatabasePool) error {
+	if pool == nil {
+		return nil
+	}
+
+	if pool.MaxConns < 0 || pool.MinConns < 0 {
+		return fmt.Errorf("database pool: max_conns and min_conns must not be negative")
+	}
+	if pool.MaxConns > 0 && pool.MinConns > pool.MaxConns {
+		return fmt.Errorf("database pool: min_conns (%d) must not be greater than max_conns (%d)", pool.MinConns, pool.MaxConns)
+	}
+
+	durations := []struct {
+		name  string
+		value int64
+	}{
+		{"max_conn_lifetime", pool.MaxConnLifetime},
+		{"max_conn_lifetime_jitter", pool.MaxConnLifetimeJitter},
+		{"max_conn_idle_time", pool.MaxConnIdleTime},
+		{"health_check_period", pool.HealthCheckPeriod},
+		{"statement_timeout", pool.StatementTimeout},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			return fmt.Errorf("database pool: %s must not be negative", d.name)
+		}
+	}
+	if pool.MaxConnLifetime > 0 && pool.MaxConnLifetimeJitter >= pool.MaxConnLifetime {
+		return fmt.Errorf("database pool: max_conn_lifetime_jitter must be less than max_conn_lifetime")
+	}
 
 	return nil
 }
@@ -23,8 +23,9 @@ func (suite *ConfigTestSuite) SetupTest() {
 func (suite *ConfigTestSuite) TestInit_ValidConfig() {
 	// 使用项目中的实际配置文件进行测试
 	configPath := "configs/config.yaml"
+	os.Setenv("CONFIG_PATH", configPath)
 
-	conf := Init(configPath)
+	conf := Init()
 
 	// 配置文件可能不存在，所以两种情况都接受
 	if conf != nil {
@@ -41,8 +42,9 @@ func (suite *ConfigTestSuite) TestInit_ValidConfig() {
 func (suite *ConfigTestSuite) TestInit_InvalidConfig() {
 	// 测试不存在的配置文件
 	configPath := "nonexistent/config.yaml"
+	os.Setenv("CONFIG_PATH", configPath)
 
-	conf := Init(configPath)
+	conf := Init()
 
 	assert.Nil(suite.T(), conf)
 }
@@ -150,6 +152,28 @@ func (suite *ConfigTestSuite) TestValidateConfig_MissingServer() {
 	assert.Equal(suite.T(), "server configuration is required", err.Error())
 }
 
+func (suite *ConfigTestSuite) TestValidateConfig_NegativeHealth() {
+	err := ValidateConfig(&confv1.Bootstrap{
+		Server: &confv1.Server{
+			Http:   &confv1.Server_HTTP{Addr: ":8080"},
+			Health: &confv1.Server_Health{Timeout: -1},
+		},
+	})
+
+	assert.Error(suite.T(), err)
+}
+
+func (suite *ConfigTestSuite) TestValidateConfig_NegativeShutdown() {
+	err := ValidateConfig(&confv1.Bootstrap{
+		Server: &confv1.Server{
+			Http:     &confv1.Server_HTTP{Addr: ":8080"},
+			Shutdown: &confv1.Server_Shutdown{PropagationDelay: -1},
+		},
+	})
+
+	assert.Error(suite.T(), err)
+}
+
 func (suite *ConfigTestSuite) TestValidateConfig_MissingDatabase() {
 	invalidConfig := &confv1.Bootstrap{
 		Server: &confv1.Server{
@@ -165,6 +189,280 @@ func (suite *ConfigTestSuite) TestValidateConfig_MissingDatabase() {
 	assert.Equal(suite.T(), "database configuration is required", err.Error())
 }
 
+func (suite *ConfigTestSuite) TestValidateConfig_DatabasePool() {
+	tests := []struct {
+		name    string
+		pool    *confv1.Data_DatabasePool
+		wantErr bool
+	}{
+		{"defaults", &confv1.Data_DatabasePool{}, false},
+		{"valid", &confv1.Data_DatabasePool{MaxConns: 10, MinConns: 2, MaxConnLifetime: 3600, MaxConnLifetimeJitter: 60, StatementTimeout: 5000}, false},
+		{"min only", &confv1.Data_DatabasePool{MinConns: 2}, false},
+		{"min greater than max", &confv1.Data_DatabasePool{MaxConns: 2, MinConns: 5}, true},
+		{"negative max", &confv1.Data_DatabasePool{MaxConns: -1}, true},
+		{"negative idle time", &confv1.Data_DatabasePool{MaxConnIdleTime: -1}, true},
+		{"negative statement timeout", &confv1.Data_DatabasePool{StatementTimeout: -1}, true},
+		{"jitter not less than lifetime", &confv1.Data_DatabasePool{MaxConnLifetime: 60, MaxConnLifetimeJitter: 60}, true},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			err := ValidateConfig(&confv1.Bootstrap{
+				Server:    &confv1.Server{Http: &confv1.Server_HTTP{Addr: ":8080"}},
+				Data:      &confv1.Data{Database: &confv1.Data_Database{Pool: tt.pool}},
+				Auth:      &confv1.Auth{},
+				Trace:     &confv1.Trace{},
+				Discovery: &confv1.Discovery{},
+			})
+			if tt.wantErr {
+				assert.Error(suite.T(), err)
+			} else {
+				assert.NoError(suite.T(), err)
+			}
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestValidateConfig_ConsulCheck() {
+	tests := []struct {
+		name    string
+		check   *confv1.Discovery_Check
+		wantErr bool
+	}{
+		{"defaults", &confv1.Discovery_Check{}, false},
+		{"http", &confv1.Discovery_Check{Mode: "http", Interval: 10, Timeout: 2, HttpPath: "/readyz"}, false},
+		{"grpc", &confv1.Discovery_Check{Mode: "grpc", GrpcService: "user.v1.UserService"}, false},
+		{"unknown mode", &confv1.Discovery_Check{Mode: "tcp"}, true},
+		{"negative interval", &confv1.Discovery_Check{Interval: -1}, true},
+		{"relative http path", &confv1.Discovery_Check{Mode: "http", HttpPath: "readyz"}, true},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			err := ValidateConfig(&confv1.Bootstrap{
+				Server:    &confv1.Server{Http: &confv1.Server_HTTP{Addr: ":8080"}},
+				Data:      &confv1.Data{},
+				Auth:      &confv1.Auth{},
+				Trace:     &confv1.Trace{},
+				Discovery: &confv1.Discovery{Consul: &confv1.Discovery_Consul{Check: tt.check}},
+			})
+			if tt.wantErr {
+				assert.Error(suite.T(), err)
+			} else {
+				assert.NoError(suite.T(), err)
+			}
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestValidateConfig_Outbox() {
+	tests := []struct {
+		name    string
+		outbox  *confv1.Data_Outbox
+		wantErr bool
+	}{
+		{"defaults", &confv1.Data_Outbox{}, false},
+		{"custom", &confv1.Data_Outbox{PollInterval: 2, BatchSize: 50, MaxAttempts: 5, BaseBackoff: 1, MaxBackoff: 60}, false},
+		{"negative poll interval", &confv1.Data_Outbox{PollInterval: -1}, true},
+		{"base greater than max", &confv1.Data_Outbox{BaseBackoff: 600, MaxBackoff: 60}, true},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			err := ValidateConfig(&confv1.Bootstrap{
+				Server:    &confv1.Server{Http: &confv1.Server_HTTP{Addr: ":8080"}},
+				Data:      &confv1.Data{Outbox: tt.outbox},
+				Auth:      &confv1.Auth{},
+				Trace:     &confv1.Trace{},
+				Discovery: &confv1.Discovery{},
+			})
+			if tt.wantErr {
+				assert.Error(suite.T(), err)
+			} else {
+				assert.NoError(suite.T(), err)
+			}
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestValidateConfig_Lockout() {
+	tests := []struct {
+		name    string
+		lockout *confv1.Auth_Lockout
+		wantErr bool
+	}{
+		{"defaults", &confv1.Auth_Lockout{}, false},
+		{"custom", &confv1.Auth_Lockout{MaxFailures: 10, Window: 600, BaseDuration: 30, MaxDuration: 1800}, false},
+		{"negative window", &confv1.Auth_Lockout{Window: -1}, true},
+		{"base greater than max", &confv1.Auth_Lockout{BaseDuration: 600, MaxDuration: 60}, true},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			err := ValidateConfig(&confv1.Bootstrap{
+				Server:    &confv1.Server{Http: &confv1.Server_HTTP{Addr: ":8080"}},
+				Data:      &confv1.Data{},
+				Auth:      &confv1.Auth{Lockout: tt.lockout},
+				Trace:     &confv1.Trace{},
+				Discovery: &confv1.Discovery{},
+			})
+			if tt.wantErr {
+				assert.Error(suite.T(), err)
+			} else {
+				assert.NoError(suite.T(), err)
+			}
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestValidateConfig_MFA() {
+	tests := []struct {
+		name    string
+		mfa     *confv1.Auth_Mfa
+		wantErr bool
+	}{
+		{"defaults", &confv1.Auth_Mfa{}, false},
+		{"valid key", &confv1.Auth_Mfa{EncryptionKey: "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=", ChallengeTtl: 120}, false},
+		{"short key", &confv1.Auth_Mfa{EncryptionKey: "c2hvcnQ="}, true},
+		{"not base64", &confv1.Auth_Mfa{EncryptionKey: "not base64!"}, true},
+		{"negative recovery codes", &confv1.Auth_Mfa{RecoveryCodes: -1}, true},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			err := ValidateConfig(&confv1.Bootstrap{
+				Server:    &confv1.Server{Http: &confv1.Server_HTTP{Addr: ":8080"}},
+				Data:      &confv1.Data{},
+				Auth:      &confv1.Auth{Mfa: tt.mfa},
+				Trace:     &confv1.Trace{},
+				Discovery: &confv1.Discovery{},
+			})
+			if tt.wantErr {
+				assert.Error(suite.T(), err)
+			} else {
+				assert.NoError(suite.T(), err)
+			}
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestValidateConfig_EmailTokens() {
+	tests := []struct {
+		name    string
+		tokens  *confv1.Auth_EmailTokens
+		wantErr bool
+	}{
+		{"defaults", &confv1.Auth_EmailTokens{}, false},
+		{"link base url", &confv1.Auth_EmailTokens{LinkBaseUrl: "https://example.com/account/verify?lang=en", VerificationTtl: 3600}, false},
+		{"relative link base url", &confv1.Auth_EmailTokens{LinkBaseUrl: "/account/verify"}, true},
+		{"unsupported scheme", &confv1.Auth_EmailTokens{LinkBaseUrl: "javascript:alert(1)"}, true},
+		{"negative ttl", &confv1.Auth_EmailTokens{PasswordResetTtl: -1}, true},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			err := ValidateConfig(&confv1.Bootstrap{
+				Server:    &confv1.Server{Http: &confv1.Server_HTTP{Addr: ":8080"}},
+				Data:      &confv1.Data{},
+				Auth:      &confv1.Auth{EmailTokens: tt.tokens},
+				Trace:     &confv1.Trace{},
+				Discovery: &confv1.Discovery{},
+			})
+			if tt.wantErr {
+				assert.Error(suite.T(), err)
+			} else {
+				assert.NoError(suite.T(), err)
+			}
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestValidateConfig_Mail() {
+	smtp := &confv1.Mail_SMTP{Host: "smtp.example.com"}
+	tests := []struct {
+		name    string
+		mail    *confv1.Mail
+		wantErr bool
+	}{
+		{"not configured", nil, false},
+		{"log", &confv1.Mail{Driver: "log"}, false},
+		{"smtp", &confv1.Mail{Driver: "smtp", From: "Example <no-reply@example.com>", Smtp: smtp}, false},
+		{"smtp without host", &confv1.Mail{Driver: "smtp", From: "no-reply@example.com"}, true},
+		{"smtp without from", &confv1.Mail{Driver: "smtp", Smtp: smtp}, true},
+		{"smtp port out of range", &confv1.Mail{Driver: "smtp", From: "no-reply@example.com", Smtp: &confv1.Mail_SMTP{Host: "smtp.example.com", Port: 70000}}, true},
+		{"unknown driver", &confv1.Mail{Driver: "sendmail"}, true},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			err := ValidateConfig(&confv1.Bootstrap{
+				Server:    &confv1.Server{Http: &confv1.Server_HTTP{Addr: ":8080"}},
+				Data:      &confv1.Data{},
+				Auth:      &confv1.Auth{},
+				Trace:     &confv1.Trace{},
+				Discovery: &confv1.Discovery{},
+				Mail:      tt.mail,
+			})
+			if tt.wantErr {
+				assert.Error(suite.T(), err)
+			} else {
+				assert.NoError(suite.T(), err)
+			}
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestValidateConfig_RateLimit() {
+	signIn := "/user.v1.UserService/SignIn"
+	tests := []struct {
+		name    string
+		rule    *confv1.RateLimit_Rule
+		wantErr bool
+	}{
+		{"defaults", &confv1.RateLimit_Rule{Procedure: signIn, Limit: 5}, false},
+		{"user key", &confv1.RateLimit_Rule{Procedure: "/user.v1.UserService/*", Limit: 100, Period: 60, Burst: 20, Key: "user"}, false},
+		{"missing procedure", &confv1.RateLimit_Rule{Limit: 5}, true},
+		{"zero limit disables", &confv1.RateLimit_Rule{Procedure: signIn}, false},
+		{"negative limit", &confv1.RateLimit_Rule{Procedure: signIn, Limit: -1}, true},
+		{"negative burst", &confv1.RateLimit_Rule{Procedure: signIn, Limit: 5, Burst: -1}, true},
+		{"unknown key", &confv1.RateLimit_Rule{Procedure: signIn, Limit: 5, Key: "session"}, true},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			err := ValidateConfig(&confv1.Bootstrap{
+				Server:    &confv1.Server{Http: &confv1.Server_HTTP{Addr: ":8080"}},
+				Data:      &confv1.Data{},
+				Auth:      &confv1.Auth{},
+				Trace:     &confv1.Trace{},
+				Discovery: &confv1.Discovery{},
+				RateLimit: &confv1.RateLimit{Rules: []*confv1.RateLimit_Rule{tt.rule}},
+			})
+			if tt.wantErr {
+				assert.Error(suite.T(), err)
+			} else {
+				assert.NoError(suite.T(), err)
+			}
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestDecode_Authorization() {
+	m := map[string]interface{}{
+		"default_deny": true,
+		"policies": []interface{}{
+			map[string]interface{}{
+				"procedure":   "/user.v1.UserService/SignOut",
+				"roles":       []interface{}{"admin"},
+				"permissions": []interface{}{"user:write"},
+			},
+		},
+	}
+
+	out := &confv1.Authorization{}
+	err := Decode(m, out)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), out.DefaultDeny)
+	if assert.Len(suite.T(), out.Policies, 1) {
+		assert.Equal(suite.T(), "/user.v1.UserService/SignOut", out.Policies[0].Procedure)
+		assert.Equal(suite.T(), []string{"admin"}, out.Policies[0].Roles)
+		assert.Equal(suite.T(), []string{"user:write"}, out.Policies[0].Permissions)
+	}
+}
+
 func (suite *ConfigTestSuite) TestContains() {
 	// 测试包含子字符串
 	assert.True(suite.T(), contains("hello world", "hello"))
@@ -0,0 +1,130 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	healthv1 "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// ServiceName grpc.health.v1.Health 的完整服务名
+	ServiceName = "grpc.health.v1.Health"
+
+	CheckProcedure = "/grpc.health.v1.Health/Check"
+	WatchProcedure = "/grpc.health.v1.Health/Watch"
+	ListProcedure  = "/grpc.health.v1.Health/List"
+)
+
+var healthMethods = healthv1.File_grpc_health_v1_health_proto.Services().ByName("Health").Methods()
+
+// NewHandler 返回 grpc.health.v1.Health 服务的路径前缀和 Handler，支持 Connect、gRPC 和 gRPC-Web 协议
+func NewHandler(checker *Checker, opts ...connect.HandlerOption) (string, http.Handler) {
+	check := connect.NewUnaryHandler(
+		CheckProcedure,
+		func(ctx context.Context, req *connect.Request[healthv1.HealthCheckRequest]) (*connect.Response[healthv1.HealthCheckResponse], error) {
+			result, ok := checker.Result(req.Msg.GetService())
+			if !ok {
+				return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("unknown service %q", req.Msg.GetService()))
+			}
+			return connect.NewResponse(&healthv1.HealthCheckResponse{Status: result.Status}), nil
+		},
+		append(opts, connect.WithSchema(healthMethods.ByName("Check")))...,
+	)
+
+	watch := connect.NewServerStreamHandler(
+		WatchProcedure,
+		func(ctx context.Context, req *connect.Request[healthv1.HealthCheckRequest], stream *connect.ServerStream[healthv1.HealthCheckResponse]) error {
+			updates, stop := checker.watch(req.Msg.GetService())
+			defer stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case status := <-updates:
+					if err := stream.Send(&healthv1.HealthCheckResponse{Status: status}); err != nil {
+						return err
+					}
+				}
+			}
+		},
+		append(opts, connect.WithSchema(healthMethods.ByName("Watch")))...,
+	)
+
+	list := connect.NewUnaryHandler(
+		ListProcedure,
+		func(ctx context.Context, req *connect.Request[healthv1.HealthListRequest]) (*connect.Response[healthv1.HealthListResponse], error) {
+			statuses := make(map[string]*healthv1.HealthCheckResponse)
+			for name, result := range checker.Results() {
+				statuses[name] = &healthv1.HealthCheckResponse{Status: result.Status}
+			}
+			return connect.NewResponse(&healthv1.HealthListResponse{Statuses: statuses}), nil
+		},
+		append(opts, connect.WithSchema(healthMethods.ByName("List")))...,
+	)
+
+	return "/" + ServiceName + "/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case CheckProcedure:
+			check.ServeHTTP(w, r)
+		case WatchProcedure:
+			watch.ServeHTTP(w, r)
+		case ListProcedure:
+			list.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// LivenessHandler 存活探针，进程能处理请求即返回 200，不检查外部依赖，避免依赖故障导致 Pod 被反复重启
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte("ok\n"))
+	})
+}
+
+type readinessCheck struct {
+	Status string `json:"status"`
+}
+
+type readinessResponse struct {
+	Status string                    `json:"status"`
+	Checks map[string]readinessCheck `json:"checks,omitempty"`
+}
+
+// ReadinessHandler 就绪探针，整体状态为 SERVING 时返回 200，否则返回 503
+// 默认只返回整体状态，加上 ?verbose=true 时列出每个依赖的状态
+// 探针无需认证，响应中不包含错误信息，避免泄露内部地址和账号；错误由 Checker 在状态变化时记录到日志
+func ReadinessHandler(checker *Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		results := checker.Results()
+		overall := results[""]
+
+		resp := readinessResponse{Status: overall.Status.String()}
+		if strings.EqualFold(r.URL.Query().Get("verbose"), "true") {
+			resp.Checks = make(map[string]readinessCheck, len(results))
+			for name, result := range results {
+				if name == "" {
+					continue
+				}
+				resp.Checks[name] = readinessCheck{Status: result.Status.String()}
+			}
+		}
+
+		code := http.StatusOK
+		if overall.Status != StatusServing {
+			code = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
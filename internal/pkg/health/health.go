@@ -0,0 +1,303 @@
+// Package health 实现 gRPC 健康检查协议（grpc.health.v1.Health）以及 Kubernetes 探针使用的 HTTP 端点
+// 各依赖的状态由 Checker 周期性探测得到，Check/Watch/List 和 /readyz 只读取缓存的结果
+package health
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	healthv1 "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Status 服务状态，与 grpc.health.v1 的 ServingStatus 一致
+type Status = healthv1.HealthCheckResponse_ServingStatus
+
+const (
+	StatusUnknown    = healthv1.HealthCheckResponse_UNKNOWN
+	StatusServing    = healthv1.HealthCheckResponse_SERVING
+	StatusNotServing = healthv1.HealthCheckResponse_NOT_SERVING
+	// StatusServiceUnknown 仅用于 Watch，表示被查询的服务尚未注册
+	StatusServiceUnknown = healthv1.HealthCheckResponse_SERVICE_UNKNOWN
+)
+
+// 探测的默认配置
+const (
+	DefaultInterval = 10 * time.Second
+	DefaultTimeout  = 3 * time.Second
+)
+
+var ErrNotStarted = errors.New("health checker not started")
+
+// Probe 一个依赖的探测，Check 返回 nil 表示健康
+type Probe struct {
+	Name  string
+	Check func(ctx context.Context) error
+	// Optional 为 true 时探测失败只影响该依赖自己的状态，不影响聚合服务和就绪探针
+	Optional bool
+}
+
+// Result 一个服务最近一次的检查结果
+type Result struct {
+	Status    Status
+	Error     string
+	CheckedAt time.Time
+}
+
+// Checker 周期性执行所有 Probe，维护每个服务的状态
+// 每个 Probe 以自己的名字注册为一个服务；aggregates 中的服务（包括整体状态 ""）依赖所有非 Optional 的 Probe，
+// 任意一个这样的 Probe 失败时都会变为 NOT_SERVING
+type Checker struct {
+	probes     []Probe
+	aggregates []string
+	interval   time.Duration
+	timeout    time.Duration
+	l          *zap.Logger
+
+	mu       sync.RWMutex
+	results  map[string]Result
+	watchers map[string]map[chan Status]struct{}
+	// shutdown 后不再更新状态，所有服务保持 NOT_SERVING
+	shutdown bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewChecker 创建 Checker，aggregates 为依赖所有非 Optional Probe 的服务名，整体状态 "" 总是包含在内
+func NewChecker(probes []Probe, aggregates []string, interval, timeout time.Duration, logger *zap.Logger) *Checker {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	c := &Checker{
+		probes:     probes,
+		aggregates: append([]string{""}, aggregates...),
+		interval:   interval,
+		timeout:    timeout,
+		l:          logger,
+		results:    make(map[string]Result),
+		watchers:   make(map[string]map[chan Status]struct{}),
+	}
+
+	// 第一次检查完成前所有服务都是 NOT_SERVING，避免在依赖就绪前接收流量
+	for _, name := range c.services() {
+		c.results[name] = Result{Status: StatusNotServing, Error: ErrNotStarted.Error()}
+	}
+	return c
+}
+
+func (c *Checker) services() []string {
+	names := append([]string{}, c.aggregates...)
+	for _, probe := range c.probes {
+		names = append(names, probe.Name)
+	}
+	return names
+}
+
+// Start 同步执行一次检查，然后在后台周期性检查
+func (c *Checker) Start(ctx context.Context) {
+	c.CheckNow(ctx)
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	go c.run(runCtx)
+	c.l.Info("Health checker started", zap.Duration("interval", c.interval))
+}
+
+// Stop 停止后台检查
+func (c *Checker) Stop(ctx context.Context) error {
+	if c.cancel == nil {
+		return nil
+	}
+	c.cancel()
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Checker) run(ctx context.Context) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.CheckNow(ctx)
+		}
+	}
+}
+
+// CheckNow 并发执行所有 Probe 并更新状态
+func (c *Checker) CheckNow(ctx context.Context) {
+	errs := make([]error, len(c.probes))
+
+	var wg sync.WaitGroup
+	for i, probe := range c.probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+			errs[i] = probe.Check(probeCtx)
+		}()
+	}
+	wg.Wait()
+
+	now := time.Now()
+	aggregate := Result{Status: StatusServing, CheckedAt: now}
+	for i, probe := range c.probes {
+		result := Result{Status: StatusServing, CheckedAt: now}
+		if errs[i] != nil {
+			result.Status = StatusNotServing
+			result.Error = errs[i].Error()
+			if !probe.Optional {
+				aggregate.Status = StatusNotServing
+				aggregate.Error = probe.Name + ": " + result.Error
+			}
+		}
+		c.set(probe.Name, result)
+	}
+	for _, name := range c.aggregates {
+		c.set(name, aggregate)
+	}
+}
+
+// Shutdown 将所有服务标记为 NOT_SERVING 并停止后续更新，用于优雅关闭前摘除流量
+func (c *Checker) Shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := Result{Status: StatusNotServing, Error: "shutting down", CheckedAt: time.Now()}
+	for name := range c.results {
+		c.setLocked(name, result)
+	}
+	c.shutdown = true
+}
+
+// SetStatus 设置由外部维护的服务状态（例如注册中心的注册状态）
+// 这类服务可以通过 Check/Watch 和 /readyz 查询，但不影响整体状态
+func (c *Checker) SetStatus(service string, status Status, reason string) {
+	c.set(service, Result{Status: status, Error: reason, CheckedAt: time.Now()})
+}
+
+func (c *Checker) set(service string, result Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.shutdown {
+		return
+	}
+	c.setLocked(service, result)
+}
+
+func (c *Checker) setLocked(service string, result Result) {
+	previous, ok := c.results[service]
+	c.results[service] = result
+	if ok && previous.Status == result.Status {
+		return
+	}
+
+	if result.Status == StatusNotServing {
+		c.l.Warn("Service not serving", zap.String("service", service), zap.String("error", result.Error))
+	} else {
+		c.l.Info("Service status changed", zap.String("service", service), zap.Stringer("status", result.Status))
+	}
+	for ch := range c.watchers[service] {
+		notify(ch, result.Status)
+	}
+}
+
+// notify 只保留最新的状态，慢速的 Watch 不会阻塞状态更新
+func notify(ch chan Status, status Status) {
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- status
+}
+
+// Result 返回服务最近一次的检查结果，服务未注册时返回 false
+func (c *Checker) Result(service string) (Result, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result, ok := c.results[service]
+	return result, ok
+}
+
+// Results 返回所有服务最近一次的检查结果
+func (c *Checker) Results() map[string]Result {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	results := make(map[string]Result, len(c.results))
+	for name, result := range c.results {
+		results[name] = result
+	}
+	return results
+}
+
+// ProbeResults 返回每个 Probe 最近一次的检查结果，不包含聚合服务
+func (c *Checker) ProbeResults() map[string]Result {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	results := make(map[string]Result, len(c.probes))
+	for _, probe := range c.probes {
+		results[probe.Name] = c.results[probe.Name]
+	}
+	return results
+}
+
+// Services 返回所有已注册的服务名（按字典序）
+func (c *Checker) Services() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.results))
+	for name := range c.results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// watch 订阅服务的状态变化，返回的 channel 会先收到当前状态
+func (c *Checker) watch(service string) (<-chan Status, func()) {
+	ch := make(chan Status, 1)
+
+	c.mu.Lock()
+	if c.watchers[service] == nil {
+		c.watchers[service] = make(map[chan Status]struct{})
+	}
+	c.watchers[service][ch] = struct{}{}
+	if result, ok := c.results[service]; ok {
+		ch <- result.Status
+	} else {
+		ch <- StatusServiceUnknown
+	}
+	c.mu.Unlock()
+
+	return ch, func() {
+		c.mu.Lock()
+		delete(c.watchers[service], ch)
+		c.mu.Unlock()
+	}
+}
@@ -0,0 +1,223 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	healthv1 "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HealthTestSuite 是健康检查的测试套件
+type HealthTestSuite struct {
+	suite.Suite
+	redisDown  atomic.Bool
+	searchDown atomic.Bool
+	checker    *Checker
+	server     *httptest.Server
+}
+
+func (suite *HealthTestSuite) SetupTest() {
+	suite.redisDown.Store(false)
+	suite.searchDown.Store(false)
+	suite.checker = NewChecker([]Probe{
+		{Name: "postgres", Check: func(ctx context.Context) error { return nil }},
+		{Name: "redis", Check: func(ctx context.Context) error {
+			if suite.redisDown.Load() {
+				return errors.New("connection refused")
+			}
+			return nil
+		}},
+		{Name: "elasticsearch", Optional: true, Check: func(ctx context.Context) error {
+			if suite.searchDown.Load() {
+				return errors.New("no such host")
+			}
+			return nil
+		}},
+	}, []string{"user.v1.UserService"}, time.Hour, time.Second, zap.NewNop())
+
+	mux := http.NewServeMux()
+	mux.Handle(NewHandler(suite.checker))
+	mux.Handle("GET /healthz", LivenessHandler())
+	mux.Handle("GET /readyz", ReadinessHandler(suite.checker))
+	suite.server = httptest.NewServer(mux)
+}
+
+func (suite *HealthTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *HealthTestSuite) check(service string) (Status, error) {
+	client := connect.NewClient[healthv1.HealthCheckRequest, healthv1.HealthCheckResponse](
+		http.DefaultClient, suite.server.URL+CheckProcedure,
+	)
+	resp, err := client.CallUnary(context.Background(), connect.NewRequest(&healthv1.HealthCheckRequest{Service: service}))
+	if err != nil {
+		return StatusUnknown, err
+	}
+	return resp.Msg.GetStatus(), nil
+}
+
+func (suite *HealthTestSuite) TestNotServingBeforeFirstCheck() {
+	status, err := suite.check("")
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), StatusNotServing, status)
+}
+
+func (suite *HealthTestSuite) TestPerServiceStatus() {
+	suite.redisDown.Store(true)
+	suite.checker.CheckNow(context.Background())
+
+	for service, want := range map[string]Status{
+		"":                    StatusNotServing,
+		"user.v1.UserService": StatusNotServing,
+		"postgres":            StatusServing,
+		"redis":               StatusNotServing,
+	} {
+		status, err := suite.check(service)
+		require.NoError(suite.T(), err, service)
+		assert.Equal(suite.T(), want, status, service)
+	}
+
+	_, err := suite.check("unknown.Service")
+	assert.Equal(suite.T(), connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func (suite *HealthTestSuite) TestOptionalProbe() {
+	suite.searchDown.Store(true)
+	suite.checker.CheckNow(context.Background())
+
+	// 可选依赖失败只影响自己的状态
+	for service, want := range map[string]Status{
+		"":                    StatusServing,
+		"user.v1.UserService": StatusServing,
+		"elasticsearch":       StatusNotServing,
+	} {
+		status, err := suite.check(service)
+		require.NoError(suite.T(), err, service)
+		assert.Equal(suite.T(), want, status, service)
+	}
+
+	resp, err := http.Get(suite.server.URL + "/readyz?verbose=true")
+	require.NoError(suite.T(), err)
+	defer resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var body readinessResponse
+	require.NoError(suite.T(), json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(suite.T(), "NOT_SERVING", body.Checks["elasticsearch"].Status)
+}
+
+func (suite *HealthTestSuite) TestWatch() {
+	client := connect.NewClient[healthv1.HealthCheckRequest, healthv1.HealthCheckResponse](
+		http.DefaultClient, suite.server.URL+WatchProcedure,
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.CallServerStream(ctx, connect.NewRequest(&healthv1.HealthCheckRequest{Service: "redis"}))
+	require.NoError(suite.T(), err)
+	defer stream.Close()
+
+	require.True(suite.T(), stream.Receive())
+	assert.Equal(suite.T(), StatusNotServing, stream.Msg().GetStatus())
+
+	suite.checker.CheckNow(context.Background())
+	require.True(suite.T(), stream.Receive())
+	assert.Equal(suite.T(), StatusServing, stream.Msg().GetStatus())
+
+	suite.redisDown.Store(true)
+	suite.checker.CheckNow(context.Background())
+	require.True(suite.T(), stream.Receive())
+	assert.Equal(suite.T(), StatusNotServing, stream.Msg().GetStatus())
+}
+
+func (suite *HealthTestSuite) TestReadiness() {
+	resp, err := http.Get(suite.server.URL + "/readyz")
+	require.NoError(suite.T(), err)
+	resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, resp.StatusCode)
+
+	suite.checker.CheckNow(context.Background())
+	resp, err = http.Get(suite.server.URL + "/readyz")
+	require.NoError(suite.T(), err)
+	var body readinessResponse
+	require.NoError(suite.T(), json.NewDecoder(resp.Body).Decode(&body))
+	resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "SERVING", body.Status)
+	// 默认不列出各个依赖
+	assert.Empty(suite.T(), body.Checks)
+
+	suite.redisDown.Store(true)
+	suite.checker.CheckNow(context.Background())
+	resp, err = http.Get(suite.server.URL + "/readyz?verbose=true")
+	require.NoError(suite.T(), err)
+	defer resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, resp.StatusCode)
+
+	// 详细输出只包含状态，不包含可能泄露内部地址的错误信息
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(suite.T(), err)
+	assert.NotContains(suite.T(), string(raw), "connection refused")
+	body = readinessResponse{}
+	require.NoError(suite.T(), json.Unmarshal(raw, &body))
+	assert.Equal(suite.T(), "NOT_SERVING", body.Checks["redis"].Status)
+	assert.Equal(suite.T(), "SERVING", body.Checks["postgres"].Status)
+}
+
+func (suite *HealthTestSuite) TestSetStatus() {
+	suite.checker.CheckNow(context.Background())
+	suite.checker.SetStatus("registry", StatusNotServing, "agent unavailable")
+
+	// 外部维护的状态可以单独查询，但不影响整体状态
+	status, err := suite.check("registry")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), StatusNotServing, status)
+
+	status, err = suite.check("")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), StatusServing, status)
+
+	resp, err := http.Get(suite.server.URL + "/readyz?verbose=true")
+	require.NoError(suite.T(), err)
+	defer resp.Body.Close()
+	var body readinessResponse
+	require.NoError(suite.T(), json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "NOT_SERVING", body.Checks["registry"].Status)
+	assert.Contains(suite.T(), suite.checker.Services(), "registry")
+}
+
+func (suite *HealthTestSuite) TestShutdown() {
+	suite.checker.CheckNow(context.Background())
+	suite.checker.Shutdown()
+	// 关闭后即使探测成功也保持 NOT_SERVING
+	suite.checker.CheckNow(context.Background())
+
+	status, err := suite.check("")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), StatusNotServing, status)
+
+	resp, err := http.Get(suite.server.URL + "/healthz")
+	require.NoError(suite.T(), err)
+	resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+}
+
+// 运行测试套件
+func TestHealthTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthTestSuite))
+}
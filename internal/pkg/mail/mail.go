@@ -0,0 +1,95 @@
+// Package mail 发送事务性邮件，例如重置密码和验证邮箱
+// 通过 Mailer 接口屏蔽具体的投递方式：生产环境使用 SMTP，开发环境只写日志，测试使用内存实现断言发出的邮件
+package mail
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	confv1 "connect-go-example/internal/conf/v1"
+
+	"go.uber.org/zap"
+)
+
+// Message 一封纯文本邮件
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+}
+
+// Mailer 邮件投递接口
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New 根据配置创建 Mailer，未配置时只写日志
+func New(cfg *confv1.Mail, logger *zap.Logger) (Mailer, error) {
+	switch cfg.GetDriver() {
+	case "", "log":
+		return NewLogMailer(logger), nil
+	case "memory":
+		return NewMemoryMailer(), nil
+	case "smtp":
+		if cfg.GetSmtp().GetHost() == "" {
+			return nil, fmt.Errorf("mail: smtp host is required")
+		}
+		if cfg.GetFrom() == "" {
+			return nil, fmt.Errorf("mail: from is required")
+		}
+		return NewSMTPMailer(cfg.GetSmtp(), cfg.GetFrom()), nil
+	default:
+		return nil, fmt.Errorf("mail: unsupported driver %q", cfg.GetDriver())
+	}
+}
+
+// LogMailer 只把邮件写入日志，用于本地开发
+// 日志中包含完整的正文（其中可能有一次性令牌），不要在生产环境使用
+type LogMailer struct {
+	logger *zap.Logger
+}
+
+func NewLogMailer(logger *zap.Logger) *LogMailer {
+	return &LogMailer{logger: logger}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	m.logger.Info("Mail not delivered, logging only",
+		zap.Strings("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("text", msg.Text),
+	)
+	return nil
+}
+
+// MemoryMailer 把邮件保存在内存中，用于测试
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages 返回已发送邮件的副本
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.messages...)
+}
+
+// Reset 清空已发送的邮件
+func (m *MemoryMailer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = nil
+}
@@ -0,0 +1,209 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	confv1 "connect-go-example/internal/conf/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *confv1.Mail
+		want    Mailer
+		wantErr bool
+	}{
+		{"not configured", nil, &LogMailer{}, false},
+		{"log", &confv1.Mail{Driver: "log"}, &LogMailer{}, false},
+		{"memory", &confv1.Mail{Driver: "memory"}, &MemoryMailer{}, false},
+		{"smtp", &confv1.Mail{Driver: "smtp", From: "no-reply@example.com", Smtp: &confv1.Mail_SMTP{Host: "smtp.example.com"}}, &SMTPMailer{}, false},
+		{"smtp without host", &confv1.Mail{Driver: "smtp", From: "no-reply@example.com"}, nil, true},
+		{"unknown driver", &confv1.Mail{Driver: "sendmail"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mailer, err := New(tt.cfg, zap.NewNop())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, tt.want, mailer)
+		})
+	}
+}
+
+func TestNewSMTPMailer_DefaultPort(t *testing.T) {
+	m := NewSMTPMailer(&confv1.Mail_SMTP{Host: "smtp.example.com"}, "no-reply@example.com")
+	assert.Equal(t, "smtp.example.com:587", m.addr)
+	assert.Nil(t, m.auth)
+}
+
+func TestMemoryMailer(t *testing.T) {
+	m := NewMemoryMailer()
+	require.NoError(t, m.Send(context.Background(), Message{To: []string{"alice@example.com"}, Subject: "hello"}))
+
+	messages := m.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "hello", messages[0].Subject)
+
+	// 返回的是副本
+	messages[0].Subject = "changed"
+	assert.Equal(t, "hello", m.Messages()[0].Subject)
+
+	m.Reset()
+	assert.Empty(t, m.Messages())
+}
+
+func TestBuildMessage(t *testing.T) {
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	data, err := buildMessage("Example <no-reply@example.com>", Message{
+		To:      []string{"alice@example.com", "bob@example.com"},
+		Subject: "重置密码",
+		Text:    "Hi alice,\nopen https://example.com/reset?token=abc\n",
+	}, now)
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	assert.Equal(t, "Example <no-reply@example.com>", msg.Header.Get("From"))
+	assert.Equal(t, "alice@example.com, bob@example.com", msg.Header.Get("To"))
+	assert.Equal(t, "=?utf-8?q?=E9=87=8D=E7=BD=AE=E5=AF=86=E7=A0=81?=", msg.Header.Get("Subject"))
+	assert.Equal(t, "Wed, 01 May 2024 08:00:00 +0000", msg.Header.Get("Date"))
+	assert.True(t, strings.HasSuffix(msg.Header.Get("Message-ID"), "@example.com>"))
+
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	require.NoError(t, err)
+	assert.Equal(t, "Hi alice,\r\nopen https://example.com/reset?token=abc\r\n", string(body))
+}
+
+func TestBuildMessage_Invalid(t *testing.T) {
+	_, err := buildMessage("no-reply@example.com", Message{Subject: "hello"}, time.Now())
+	assert.Error(t, err)
+
+	// 收件人中的换行会注入额外的邮件头
+	_, err = buildMessage("no-reply@example.com", Message{To: []string{"alice@example.com\r\nBcc: mallory@example.com"}}, time.Now())
+	assert.Error(t, err)
+}
+
+// fakeSMTPServer 只实现发送一封邮件所需命令的 SMTP 服务器，不支持 STARTTLS 和认证
+type fakeSMTPServer struct {
+	ln net.Listener
+
+	mu   sync.Mutex
+	from string
+	rcpt []string
+	data string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	s := &fakeSMTPServer{ln: ln}
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTPServer) serve() {
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		s.mu.Lock()
+		switch cmd {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case "MAIL":
+			s.from = line
+			reply("250 OK")
+		case "RCPT":
+			s.rcpt = append(s.rcpt, line)
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil || l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.data = data.String()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			s.mu.Unlock()
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+		s.mu.Unlock()
+	}
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	host, port, err := net.SplitHostPort(server.ln.Addr().String())
+	require.NoError(t, err)
+	portNum, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	m := NewSMTPMailer(&confv1.Mail_SMTP{Host: host, Port: int32(portNum)}, "Example <no-reply@example.com>")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = m.Send(ctx, Message{
+		To:      []string{"alice@example.com"},
+		Subject: "Verify your email address",
+		Text:    "token=abc",
+	})
+	require.NoError(t, err)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Equal(t, "MAIL FROM:<no-reply@example.com> BODY=8BITMIME", server.from)
+	assert.Equal(t, []string{"RCPT TO:<alice@example.com>"}, server.rcpt)
+	assert.Contains(t, server.data, "Subject: Verify your email address\r\n")
+	assert.Contains(t, server.data, "token=3Dabc")
+}
+
+func TestSMTPMailer_DialError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().(*net.TCPAddr)
+	ln.Close()
+
+	m := NewSMTPMailer(&confv1.Mail_SMTP{Host: "127.0.0.1", Port: int32(addr.Port)}, "no-reply@example.com")
+	err = m.Send(context.Background(), Message{To: []string{"alice@example.com"}, Subject: "hello"})
+	assert.ErrorContains(t, err, "dial")
+}
@@ -0,0 +1,164 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	confv1 "connect-go-example/internal/conf/v1"
+)
+
+// defaultSMTPPort 提交端口，连接后通过 STARTTLS 升级加密
+const defaultSMTPPort = 587
+
+// SMTPMailer 通过 SMTP 投递邮件
+// 服务器支持 STARTTLS 时总是升级加密；配置了用户名时使用 PLAIN 认证，net/smtp 只允许在加密连接或本机上发送密码
+type SMTPMailer struct {
+	host string
+	addr string
+	from string
+	auth smtp.Auth
+	// tlsConfig 为 nil 时使用默认配置，测试中替换
+	tlsConfig *tls.Config
+}
+
+func NewSMTPMailer(cfg *confv1.Mail_SMTP, from string) *SMTPMailer {
+	port := int(cfg.GetPort())
+	if port <= 0 {
+		port = defaultSMTPPort
+	}
+
+	m := &SMTPMailer{
+		host: cfg.GetHost(),
+		addr: net.JoinHostPort(cfg.GetHost(), strconv.Itoa(port)),
+		from: from,
+	}
+	if cfg.GetUsername() != "" {
+		m.auth = smtp.PlainAuth("", cfg.GetUsername(), cfg.GetPassword(), cfg.GetHost())
+	}
+	return m
+}
+
+// Send 投递邮件，ctx 的截止时间同时作用于建立连接和整个会话
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	from, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return fmt.Errorf("smtp: invalid from address: %w", err)
+	}
+	data, err := buildMessage(m.from, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return fmt.Errorf("smtp: dial %s failed: %w", m.addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		tlsConfig := m.tlsConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{ServerName: m.host}
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("smtp: starttls failed: %w", err)
+		}
+	}
+	if m.auth != nil {
+		if err := c.Auth(m.auth); err != nil {
+			return fmt.Errorf("smtp: auth failed: %w", err)
+		}
+	}
+
+	if err := c.Mail(from.Address); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	for _, to := range msg.To {
+		if err := c.Rcpt(to); err != nil {
+			return fmt.Errorf("smtp: rcpt %s failed: %w", to, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("smtp: write message failed: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return c.Quit()
+}
+
+// buildMessage 生成 RFC 5322 格式的邮件，主题按 RFC 2047 编码，正文使用 quoted-printable
+func buildMessage(from string, msg Message, now time.Time) ([]byte, error) {
+	if len(msg.To) == 0 {
+		return nil, fmt.Errorf("smtp: no recipients")
+	}
+	for _, to := range msg.To {
+		// 收件人来自用户输入，拒绝包含换行的地址，防止头部注入
+		if strings.ContainsAny(to, "\r\n") {
+			return nil, fmt.Errorf("smtp: invalid recipient %q", to)
+		}
+	}
+
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+	header("From", from)
+	header("To", strings.Join(msg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", messageID(from))
+	header("MIME-Version", "1.0")
+	header("Content-Type", `text/plain; charset="utf-8"`)
+	header("Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	if _, err := w.Write([]byte(strings.ReplaceAll(msg.Text, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// messageID 生成随机的 Message-ID，域名取发件人地址的域名
+func messageID(from string) string {
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(addr.Address, "@"); at >= 0 {
+			domain = addr.Address[at+1:]
+		}
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
@@ -36,9 +36,23 @@ var (
 				return SetupOTelSDK(context.Background(), info, cfg, logger)
 			},
 		),
+		fx.Invoke(registerShutdown),
 	)
 )
 
+// registerShutdown 在模块初始化时注册 OnStop 钩子，早于数据等模块注册，
+// fx 按逆序执行 OnStop，因此 OTel 在其他组件关闭之后最后刷新，不会丢失关闭过程中产生的数据
+func registerShutdown(lc fx.Lifecycle, shutdown func(context.Context) error, logger *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			if err := shutdown(ctx); err != nil {
+				logger.Error("Failed to shutdown OTel", zap.Error(err))
+			}
+			return nil
+		},
+	})
+}
+
 // SetEndpoint 从配置中设置端点
 func SetEndpoint(cfg *confv1.Trace, logger *zap.Logger) {
 	if cfg != nil && cfg.Endpoint != "" {
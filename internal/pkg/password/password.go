@@ -0,0 +1,83 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// argon2id 参数，参考 OWASP Password Storage Cheat Sheet 的推荐值
+const (
+	argonTime    uint32 = 3
+	argonMemory  uint32 = 64 * 1024 // KiB
+	argonThreads uint8  = 2
+	argonKeyLen  uint32 = 32
+	saltLen             = 16
+)
+
+var ErrInvalidHash = errors.New("invalid password hash format")
+
+// Hash 使用 argon2id 对密码进行哈希
+// 返回 PHC 格式的哈希串（$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>）以及 base64 编码的盐值
+func Hash(password string) (encoded string, salt string, err error) {
+	rawSalt := make([]byte, saltLen)
+	if _, err := rand.Read(rawSalt); err != nil {
+		return "", "", fmt.Errorf("generate salt failed: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), rawSalt, argonTime, argonMemory, argonThreads, argonKeyLen)
+
+	salt = base64.RawStdEncoding.EncodeToString(rawSalt)
+	encoded = fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		argonMemory,
+		argonTime,
+		argonThreads,
+		salt,
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+	return encoded, salt, nil
+}
+
+// Verify 校验密码是否与 PHC 格式的哈希串匹配
+// 参数从哈希串中解析，因此调整上面的参数后旧密码依然可以校验
+func Verify(password, encoded string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return false, ErrInvalidHash
+	}
+	if version != argon2.Version {
+		return false, fmt.Errorf("unsupported argon2 version: %d", version)
+	}
+
+	var (
+		memory  uint32
+		time    uint32
+		threads uint8
+	)
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, ErrInvalidHash
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, ErrInvalidHash
+	}
+
+	got := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(want)))
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
@@ -0,0 +1,52 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashAndVerify(t *testing.T) {
+	encoded, salt, err := Hash("correct horse battery staple")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=65536,t=3,p=2$"))
+	assert.Contains(t, encoded, salt)
+
+	ok, err := Verify("correct horse battery staple", encoded)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = Verify("wrong password", encoded)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestHash_UniqueSalt(t *testing.T) {
+	a, _, err := Hash("password")
+	require.NoError(t, err)
+	b, _, err := Hash("password")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+}
+
+func TestVerify_InvalidHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"empty", ""},
+		{"bcrypt", "$2a$10$abcdefghijklmnopqrstuv"},
+		{"bad params", "$argon2id$v=19$m=x,t=3,p=2$c2FsdA$aGFzaA"},
+		{"bad salt", "$argon2id$v=19$m=65536,t=3,p=2$!!!$aGFzaA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Verify("password", tt.encoded)
+			assert.ErrorIs(t, err, ErrInvalidHash)
+		})
+	}
+}
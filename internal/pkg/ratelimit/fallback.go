@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+const (
+	// primaryTimeout 单次调用主限流器的超时，避免 Redis 阻塞时拖慢请求
+	primaryTimeout = 100 * time.Millisecond
+	// retryInterval 主限流器失败后，在这段时间内直接使用备用限流器
+	retryInterval = time.Second
+)
+
+// FallbackLimiter 优先使用 primary，失败时改用 fallback
+// 退化期间每个实例单独计数，整体允许的请求数会随实例数增加
+type FallbackLimiter struct {
+	primary  Limiter
+	fallback Limiter
+	logger   *zap.Logger
+
+	// retryAt 在此时间（UnixNano）之前跳过 primary，为 0 表示 primary 正常
+	retryAt   atomic.Int64
+	fallbacks metric.Int64Counter
+	now       func() time.Time
+}
+
+func NewFallbackLimiter(primary, fallback Limiter, logger *zap.Logger) *FallbackLimiter {
+	meter := otel.GetMeterProvider().Meter("github.com/sunmery/ecommerce/backend/ratelimit")
+
+	fallbacks, err := meter.Int64Counter(
+		"ratelimit.fallbacks",
+		metric.WithDescription("Number of rate limit decisions made by the in-process limiter"),
+		metric.WithUnit("{decision}"),
+	)
+	if err != nil {
+		panic(fmt.Errorf("failed to init counter: %w", err))
+	}
+
+	return &FallbackLimiter{
+		primary:   primary,
+		fallback:  fallback,
+		logger:    logger,
+		fallbacks: fallbacks,
+		now:       time.Now,
+	}
+}
+
+func (f *FallbackLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if retryAt := f.retryAt.Load(); retryAt == 0 || f.now().UnixNano() >= retryAt {
+		primaryCtx, cancel := context.WithTimeout(ctx, primaryTimeout)
+		res, err := f.primary.Allow(primaryCtx, key, limit)
+		cancel()
+		if err == nil {
+			if f.retryAt.Swap(0) != 0 {
+				f.logger.Info("Rate limiter recovered, using shared limits again")
+			}
+			return res, nil
+		}
+		if ctx.Err() != nil {
+			return Result{}, ctx.Err()
+		}
+		if f.retryAt.Swap(f.now().Add(retryInterval).UnixNano()) == 0 {
+			f.logger.Warn("Rate limiter unavailable, falling back to in-process limits", zap.Error(err))
+		}
+	}
+
+	f.fallbacks.Add(ctx, 1)
+	return f.fallback.Allow(ctx, key, limit)
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval 清理已过期 key 的间隔
+const sweepInterval = time.Minute
+
+// LocalLimiter 进程内的限流器，计数只在当前实例内有效
+type LocalLimiter struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time // key -> 理论到达时间
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewLocalLimiter() *LocalLimiter {
+	return &LocalLimiter{
+		tats: make(map[string]time.Time),
+		now:  time.Now,
+	}
+}
+
+func (l *LocalLimiter) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	tat, ok := l.tats[key]
+	if !ok || tat.Before(now) {
+		tat = now
+	}
+
+	interval := limit.interval()
+	newTat := tat.Add(interval)
+	allowAt := newTat.Add(-limit.tolerance())
+	if allowAt.After(now) {
+		return Result{RetryAfter: allowAt.Sub(now)}, nil
+	}
+
+	l.tats[key] = newTat
+	return Result{
+		Allowed:   true,
+		Remaining: int64(now.Sub(allowAt) / interval),
+	}, nil
+}
+
+// sweep 删除理论到达时间已经过去的 key，这些 key 的状态与不存在时相同
+func (l *LocalLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, tat := range l.tats {
+		if tat.Before(now) {
+			delete(l.tats, key)
+		}
+	}
+}
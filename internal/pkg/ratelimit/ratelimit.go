@@ -0,0 +1,41 @@
+// Package ratelimit 基于 GCRA（通用信元速率算法）的限流器
+//
+// RedisLimiter 在 Redis 中用 Lua 脚本原子地维护每个 key 的理论到达时间，多个实例共享同一份计数；
+// LocalLimiter 在进程内维护同样的状态；FallbackLimiter 在 Redis 不可用时临时退化为进程内限流。
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit 限流参数：每个 Period 允许 Rate 个请求，最多可以一次性突发 Burst 个请求
+type Limit struct {
+	Rate   int64
+	Period time.Duration
+	Burst  int64
+}
+
+// interval 两个请求之间的平均间隔
+func (l Limit) interval() time.Duration {
+	return max(l.Period/time.Duration(l.Rate), time.Microsecond)
+}
+
+// tolerance 允许请求提前到达的时间，决定突发容量
+func (l Limit) tolerance() time.Duration {
+	return l.interval() * time.Duration(l.Burst)
+}
+
+// Result 一次限流判定的结果
+type Result struct {
+	Allowed bool
+	// Remaining 当前还可以立即通过的请求数
+	Remaining int64
+	// RetryAfter 被拒绝时，距离下一个请求可以通过的时间
+	RetryAfter time.Duration
+}
+
+// Limiter 判断 key 对应的调用方能否再发起一个请求
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
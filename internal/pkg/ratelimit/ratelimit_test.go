@@ -0,0 +1,173 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// 每秒 2 个请求，最多突发 3 个
+var testLimit = Limit{Rate: 2, Period: time.Second, Burst: 3}
+
+// clockedLimiter 可以控制时间的限流器
+type clockedLimiter struct {
+	Limiter
+	advance func(d time.Duration)
+}
+
+func newLocal(t *testing.T) clockedLimiter {
+	now := time.Unix(1_700_000_000, 0)
+	l := NewLocalLimiter()
+	l.now = func() time.Time { return now }
+	return clockedLimiter{Limiter: l, advance: func(d time.Duration) { now = now.Add(d) }}
+}
+
+func newRedis(t *testing.T) clockedLimiter {
+	mr := miniredis.RunT(t)
+	now := time.Unix(1_700_000_000, 0)
+	mr.SetTime(now)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return clockedLimiter{Limiter: NewRedisLimiter(rdb), advance: func(d time.Duration) {
+		now = now.Add(d)
+		mr.SetTime(now)
+	}}
+}
+
+func TestLimiter(t *testing.T) {
+	limiters := map[string]func(t *testing.T) clockedLimiter{
+		"local": newLocal,
+		"redis": newRedis,
+	}
+	for name, newLimiter := range limiters {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			t.Run("burst then reject", func(t *testing.T) {
+				l := newLimiter(t)
+				for i := range 3 {
+					res, err := l.Allow(ctx, "k", testLimit)
+					require.NoError(t, err)
+					assert.True(t, res.Allowed, "request %d", i)
+					assert.Equal(t, int64(2-i), res.Remaining)
+				}
+
+				res, err := l.Allow(ctx, "k", testLimit)
+				require.NoError(t, err)
+				assert.False(t, res.Allowed)
+				assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+			})
+
+			t.Run("refill after interval", func(t *testing.T) {
+				l := newLimiter(t)
+				for range 3 {
+					_, err := l.Allow(ctx, "k", testLimit)
+					require.NoError(t, err)
+				}
+
+				l.advance(500 * time.Millisecond)
+				res, err := l.Allow(ctx, "k", testLimit)
+				require.NoError(t, err)
+				assert.True(t, res.Allowed)
+				assert.Equal(t, int64(0), res.Remaining)
+
+				res, err = l.Allow(ctx, "k", testLimit)
+				require.NoError(t, err)
+				assert.False(t, res.Allowed)
+			})
+
+			t.Run("keys are independent", func(t *testing.T) {
+				l := newLimiter(t)
+				limit := Limit{Rate: 1, Period: time.Minute, Burst: 1}
+				res, err := l.Allow(ctx, "a", limit)
+				require.NoError(t, err)
+				assert.True(t, res.Allowed)
+
+				res, err = l.Allow(ctx, "b", limit)
+				require.NoError(t, err)
+				assert.True(t, res.Allowed)
+
+				res, err = l.Allow(ctx, "a", limit)
+				require.NoError(t, err)
+				assert.False(t, res.Allowed)
+				assert.Equal(t, time.Minute, res.RetryAfter)
+			})
+		})
+	}
+}
+
+func TestLocalLimiter_Sweep(t *testing.T) {
+	l := NewLocalLimiter()
+	now := time.Unix(1_700_000_000, 0)
+	l.now = func() time.Time { return now }
+
+	_, err := l.Allow(context.Background(), "k", testLimit)
+	require.NoError(t, err)
+	require.Len(t, l.tats, 1)
+
+	now = now.Add(2 * sweepInterval)
+	_, err = l.Allow(context.Background(), "other", testLimit)
+	require.NoError(t, err)
+	assert.NotContains(t, l.tats, "k")
+}
+
+// failingLimiter 模拟 Redis 不可用
+type failingLimiter struct {
+	calls int
+	err   error
+}
+
+func (f *failingLimiter) Allow(context.Context, string, Limit) (Result, error) {
+	f.calls++
+	if f.err != nil {
+		return Result{}, f.err
+	}
+	return Result{Allowed: true, Remaining: 99}, nil
+}
+
+func TestFallbackLimiter(t *testing.T) {
+	ctx := context.Background()
+	primary := &failingLimiter{err: errors.New("connection refused")}
+	now := time.Unix(1_700_000_000, 0)
+	f := NewFallbackLimiter(primary, NewLocalLimiter(), zap.NewNop())
+	f.now = func() time.Time { return now }
+
+	// primary 失败后改用进程内限流，仍然生效
+	limit := Limit{Rate: 1, Period: time.Minute, Burst: 1}
+	res, err := f.Allow(ctx, "k", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	res, err = f.Allow(ctx, "k", limit)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+
+	// 退化期间不再尝试 primary
+	assert.Equal(t, 1, primary.calls)
+
+	// 超过重试间隔后重新使用 primary
+	primary.err = nil
+	now = now.Add(retryInterval)
+	res, err = f.Allow(ctx, "k", limit)
+	require.NoError(t, err)
+	assert.Equal(t, int64(99), res.Remaining)
+	assert.Equal(t, 2, primary.calls)
+	assert.Zero(t, f.retryAt.Load())
+}
+
+func TestFallbackLimiter_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	f := NewFallbackLimiter(&failingLimiter{err: context.Canceled}, NewLocalLimiter(), zap.NewNop())
+	_, err := f.Allow(ctx, "k", testLimit)
+	assert.ErrorIs(t, err, context.Canceled)
+	// 调用方取消不代表 primary 不可用
+	assert.Zero(t, f.retryAt.Load())
+}
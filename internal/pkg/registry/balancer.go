@@ -0,0 +1,145 @@
+package registry
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// 负载均衡策略
+const (
+	BalancerRoundRobin    = "round_robin"
+	BalancerLeastRequests = "least_requests"
+)
+
+// Balancer 从健康实例中选择一个处理请求
+type Balancer interface {
+	// Pick 选择一个实例，返回的 done 必须在请求结束（响应体关闭）后调用
+	Pick(instances []Instance) (Instance, func())
+}
+
+// NewBalancer 按策略名创建 Balancer，空字符串使用轮询
+func NewBalancer(policy string) (Balancer, error) {
+	switch policy {
+	case "", BalancerRoundRobin:
+		return NewRoundRobinBalancer(), nil
+	case BalancerLeastRequests:
+		return NewLeastRequestsBalancer(), nil
+	default:
+		return nil, fmt.Errorf("unsupported balancer %q", policy)
+	}
+}
+
+type roundRobinBalancer struct {
+	next atomic.Uint64
+}
+
+// NewRoundRobinBalancer 依次选择每个实例
+func NewRoundRobinBalancer() Balancer {
+	return &roundRobinBalancer{}
+}
+
+func (b *roundRobinBalancer) Pick(instances []Instance) (Instance, func()) {
+	n := b.next.Add(1) - 1
+	return instances[n%uint64(len(instances))], func() {}
+}
+
+type leastRequestsBalancer struct {
+	mu       sync.Mutex
+	inflight map[string]int
+}
+
+// NewLeastRequestsBalancer 选择进行中请求最少的实例，数量相同时随机选择
+func NewLeastRequestsBalancer() Balancer {
+	return &leastRequestsBalancer{inflight: make(map[string]int)}
+}
+
+func (b *leastRequestsBalancer) Pick(instances []Instance) (Instance, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// 从随机位置开始扫描，避免请求数相同时总是选中第一个实例
+	start := rand.IntN(len(instances))
+	picked := instances[start]
+	for i := 1; i < len(instances); i++ {
+		instance := instances[(start+i)%len(instances)]
+		if b.inflight[instance.ID] < b.inflight[picked.ID] {
+			picked = instance
+		}
+	}
+	b.inflight[picked.ID]++
+
+	var once sync.Once
+	return picked, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if b.inflight[picked.ID]--; b.inflight[picked.ID] <= 0 {
+				delete(b.inflight, picked.ID)
+			}
+		})
+	}
+}
+
+// balancingTransport 将请求发送到 Watcher 提供的实例
+type balancingTransport struct {
+	watcher  Watcher
+	balancer Balancer
+	base     http.RoundTripper
+}
+
+// NewRoundTripper 返回按实例负载均衡的 http.RoundTripper，请求 URL 中的 host 会被替换为选中实例的地址
+// base 为 nil 时使用支持 h2c 的默认 Transport，可以直接用于 Connect/gRPC 客户端
+func NewRoundTripper(watcher Watcher, balancer Balancer, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		p := new(http.Protocols)
+		p.SetHTTP1(true)
+		p.SetUnencryptedHTTP2(true)
+		transport.Protocols = p
+		base = transport
+	}
+	return &balancingTransport{watcher: watcher, balancer: balancer, base: base}
+}
+
+// NewHTTPClient 返回可以传给 connect.NewClient 的 HTTP 客户端，baseURL 的 host 可以是任意占位符（例如服务名）
+func NewHTTPClient(watcher Watcher, balancer Balancer) *http.Client {
+	return &http.Client{Transport: NewRoundTripper(watcher, balancer, nil)}
+}
+
+func (t *balancingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	instances := t.watcher.Instances()
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("%s: %w", req.URL.Host, ErrNoInstances)
+	}
+
+	instance, done := t.balancer.Pick(instances)
+
+	// RoundTripper 不能修改原请求
+	out := req.Clone(req.Context())
+	out.URL.Host = instance.Addr()
+	out.Host = ""
+
+	resp, err := t.base.RoundTrip(out)
+	if err != nil {
+		done()
+		return nil, err
+	}
+	// 流式响应在响应体关闭时才算结束
+	resp.Body = &doneBody{ReadCloser: resp.Body, done: done}
+	return resp, nil
+}
+
+type doneBody struct {
+	io.ReadCloser
+	done func()
+}
+
+func (b *doneBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.done()
+	return err
+}
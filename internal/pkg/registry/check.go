@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	confv1 "connect-go-example/internal/conf/v1"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// Consul 健康检查模式
+const (
+	CheckModeTTL  = "ttl"
+	CheckModeHTTP = "http"
+	CheckModeGRPC = "grpc"
+)
+
+// 健康检查的默认配置
+const (
+	defaultCheckInterval   = TtlPingInterval
+	defaultCheckTimeout    = 5 * time.Second
+	defaultDeregisterAfter = time.Minute
+	defaultCheckHTTPPath   = "/readyz"
+	ttlIntervalMultiplier  = 3 // TTL 为心跳间隔的倍数，允许丢失两次心跳
+)
+
+// CheckSettings 注册时使用的健康检查配置
+type CheckSettings struct {
+	Mode            string
+	Interval        time.Duration
+	Timeout         time.Duration
+	DeregisterAfter time.Duration
+	HTTPPath        string
+	GRPCService     string
+}
+
+// NewCheckSettings 从配置生成健康检查配置，未配置的项使用默认值
+func NewCheckSettings(cfg *confv1.Discovery_Consul) (CheckSettings, error) {
+	check := cfg.GetCheck()
+	s := CheckSettings{
+		Mode:            check.GetMode(),
+		Interval:        defaultCheckInterval,
+		Timeout:         defaultCheckTimeout,
+		DeregisterAfter: defaultDeregisterAfter,
+		HTTPPath:        defaultCheckHTTPPath,
+		GRPCService:     check.GetGrpcService(),
+	}
+
+	if s.Mode == "" {
+		s.Mode = CheckModeTTL
+		if cfg.GetHealthCheck() {
+			s.Mode = CheckModeHTTP
+		}
+	}
+	switch s.Mode {
+	case CheckModeTTL, CheckModeHTTP, CheckModeGRPC:
+	default:
+		return CheckSettings{}, fmt.Errorf("unsupported consul check mode %q", s.Mode)
+	}
+
+	if v := check.GetInterval(); v > 0 {
+		s.Interval = time.Duration(v) * time.Second
+	}
+	if v := check.GetTimeout(); v > 0 {
+		s.Timeout = time.Duration(v) * time.Second
+	}
+	if v := check.GetDeregisterCriticalAfter(); v > 0 {
+		s.DeregisterAfter = time.Duration(v) * time.Second
+	}
+	if v := check.GetHttpPath(); v != "" {
+		s.HTTPPath = v
+	}
+	return s, nil
+}
+
+// TTL ttl 模式下 Consul 等待心跳的时间
+func (s CheckSettings) TTL() time.Duration {
+	return ttlIntervalMultiplier * s.Interval
+}
+
+// AgentServiceCheck 生成对应模式的 Consul 检查定义，host/port 为 Consul 访问本实例的地址
+func (s CheckSettings) AgentServiceCheck(host string, port int) *api.AgentServiceCheck {
+	check := &api.AgentServiceCheck{
+		// 检查持续失败后自动注销
+		DeregisterCriticalServiceAfter: s.DeregisterAfter.String(),
+	}
+
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	switch s.Mode {
+	case CheckModeHTTP:
+		check.HTTP = "http://" + addr + s.HTTPPath
+		check.Method = "GET"
+		check.Interval = s.Interval.String()
+		check.Timeout = s.Timeout.String()
+	case CheckModeGRPC:
+		// Connect 服务使用 h2c，Consul 以明文 HTTP/2 调用 grpc.health.v1.Health/Check
+		check.GRPC = addr
+		if s.GRPCService != "" {
+			check.GRPC += "/" + s.GRPCService
+		}
+		check.Interval = s.Interval.String()
+		check.Timeout = s.Timeout.String()
+	default:
+		check.TTL = s.TTL().String()
+	}
+	return check
+}
@@ -0,0 +1,113 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	confv1 "connect-go-example/internal/conf/v1"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewCheckSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *confv1.Discovery_Consul
+		want CheckSettings
+	}{
+		{
+			name: "defaults to ttl",
+			cfg:  &confv1.Discovery_Consul{},
+			want: CheckSettings{Mode: CheckModeTTL, Interval: 10 * time.Second, Timeout: 5 * time.Second, DeregisterAfter: time.Minute, HTTPPath: "/readyz"},
+		},
+		{
+			name: "legacy health_check enables http",
+			cfg:  &confv1.Discovery_Consul{HealthCheck: true},
+			want: CheckSettings{Mode: CheckModeHTTP, Interval: 10 * time.Second, Timeout: 5 * time.Second, DeregisterAfter: time.Minute, HTTPPath: "/readyz"},
+		},
+		{
+			name: "explicit grpc",
+			cfg: &confv1.Discovery_Consul{HealthCheck: true, Check: &confv1.Discovery_Check{
+				Mode: "grpc", Interval: 5, Timeout: 1, DeregisterCriticalAfter: 300, GrpcService: "user.v1.UserService",
+			}},
+			want: CheckSettings{Mode: CheckModeGRPC, Interval: 5 * time.Second, Timeout: time.Second, DeregisterAfter: 5 * time.Minute, HTTPPath: "/readyz", GRPCService: "user.v1.UserService"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCheckSettings(tt.cfg)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := NewCheckSettings(&confv1.Discovery_Consul{Check: &confv1.Discovery_Check{Mode: "tcp"}})
+	assert.Error(t, err)
+}
+
+func TestCheckSettings_AgentServiceCheck(t *testing.T) {
+	base := CheckSettings{Interval: 10 * time.Second, Timeout: 2 * time.Second, DeregisterAfter: time.Minute, HTTPPath: "/readyz"}
+
+	ttl := base
+	ttl.Mode = CheckModeTTL
+	assert.Equal(t, &api.AgentServiceCheck{TTL: "30s", DeregisterCriticalServiceAfter: "1m0s"}, ttl.AgentServiceCheck("10.0.0.1", 8080))
+
+	httpCheck := base
+	httpCheck.Mode = CheckModeHTTP
+	assert.Equal(t, &api.AgentServiceCheck{
+		HTTP:                           "http://10.0.0.1:8080/readyz",
+		Method:                         "GET",
+		Interval:                       "10s",
+		Timeout:                        "2s",
+		DeregisterCriticalServiceAfter: "1m0s",
+	}, httpCheck.AgentServiceCheck("10.0.0.1", 8080))
+
+	grpcCheck := base
+	grpcCheck.Mode = CheckModeGRPC
+	grpcCheck.GRPCService = "user.v1.UserService"
+	assert.Equal(t, &api.AgentServiceCheck{
+		GRPC:                           "10.0.0.1:8080/user.v1.UserService",
+		Interval:                       "10s",
+		Timeout:                        "2s",
+		DeregisterCriticalServiceAfter: "1m0s",
+	}, grpcCheck.AgentServiceCheck("10.0.0.1", 8080))
+}
+
+// TestConsulRegistry_RegisterWithHTTPCheck 注册请求携带配置的检查定义
+func TestConsulRegistry_RegisterWithHTTPCheck(t *testing.T) {
+	registrations := make(chan api.AgentServiceRegistration, 1)
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/v1/agent/service/register" {
+			http.NotFound(w, r)
+			return
+		}
+		var reg api.AgentServiceRegistration
+		_ = json.NewDecoder(r.Body).Decode(&reg)
+		registrations <- reg
+	}))
+	defer agent.Close()
+
+	check := CheckSettings{Mode: CheckModeHTTP, Interval: 5 * time.Second, Timeout: time.Second, DeregisterAfter: time.Minute, HTTPPath: "/readyz"}
+	reg, err := NewConsulRegistry(strings.TrimPrefix(agent.URL, "http://"), "instance-1", "user", "v1", 8080, "http", "10.0.0.1", check, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, reg.Register(context.Background()))
+
+	got := <-registrations
+	assert.Equal(t, "user-v1", got.Name)
+	assert.Contains(t, got.Tags, CheckModeHTTP)
+	require.NotNil(t, got.Check)
+	assert.Equal(t, "http://10.0.0.1:8080/readyz", got.Check.HTTP)
+	assert.Equal(t, "5s", got.Check.Interval)
+	assert.Empty(t, got.Check.TTL)
+}
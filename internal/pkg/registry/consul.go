@@ -2,29 +2,65 @@ package registry
 
 import (
 	confv1 "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/health"
 	"connect-go-example/internal/pkg/meta"
 	"context"
+	"errors"
 	"fmt"
-	"net"
-	"os"
-	"strconv"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/consul/api"
-	"go.uber.org/fx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 )
 
-//	TtlDuration 定义了 Consul Agent 期望的心跳时间间隔。
+//	TtlDuration 定义了 Consul Agent 期望的心跳时间间隔，未配置 check 时的默认值。
 // 建议：TTL 持续时间（如 15s）应比心跳间隔（如 5s）长，以提供冗余。
 const (
 	TtlDuration     = "30s"
 	TtlPingInterval = 10 * time.Second
 )
 
+// 重新注册的默认退避时间
+const (
+	defaultReregisterMinBackoff = time.Second
+	defaultReregisterMaxBackoff = 30 * time.Second
+)
+
+// registrationHealthService 在健康检查中上报注册状态使用的服务名
+const registrationHealthService = "registry"
+
+var errDeregistered = errors.New("deregistered")
+
+// StatusReporter 接收注册状态，*health.Checker 满足该接口
+type StatusReporter interface {
+	SetStatus(service string, status health.Status, reason string)
+}
+
 type ConsulRegistry struct {
-	client  *api.Client
-	logger  *zap.Logger
+	client   *api.Client
+	health   HealthSource
+	check    CheckSettings
+	reporter StatusReporter
+	logger   *zap.Logger
+
+	// 重新注册的退避时间
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	registered      atomic.Bool
+	reregistrations metric.Int64Counter
+
+	mu sync.Mutex
+	// stopKeepAlive 停止后台维护并等待其退出
+	stopKeepAlive func()
+
 	ID      string
 	Name    string
 	Version string
@@ -32,72 +68,41 @@ type ConsulRegistry struct {
 	Port    int
 }
 
-// Module 提供 Fx 模块
-var Module = fx.Module("registry",
-	fx.Provide(
-		// 提供 Consul 注册中心（支持优雅降级）
-		func(lc fx.Lifecycle, logger *zap.Logger, conf *confv1.Bootstrap, appInfo meta.AppInfo) (*ConsulRegistry, error) {
-			if os.Getenv("DISABLE_CONSUL") == "true" {
-				logger.Info("Consul disabled by environment variable DISABLE_CONSUL=true")
-				return nil, nil
-			}
+// newConsulFromConfig 根据配置创建 Consul 注册中心
+func newConsulFromConfig(conf *confv1.Bootstrap, appInfo meta.AppInfo, checker *health.Checker, logger *zap.Logger) (*ConsulRegistry, error) {
+	consulAddr := conf.Discovery.Consul.Addr
+	serviceScheme := conf.Discovery.Consul.Scheme
 
-			if conf.Discovery == nil || conf.Discovery.Consul == nil || conf.Discovery.Consul.Addr == "" {
-				logger.Info("Consul not configured, service discovery disabled")
-				return nil, nil
-			}
-
-			consulAddr := conf.Discovery.Consul.Addr
-			serviceScheme := conf.Discovery.Consul.Scheme
+	// 解析端口
+	Port, err := servicePort(conf)
+	if err != nil {
+		return nil, err
+	}
 
-			// 解析端口
-			_, portStr, err := net.SplitHostPort(conf.Server.Http.Addr)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse service address: %w", err)
-			}
-			Port, err := strconv.Atoi(portStr)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse service port: %w", err)
-			}
+	// 获取 Pod 或机器的 IP 地址
+	logger.Info("Initializing Consul registry", zap.String("addr", consulAddr), zap.String("Host", appInfo.Host))
 
-			// 获取 Pod 或机器的 IP 地址
-			logger.Info("Initializing Consul registry", zap.String("addr", consulAddr), zap.String("Host", appInfo.Host))
+	// 心跳上报依赖的真实健康状态
+	healthSource := NewCheckerHealthSource(checker, conf.Discovery.Consul.DegradedChecks)
 
-			reg, err := NewConsulRegistry(consulAddr, appInfo.ID, appInfo.Name, appInfo.Version, Port, serviceScheme, appInfo.Host, logger)
-			if err != nil {
-				logger.Warn("Failed to initialize Consul registry, service discovery disabled", zap.Error(err))
-				return nil, nil
-			}
+	check, err := NewCheckSettings(conf.Discovery.Consul)
+	if err != nil {
+		return nil, err
+	}
 
-			// 使用生命周期钩子自动注册、启动心跳和注销
-			lc.Append(fx.Hook{
-				OnStart: func(ctx context.Context) error {
-					if err := reg.Register(); err != nil {
-						logger.Warn("Failed to register with Consul, service discovery disabled", zap.Error(err))
-						return nil // 允许应用继续运行
-					}
-
-					// 启动 TTL 心跳 Pinger
-					go reg.TtlCheckPinger(context.Background())
-					return nil
-				},
-				OnStop: func(ctx context.Context) error {
-					if reg != nil {
-						// Deregister() 也会停止心跳，但我们不需要显式停止 TtlCheckPinger，
-						// 因为 Deregister 是 OnStop 的一部分，当应用退出时，TtlCheckPinger 的 context 也会关闭。
-						if err := reg.Deregister(); err != nil {
-							logger.Warn("Failed to deregister from Consul", zap.Error(err))
-						}
-					}
-					return nil
-				},
-			})
-			return reg, nil
-		},
-	),
-)
+	reg, err := NewConsulRegistry(consulAddr, appInfo.ID, appInfo.Name, appInfo.Version, Port, serviceScheme, appInfo.Host, check, healthSource, logger)
+	if err != nil {
+		return nil, err
+	}
+	// 注册状态通过健康检查的 registry 服务暴露
+	if checker != nil {
+		reg.reporter = checker
+	}
+	return reg, nil
+}
 
-func NewConsulRegistry(addr, ID, Name, Version string, Port int, serviceScheme string, Host string, logger *zap.Logger, ) (*ConsulRegistry, error) {
+// NewConsulRegistry health 为 nil 时心跳总是上报 passing
+func NewConsulRegistry(addr, ID, Name, Version string, Port int, serviceScheme string, Host string, check CheckSettings, health HealthSource, logger *zap.Logger, ) (*ConsulRegistry, error) {
 	config := &api.Config{
 		Address: addr,
 		Scheme:  serviceScheme,
@@ -107,69 +112,241 @@ func NewConsulRegistry(addr, ID, Name, Version string, Port int, serviceScheme s
 		return nil, err
 	}
 
-	return &ConsulRegistry{
-		client: client,
-		logger: logger,
-		ID:     ID,
-		Name:   fmt.Sprintf("%s-%s", Name, Version),
-		Port:   Port,
-		Host:   Host,
-	}, nil
+	if health == nil {
+		health = AlwaysPassing
+	}
+
+	r := &ConsulRegistry{
+		client:     client,
+		health:     health,
+		check:      check,
+		logger:     logger,
+		minBackoff: defaultReregisterMinBackoff,
+		maxBackoff: defaultReregisterMaxBackoff,
+		ID:         ID,
+		Name:       fmt.Sprintf("%s-%s", Name, Version),
+		Port:       Port,
+		Host:       Host,
+	}
+
+	meter := otel.GetMeterProvider().Meter("github.com/sunmery/ecommerce/backend/registry")
+	attrs := metric.WithAttributes(attribute.String("service", r.Name))
+
+	r.reregistrations, err = meter.Int64Counter(
+		"registry.reregistrations",
+		metric.WithDescription("Number of times the service re-registered with Consul after losing its registration"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init counter: %w", err)
+	}
+
+	_, err = meter.Int64ObservableGauge(
+		"registry.registered",
+		metric.WithDescription("Whether the service is currently registered with Consul (1) or not (0)"),
+		metric.WithInt64Callback(func(ctx context.Context, o metric.Int64Observer) error {
+			var v int64
+			if r.registered.Load() {
+				v = 1
+			}
+			o.Observe(v, attrs)
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init gauge: %w", err)
+	}
+
+	return r, nil
+}
+
+// Register 使用配置的健康检查模式注册服务，并启动后台维护：TTL 模式下发送心跳，
+// 发现 Agent 丢失注册（Agent 重启或因 critical 被自动注销）时按指数退避重新注册
+// 首次注册失败时同样会在后台重试
+func (r *ConsulRegistry) Register(ctx context.Context) error {
+	err := r.register(ctx)
+	r.startKeepAlive()
+	return err
 }
 
-// Register 使用 TTL 健康检查注册服务
-func (r *ConsulRegistry) Register() error {
+func (r *ConsulRegistry) register(ctx context.Context) error {
 	reg := &api.AgentServiceRegistration{
 		ID:      r.ID,
 		Name:    r.Name,
 		Address: r.Host,
 		Port:    r.Port,
-		Tags:    []string{r.Name, "fx", "ttl"}, // 增加 'ttl' tag
-		Check: &api.AgentServiceCheck{
-			// 1. 使用 TTL 替换 HTTP/TCP 检查
-			TTL: TtlDuration,
-			// 2. 配置在检查失败后自动注销
-			DeregisterCriticalServiceAfter: "1m",
-		},
+		Tags:    []string{r.Name, "fx", r.check.Mode}, // 增加检查模式 tag
+		Check:   r.check.AgentServiceCheck(r.Host, r.Port),
 	}
 
-	if err := r.client.Agent().ServiceRegister(reg); err != nil {
+	if err := r.client.Agent().ServiceRegisterOpts(reg, api.ServiceRegisterOpts{}.WithContext(ctx)); err != nil {
 		r.logger.Error("Failed to register service with Consul", zap.Error(err))
+		r.setRegistered(false, err)
 		return err
 	}
 
-	r.logger.Info("Service registered with Consul using TTL check", zap.String("id", r.ID), zap.String("ttl", TtlDuration))
+	r.logger.Info("Service registered with Consul",
+		zap.String("id", r.ID),
+		zap.String("check_mode", r.check.Mode),
+		zap.Duration("interval", r.check.Interval),
+	)
+	r.setRegistered(true, nil)
 	return nil
 }
 
-// TtlCheckPinger 负责定期向 Consul Agent 发送心跳信号
-func (r *ConsulRegistry) TtlCheckPinger(ctx context.Context) {
-	ticker := time.NewTicker(TtlPingInterval)
-	defer ticker.Stop()
+// setRegistered 记录注册状态，并同步到健康检查
+func (r *ConsulRegistry) setRegistered(registered bool, cause error) {
+	r.registered.Store(registered)
+	if r.reporter == nil {
+		return
+	}
+	if registered {
+		r.reporter.SetStatus(registrationHealthService, health.StatusServing, "")
+	} else {
+		r.reporter.SetStatus(registrationHealthService, health.StatusNotServing, cause.Error())
+	}
+}
+
+// Registered 返回当前是否已注册到 Consul
+func (r *ConsulRegistry) Registered() bool {
+	return r.registered.Load()
+}
 
-	// Consul Agent 要求 CheckID 必须是 "service:<ID>" 的格式
-	checkID := fmt.Sprintf("service:%s", r.ID)
+func (r *ConsulRegistry) startKeepAlive() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	r.logger.Info("Starting TTL pinger", zap.Duration("interval", TtlPingInterval), zap.String("checkID", checkID))
+	if r.stopKeepAlive != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	r.stopKeepAlive = func() {
+		cancel()
+		<-done
+	}
+	go func() {
+		defer close(done)
+		r.KeepAlive(ctx)
+	}()
+}
+
+// KeepAlive 维护注册状态直到 ctx 结束
+// TTL 模式下定期向 Consul Agent 发送心跳，心跳状态来自 HealthSource，依赖故障时上报 warning 或 critical，
+// Consul 会将流量从该实例摘除；http/grpc 模式下定期确认 Agent 中仍有本实例
+func (r *ConsulRegistry) KeepAlive(ctx context.Context) {
+	r.logger.Info("Starting Consul keep-alive", zap.Duration("interval", r.check.Interval), zap.String("check_mode", r.check.Mode))
+
+	// 已注册时立即上报一次，不必等待第一个心跳周期
+	delay := time.Duration(0)
+	if !r.registered.Load() {
+		delay = r.minBackoff
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
 
+	backoff := r.minBackoff
+	var lastStatus string
 	for {
 		select {
 		case <-ctx.Done():
-			r.logger.Info("TTL pinger stopped gracefully")
+			r.logger.Info("Consul keep-alive stopped gracefully")
 			return
-		case <-ticker.C:
-			// 发送 'pass' 状态的心跳
-			err := r.client.Agent().UpdateTTL(checkID, "TTL check passing", api.HealthPassing)
-			if err != nil {
-				// 记录错误，但不退出 Pinger，因为这可能是暂时的网络问题
-				// 如果长时间失败，Consul Agent 会将服务标记为 Critical
-				r.logger.Error("Failed to update Consul TTL", zap.Error(err), zap.String("ID", r.ID))
+		case <-timer.C:
+		}
+
+		if !r.registered.Load() {
+			if err := r.register(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				r.logger.Warn("Re-registration with Consul failed, will retry", zap.Duration("backoff", backoff))
+				timer.Reset(backoff)
+				backoff = min(backoff*2, r.maxBackoff)
+				continue
 			}
+			backoff = r.minBackoff
+			r.reregistrations.Add(ctx, 1)
+			lastStatus = ""
+		}
+
+		var err error
+		if r.check.Mode == CheckModeTTL {
+			lastStatus, err = r.updateTTL(ctx, lastStatus)
+		} else {
+			_, _, err = r.client.Agent().Service(r.ID, (&api.QueryOptions{}).WithContext(ctx))
+		}
+		if err != nil && ctx.Err() == nil {
+			if isNotRegistered(err) {
+				// Agent 已经没有本实例，立即重新注册
+				r.logger.Warn("Service is no longer registered with Consul agent, re-registering", zap.Error(err))
+				r.setRegistered(false, err)
+				timer.Reset(0)
+				continue
+			}
+			// 记录错误，但不退出，因为这可能是暂时的网络问题
+			// 如果长时间失败，Consul Agent 会将服务标记为 Critical
+			r.logger.Error("Failed to maintain Consul registration", zap.Error(err), zap.String("ID", r.ID))
+		}
+		timer.Reset(r.check.Interval)
+	}
+}
+
+// updateTTL 上报一次心跳，返回本次上报的状态
+func (r *ConsulRegistry) updateTTL(ctx context.Context, lastStatus string) (string, error) {
+	report := r.health.Report(ctx)
+	if report.Status != lastStatus {
+		fields := []zap.Field{zap.String("status", report.Status), zap.String("output", report.Output)}
+		if report.Status == api.HealthPassing {
+			r.logger.Info("Consul TTL status changed", fields...)
+		} else {
+			r.logger.Warn("Consul TTL status changed", fields...)
 		}
 	}
+
+	// Consul Agent 要求 CheckID 必须是 "service:<ID>" 的格式
+	checkID := fmt.Sprintf("service:%s", r.ID)
+	err := r.client.Agent().UpdateTTLOpts(checkID, report.Output, report.Status, (&api.QueryOptions{}).WithContext(ctx))
+	return report.Status, err
 }
 
-func (r *ConsulRegistry) Deregister() error {
+// isNotRegistered 判断错误是否表示 Agent 中不存在本实例的服务或检查
+// 新版本 Consul 返回 404，旧版本返回 500 并在响应体中说明
+func isNotRegistered(err error) bool {
+	var statusErr api.StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	if statusErr.Code == http.StatusNotFound {
+		return true
+	}
+	body := strings.ToLower(statusErr.Body)
+	for _, msg := range []string{"unknown check", "unknown service", "does not have associated ttl", "not found"} {
+		if strings.Contains(body, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// Deregister 停止后台维护并从 Consul 注销
+func (r *ConsulRegistry) Deregister(ctx context.Context) error {
+	r.mu.Lock()
+	stop := r.stopKeepAlive
+	r.stopKeepAlive = nil
+	r.mu.Unlock()
+	if stop != nil {
+		stop()
+	}
+
 	r.logger.Info("Deregistering service from Consul", zap.String("id", r.ID))
-	return r.client.Agent().ServiceDeregister(r.ID)
+	err := r.client.Agent().ServiceDeregisterOpts(r.ID, (&api.QueryOptions{}).WithContext(ctx))
+	r.setRegistered(false, errDeregistered)
+	return err
+}
+
+// Watch 通过 Consul 阻塞查询监听服务的健康实例
+func (r *ConsulRegistry) Watch(name, version string) (Watcher, error) {
+	resolver := r.NewResolver(name, version)
+	resolver.Start()
+	return resolver, nil
 }
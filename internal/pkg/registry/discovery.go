@@ -0,0 +1,200 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"go.uber.org/zap"
+)
+
+// 阻塞查询的默认配置
+const (
+	defaultWatchWaitTime   = 5 * time.Minute
+	defaultWatchMinBackoff = time.Second
+	defaultWatchMaxBackoff = 30 * time.Second
+)
+
+var ErrNoInstances = errors.New("no healthy instances available")
+
+// Instance 一个健康的服务实例
+type Instance struct {
+	ID      string
+	Address string
+	Port    int
+	Tags    []string
+	Meta    map[string]string
+}
+
+// Addr 返回 host:port
+func (i Instance) Addr() string {
+	return net.JoinHostPort(i.Address, strconv.Itoa(i.Port))
+}
+
+// ServiceName 注册到 Consul 的服务名，与 ConsulRegistry 的命名规则一致
+func ServiceName(name, version string) string {
+	return fmt.Sprintf("%s-%s", name, version)
+}
+
+// ConsulResolver 通过 Consul 阻塞查询监听某个服务的健康实例
+// 实例上下线时 Consul 立即返回新的列表，查询失败时保留最后一次的结果并按指数退避重试
+type ConsulResolver struct {
+	client  *api.Client
+	service string
+	l       *zap.Logger
+
+	waitTime   time.Duration
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mu        sync.RWMutex
+	instances []Instance
+	ready     chan struct{}
+	readyOnce sync.Once
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewConsulResolver 创建服务 name-version 的实例解析器，需要调用 Start 开始监听
+func NewConsulResolver(client *api.Client, name, version string, logger *zap.Logger) *ConsulResolver {
+	return &ConsulResolver{
+		client:     client,
+		service:    ServiceName(name, version),
+		l:          logger.With(zap.String("service", ServiceName(name, version))),
+		waitTime:   defaultWatchWaitTime,
+		minBackoff: defaultWatchMinBackoff,
+		maxBackoff: defaultWatchMaxBackoff,
+		ready:      make(chan struct{}),
+	}
+}
+
+// NewResolver 使用注册中心的 Consul 客户端创建解析器
+func (r *ConsulRegistry) NewResolver(name, version string) *ConsulResolver {
+	return NewConsulResolver(r.client, name, version, r.logger)
+}
+
+// Start 开始在后台监听实例变化
+func (r *ConsulResolver) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.watch(ctx)
+}
+
+// Stop 停止监听
+func (r *ConsulResolver) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WaitReady 等待第一次查询成功
+func (r *ConsulResolver) WaitReady(ctx context.Context) error {
+	select {
+	case <-r.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Instances 返回当前的健康实例，调用方不应修改返回值
+func (r *ConsulResolver) Instances() []Instance {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.instances
+}
+
+func (r *ConsulResolver) watch(ctx context.Context) {
+	defer close(r.done)
+
+	var index uint64
+	backoff := r.minBackoff
+	for {
+		opts := (&api.QueryOptions{WaitIndex: index, WaitTime: r.waitTime}).WithContext(ctx)
+		entries, meta, err := r.client.Health().Service(r.service, "", true, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			r.l.Warn("Failed to query healthy instances, keeping last known instances",
+				zap.Error(err),
+				zap.Duration("backoff", backoff),
+			)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, r.maxBackoff)
+			continue
+		}
+		backoff = r.minBackoff
+
+		// 索引回退（例如 Consul 重启）时需要从头开始查询
+		// 参考 https://developer.hashicorp.com/consul/api-docs/features/blocking#implementation-details
+		if meta.LastIndex < index || meta.LastIndex == 0 {
+			index = 0
+		} else {
+			index = meta.LastIndex
+		}
+
+		r.update(toInstances(entries))
+	}
+}
+
+func (r *ConsulResolver) update(instances []Instance) {
+	r.mu.Lock()
+	changed := !sameInstances(r.instances, instances)
+	r.instances = instances
+	r.mu.Unlock()
+
+	if changed {
+		r.l.Info("Service instances changed", zap.Int("count", len(instances)))
+	}
+	r.readyOnce.Do(func() { close(r.ready) })
+}
+
+// sameInstances 比较两个实例列表的 ID 和地址
+func sameInstances(a, b []Instance) bool {
+	return slices.EqualFunc(a, b, func(x, y Instance) bool {
+		return x.ID == y.ID && x.Addr() == y.Addr()
+	})
+}
+
+func toInstances(entries []*api.ServiceEntry) []Instance {
+	instances := make([]Instance, 0, len(entries))
+	for _, entry := range entries {
+		address := entry.Service.Address
+		if address == "" && entry.Node != nil {
+			address = entry.Node.Address
+		}
+		instances = append(instances, Instance{
+			ID:      entry.Service.ID,
+			Address: address,
+			Port:    entry.Service.Port,
+			Tags:    entry.Service.Tags,
+			Meta:    entry.Service.Meta,
+		})
+	}
+	// 按 ID 排序，轮询的顺序不受 Consul 返回顺序影响
+	sort.Slice(instances, func(i, j int) bool { return instances[i].ID < instances[j].ID })
+	return instances
+}
@@ -0,0 +1,201 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+// fakeConsulCatalog 模拟 /v1/health/service/<name> 的阻塞查询
+type fakeConsulCatalog struct {
+	mu      sync.Mutex
+	index   uint64
+	entries []*api.ServiceEntry
+	changed chan struct{}
+}
+
+func newFakeConsulCatalog() *fakeConsulCatalog {
+	return &fakeConsulCatalog{index: 1, changed: make(chan struct{})}
+}
+
+func (c *fakeConsulCatalog) set(addrs ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = nil
+	for _, addr := range addrs {
+		host, portStr, _ := net.SplitHostPort(addr)
+		port, _ := strconv.Atoi(portStr)
+		c.entries = append(c.entries, &api.ServiceEntry{
+			Node:    &api.Node{Address: "127.0.0.1"},
+			Service: &api.AgentService{ID: addr, Service: "orders-v1", Address: host, Port: port},
+		})
+	}
+	c.index++
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+func (c *fakeConsulCatalog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/health/service/orders-v1" || r.URL.Query().Get("passing") == "" {
+		http.NotFound(w, r)
+		return
+	}
+	wait, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+
+	c.mu.Lock()
+	if wait >= c.index {
+		changed := c.changed
+		c.mu.Unlock()
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		case <-time.After(time.Second):
+		}
+		c.mu.Lock()
+	}
+	index, entries := c.index, c.entries
+	c.mu.Unlock()
+
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(index, 10))
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(entries)
+}
+
+// DiscoveryTestSuite 是服务发现的测试套件
+type DiscoveryTestSuite struct {
+	suite.Suite
+	catalog  *fakeConsulCatalog
+	consul   *httptest.Server
+	resolver *ConsulResolver
+}
+
+func (suite *DiscoveryTestSuite) SetupTest() {
+	suite.catalog = newFakeConsulCatalog()
+	suite.consul = httptest.NewServer(suite.catalog)
+
+	client, err := api.NewClient(&api.Config{Address: suite.consul.Listener.Addr().String()})
+	require.NoError(suite.T(), err)
+	suite.resolver = NewConsulResolver(client, "orders", "v1", zap.NewNop())
+	suite.resolver.Start()
+}
+
+func (suite *DiscoveryTestSuite) TearDownTest() {
+	require.NoError(suite.T(), suite.resolver.Stop(context.Background()))
+	suite.consul.Close()
+}
+
+// newBackend 启动一个返回自身地址的后端实例
+func (suite *DiscoveryTestSuite) newBackend() *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, srv.Listener.Addr().String())
+	}))
+	suite.T().Cleanup(srv.Close)
+	return srv
+}
+
+func (suite *DiscoveryTestSuite) waitInstances(n int) []Instance {
+	var instances []Instance
+	require.Eventually(suite.T(), func() bool {
+		instances = suite.resolver.Instances()
+		return len(instances) == n
+	}, 5*time.Second, 10*time.Millisecond)
+	return instances
+}
+
+func (suite *DiscoveryTestSuite) get(client *http.Client) (string, error) {
+	resp, err := client.Get("http://orders-v1/ping")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+func (suite *DiscoveryTestSuite) TestReactsToChurn() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(suite.T(), suite.resolver.WaitReady(ctx))
+	assert.Empty(suite.T(), suite.resolver.Instances())
+
+	suite.catalog.set("10.0.0.1:8080", "10.0.0.2:8080")
+	instances := suite.waitInstances(2)
+	assert.Equal(suite.T(), "10.0.0.1:8080", instances[0].Addr())
+
+	suite.catalog.set("10.0.0.2:8080")
+	instances = suite.waitInstances(1)
+	assert.Equal(suite.T(), "10.0.0.2:8080", instances[0].ID)
+}
+
+func (suite *DiscoveryTestSuite) TestRoundRobin() {
+	a, b := suite.newBackend(), suite.newBackend()
+	suite.catalog.set(a.Listener.Addr().String(), b.Listener.Addr().String())
+	suite.waitInstances(2)
+
+	client := NewHTTPClient(suite.resolver, NewRoundRobinBalancer())
+	seen := map[string]int{}
+	for range 4 {
+		addr, err := suite.get(client)
+		require.NoError(suite.T(), err)
+		seen[addr]++
+	}
+
+	assert.Equal(suite.T(), map[string]int{a.Listener.Addr().String(): 2, b.Listener.Addr().String(): 2}, seen)
+}
+
+func (suite *DiscoveryTestSuite) TestNoInstances() {
+	client := NewHTTPClient(suite.resolver, NewRoundRobinBalancer())
+
+	_, err := suite.get(client)
+
+	assert.ErrorIs(suite.T(), err, ErrNoInstances)
+}
+
+// 运行测试套件
+func TestDiscoveryTestSuite(t *testing.T) {
+	suite.Run(t, new(DiscoveryTestSuite))
+}
+
+func TestLeastRequestsBalancer(t *testing.T) {
+	instances := []Instance{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	b := NewLeastRequestsBalancer()
+
+	// 三个请求进行中时每个实例各分到一个
+	picked := map[string]func(){}
+	for range 3 {
+		instance, done := b.Pick(instances)
+		require.NotContains(t, picked, instance.ID)
+		picked[instance.ID] = done
+	}
+
+	// b 结束后下一个请求一定选中 b
+	picked["b"]()
+	instance, _ := b.Pick(instances)
+	assert.Equal(t, "b", instance.ID)
+}
+
+func TestNewBalancer(t *testing.T) {
+	for _, policy := range []string{"", BalancerRoundRobin, BalancerLeastRequests} {
+		_, err := NewBalancer(policy)
+		assert.NoError(t, err, policy)
+	}
+
+	_, err := NewBalancer("random")
+	assert.Error(t, err)
+}
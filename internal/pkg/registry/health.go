@@ -0,0 +1,87 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"connect-go-example/internal/pkg/health"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// defaultDegradedChecks 失败时只上报 warning 的依赖：搜索或第三方登录不可用时其余接口仍可正常服务
+var defaultDegradedChecks = []string{"elasticsearch", "casdoor"}
+
+// HealthReport 上报给 Consul TTL 检查的状态，Status 为 api.HealthPassing/HealthWarning/HealthCritical
+type HealthReport struct {
+	Status string
+	Output string
+}
+
+// HealthSource 为 TTL 心跳提供实例的健康状态
+type HealthSource interface {
+	Report(ctx context.Context) HealthReport
+}
+
+// HealthSourceFunc 将函数适配为 HealthSource
+type HealthSourceFunc func(ctx context.Context) HealthReport
+
+func (f HealthSourceFunc) Report(ctx context.Context) HealthReport {
+	return f(ctx)
+}
+
+// AlwaysPassing 总是上报 passing，用于没有健康来源的场景
+var AlwaysPassing HealthSource = HealthSourceFunc(func(ctx context.Context) HealthReport {
+	return HealthReport{Status: api.HealthPassing, Output: "TTL check passing"}
+})
+
+// checkerHealthSource 根据 health.Checker 缓存的探测结果生成报告
+type checkerHealthSource struct {
+	checker  *health.Checker
+	degraded []string
+}
+
+// NewCheckerHealthSource degraded 中的依赖失败时上报 warning，其余依赖失败时上报 critical
+// degraded 为空时使用 defaultDegradedChecks
+func NewCheckerHealthSource(checker *health.Checker, degraded []string) HealthSource {
+	if len(degraded) == 0 {
+		degraded = defaultDegradedChecks
+	}
+	return &checkerHealthSource{checker: checker, degraded: degraded}
+}
+
+func (s *checkerHealthSource) Report(ctx context.Context) HealthReport {
+	results := s.checker.ProbeResults()
+
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	status := api.HealthPassing
+	var failures []string
+	for _, name := range names {
+		result := results[name]
+		if result.Status == health.StatusServing {
+			continue
+		}
+
+		failures = append(failures, fmt.Sprintf("%s: %s", name, result.Error))
+		if slices.Contains(s.degraded, name) {
+			if status == api.HealthPassing {
+				status = api.HealthWarning
+			}
+		} else {
+			status = api.HealthCritical
+		}
+	}
+
+	if len(failures) == 0 {
+		return HealthReport{Status: api.HealthPassing, Output: "all checks passing"}
+	}
+	return HealthReport{Status: status, Output: strings.Join(failures, "; ")}
+}
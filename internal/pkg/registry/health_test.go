@@ -0,0 +1,144 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"connect-go-example/internal/pkg/health"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestChecker(failures map[string]error) *health.Checker {
+	var probes []health.Probe
+	for _, name := range []string{"postgres", "redis", "elasticsearch", "casdoor"} {
+		probes = append(probes, health.Probe{Name: name, Check: func(ctx context.Context) error {
+			return failures[name]
+		}})
+	}
+	checker := health.NewChecker(probes, nil, time.Hour, time.Second, zap.NewNop())
+	checker.CheckNow(context.Background())
+	return checker
+}
+
+func TestCheckerHealthSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures map[string]error
+		degraded []string
+		status   string
+		output   string
+	}{
+		{
+			name:   "all passing",
+			status: api.HealthPassing,
+			output: "all checks passing",
+		},
+		{
+			name:     "degraded dependency",
+			failures: map[string]error{"elasticsearch": errors.New("no such host")},
+			status:   api.HealthWarning,
+			output:   "elasticsearch: no such host",
+		},
+		{
+			name: "degraded dependencies",
+			failures: map[string]error{
+				"casdoor":       errors.New("unexpected status 502"),
+				"elasticsearch": errors.New("no such host"),
+			},
+			status: api.HealthWarning,
+			output: "casdoor: unexpected status 502; elasticsearch: no such host",
+		},
+		{
+			name: "critical dependency",
+			failures: map[string]error{
+				"elasticsearch": errors.New("no such host"),
+				"postgres":      errors.New("connection refused"),
+			},
+			status: api.HealthCritical,
+			output: "elasticsearch: no such host; postgres: connection refused",
+		},
+		{
+			name:     "custom degraded checks",
+			failures: map[string]error{"redis": errors.New("timeout")},
+			degraded: []string{"redis"},
+			status:   api.HealthWarning,
+			output:   "redis: timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewCheckerHealthSource(newTestChecker(tt.failures), tt.degraded)
+
+			report := source.Report(context.Background())
+
+			assert.Equal(t, tt.status, report.Status)
+			assert.Equal(t, tt.output, report.Output)
+		})
+	}
+}
+
+func TestCheckerHealthSource_Shutdown(t *testing.T) {
+	checker := newTestChecker(nil)
+	checker.Shutdown()
+
+	report := NewCheckerHealthSource(checker, nil).Report(context.Background())
+
+	assert.Equal(t, api.HealthCritical, report.Status)
+	assert.Contains(t, report.Output, "shutting down")
+}
+
+// TestKeepAlive_ReportsHealth 心跳上报 HealthSource 的状态和输出
+func TestKeepAlive_ReportsHealth(t *testing.T) {
+	type ttlUpdate struct {
+		Status string
+		Output string
+	}
+	updates := make(chan ttlUpdate, 1)
+
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || !strings.HasSuffix(r.URL.Path, "/v1/agent/check/update/service:instance-1") {
+			http.NotFound(w, r)
+			return
+		}
+		var update ttlUpdate
+		_ = json.NewDecoder(r.Body).Decode(&update)
+		updates <- update
+	}))
+	defer agent.Close()
+
+	source := NewCheckerHealthSource(newTestChecker(map[string]error{"postgres": errors.New("connection refused")}), nil)
+	reg, err := NewConsulRegistry(strings.TrimPrefix(agent.URL, "http://"), "instance-1", "user", "v1", 8080, "http", "127.0.0.1", CheckSettings{Mode: CheckModeTTL, Interval: time.Hour}, source, zap.NewNop())
+	require.NoError(t, err)
+
+	// 视为已注册，直接开始心跳
+	reg.registered.Store(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		reg.KeepAlive(ctx)
+		close(done)
+	}()
+
+	select {
+	case update := <-updates:
+		assert.Equal(t, api.HealthCritical, update.Status)
+		assert.Equal(t, "postgres: connection refused", update.Output)
+	case <-time.After(5 * time.Second):
+		t.Fatal("TTL was not updated")
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,278 @@
+package registry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	confv1 "connect-go-example/internal/conf/v1"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultKubernetesPollInterval = 5 * time.Second
+	kubernetesRequestTimeout      = 10 * time.Second
+)
+
+// serviceAccountDir 集群内 Pod 挂载的 service account 目录
+var serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// KubernetesRegistry 轮询 Kubernetes EndpointSlice 发现实例，服务 name-version 对应同名的 Service
+// 当前实例由 kubelet 根据就绪探针加入或移出 Endpoints，因此 Register 和 Deregister 不做任何事
+type KubernetesRegistry struct {
+	client    *http.Client
+	apiServer string
+	namespace string
+	portName  string
+	tokenPath string
+	interval  time.Duration
+	logger    *zap.Logger
+}
+
+// NewKubernetesRegistry 默认使用集群内的 API Server 和 service account
+// 在集群外可以把 api_server 指向 kubectl proxy，此时没有 token 也能访问
+func NewKubernetesRegistry(cfg *confv1.Discovery_Kubernetes, logger *zap.Logger) (*KubernetesRegistry, error) {
+	apiServer := cfg.GetApiServer()
+	if apiServer == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return nil, fmt.Errorf("kubernetes registry requires discovery.kubernetes.api_server outside the cluster")
+		}
+		apiServer = "https://" + net.JoinHostPort(host, port)
+	}
+
+	namespace := cfg.GetNamespace()
+	if namespace == "" {
+		content, err := os.ReadFile(filepath.Join(serviceAccountDir, "namespace"))
+		if err != nil {
+			return nil, fmt.Errorf("kubernetes registry requires discovery.kubernetes.namespace: %w", err)
+		}
+		namespace = strings.TrimSpace(string(content))
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if ca, err := os.ReadFile(filepath.Join(serviceAccountDir, "ca.crt")); err == nil {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("parse kubernetes ca certificate failed")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	r := &KubernetesRegistry{
+		client:    &http.Client{Transport: transport, Timeout: kubernetesRequestTimeout},
+		apiServer: strings.TrimSuffix(apiServer, "/"),
+		namespace: namespace,
+		portName:  cfg.GetPortName(),
+		tokenPath: filepath.Join(serviceAccountDir, "token"),
+		interval:  defaultKubernetesPollInterval,
+		logger:    logger.With(zap.String("namespace", namespace)),
+	}
+	if v := cfg.GetPollInterval(); v > 0 {
+		r.interval = time.Duration(v) * time.Second
+	}
+	r.logger.Info("Using kubernetes service registry", zap.String("api_server", r.apiServer))
+	return r, nil
+}
+
+func (r *KubernetesRegistry) Register(ctx context.Context) error {
+	return nil
+}
+
+func (r *KubernetesRegistry) Deregister(ctx context.Context) error {
+	return nil
+}
+
+// endpointSliceList discovery.k8s.io/v1 EndpointSliceList 中用到的字段
+type endpointSliceList struct {
+	Items []struct {
+		AddressType string `json:"addressType"`
+		Endpoints   []struct {
+			Addresses  []string `json:"addresses"`
+			Conditions struct {
+				Ready *bool `json:"ready"`
+			} `json:"conditions"`
+			TargetRef *struct {
+				Name string `json:"name"`
+			} `json:"targetRef"`
+		} `json:"endpoints"`
+		Ports []struct {
+			Name string `json:"name"`
+			Port *int   `json:"port"`
+		} `json:"ports"`
+	} `json:"items"`
+}
+
+// list 查询 Service 的所有 EndpointSlice，返回就绪的实例
+func (r *KubernetesRegistry) list(ctx context.Context, service string) ([]Instance, error) {
+	endpoint := fmt.Sprintf("%s/apis/discovery.k8s.io/v1/namespaces/%s/endpointslices?labelSelector=%s",
+		r.apiServer, url.PathEscape(r.namespace), url.QueryEscape("kubernetes.io/service-name="+service))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	// token 会被 kubelet 定期轮换，每次请求重新读取
+	if token, err := os.ReadFile(r.tokenPath); err == nil {
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read service account token failed: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("list endpointslices returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var result endpointSliceList
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode endpointslices failed: %w", err)
+	}
+
+	// 滚动更新时同一个端点可能同时出现在多个 EndpointSlice 中，按地址去重
+	seen := make(map[string]struct{})
+	var instances []Instance
+	for _, slice := range result.Items {
+		if slice.AddressType != "IPv4" && slice.AddressType != "IPv6" {
+			continue
+		}
+		port := 0
+		for _, p := range slice.Ports {
+			if p.Port != nil && (r.portName == "" || p.Name == r.portName) {
+				port = *p.Port
+				break
+			}
+		}
+		if port == 0 {
+			continue
+		}
+		for _, ep := range slice.Endpoints {
+			// ready 为空时按 Kubernetes 的约定视为就绪
+			if len(ep.Addresses) == 0 || (ep.Conditions.Ready != nil && !*ep.Conditions.Ready) {
+				continue
+			}
+			instance := Instance{ID: ep.Addresses[0], Address: ep.Addresses[0], Port: port}
+			if ep.TargetRef != nil && ep.TargetRef.Name != "" {
+				instance.ID = ep.TargetRef.Name
+			}
+			if _, ok := seen[instance.Addr()]; ok {
+				continue
+			}
+			seen[instance.Addr()] = struct{}{}
+			instances = append(instances, instance)
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].ID < instances[j].ID })
+	return instances, nil
+}
+
+// Watch 返回的 Watcher 周期性查询 EndpointSlice；查询失败时保留上一次的结果
+func (r *KubernetesRegistry) Watch(name, version string) (Watcher, error) {
+	service := ServiceName(name, version)
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &kubernetesWatcher{
+		registry: r,
+		service:  service,
+		logger:   r.logger.With(zap.String("service", service)),
+		ready:    make(chan struct{}),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go w.run(ctx)
+	return w, nil
+}
+
+type kubernetesWatcher struct {
+	registry *KubernetesRegistry
+	service  string
+	logger   *zap.Logger
+
+	mu        sync.RWMutex
+	instances []Instance
+	ready     chan struct{}
+	readyOnce sync.Once
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (w *kubernetesWatcher) run(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.registry.interval)
+	defer ticker.Stop()
+
+	for {
+		w.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *kubernetesWatcher) refresh(ctx context.Context) {
+	instances, err := w.registry.list(ctx, w.service)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.Warn("Failed to list endpointslices, keeping last known instances", zap.Error(err))
+		}
+		return
+	}
+
+	w.mu.Lock()
+	changed := !sameInstances(w.instances, instances)
+	w.instances = instances
+	w.mu.Unlock()
+	if changed {
+		w.logger.Info("Service instances changed", zap.Int("count", len(instances)))
+	}
+	w.readyOnce.Do(func() { close(w.ready) })
+}
+
+func (w *kubernetesWatcher) Instances() []Instance {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.instances
+}
+
+// WaitReady 等待第一次查询成功
+func (w *kubernetesWatcher) WaitReady(ctx context.Context) error {
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *kubernetesWatcher) Stop(ctx context.Context) error {
+	w.cancel()
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,171 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	confv1 "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/health"
+	"connect-go-example/internal/pkg/meta"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// 注册中心后端
+const (
+	BackendConsul     = "consul"
+	BackendNoop       = "noop"
+	BackendStatic     = "static"
+	BackendFile       = "file"
+	BackendKubernetes = "kubernetes"
+)
+
+// Registry 服务注册与发现
+type Registry interface {
+	// Register 将当前实例注册到注册中心
+	Register(ctx context.Context) error
+	// Deregister 从注册中心注销当前实例
+	Deregister(ctx context.Context) error
+	// Watch 持续监听服务 name-version 的健康实例
+	Watch(name, version string) (Watcher, error)
+}
+
+// Watcher 提供某个服务当前的健康实例
+type Watcher interface {
+	// Instances 返回当前的健康实例，调用方不应修改返回值
+	Instances() []Instance
+	// WaitReady 等待第一次获取到实例列表
+	WaitReady(ctx context.Context) error
+	// Stop 停止监听
+	Stop(ctx context.Context) error
+}
+
+// Module 提供 Fx 模块
+var Module = fx.Module("registry",
+	fx.Provide(
+		NewRegistry,
+	),
+)
+
+// NewRegistry 按 discovery.backend 创建注册中心，并随应用启动注册
+// 注销由 server 的关闭流程负责，保证先摘除流量再停止 HTTP 服务
+// 未配置 backend 时，配置了 Consul 地址则使用 Consul，否则使用 noop
+func NewRegistry(lc fx.Lifecycle, logger *zap.Logger, conf *confv1.Bootstrap, appInfo meta.AppInfo, checker *health.Checker) (Registry, error) {
+	reg, err := newRegistry(conf, appInfo, checker, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// 使用生命周期钩子自动注册
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := reg.Register(ctx); err != nil {
+				logger.Warn("Failed to register service, service discovery disabled", zap.Error(err))
+				return nil // 允许应用继续运行
+			}
+			return nil
+		},
+	})
+	return reg, nil
+}
+
+func newRegistry(conf *confv1.Bootstrap, appInfo meta.AppInfo, checker *health.Checker, logger *zap.Logger) (Registry, error) {
+	discovery := conf.GetDiscovery()
+
+	backend := discovery.GetBackend()
+	if backend == "" {
+		backend = BackendNoop
+		if discovery.GetConsul().GetAddr() != "" {
+			backend = BackendConsul
+		}
+	}
+	if backend == BackendConsul && os.Getenv("DISABLE_CONSUL") == "true" {
+		logger.Info("Consul disabled by environment variable DISABLE_CONSUL=true")
+		backend = BackendNoop
+	}
+
+	switch backend {
+	case BackendConsul:
+		if discovery.GetConsul().GetAddr() == "" {
+			return nil, fmt.Errorf("consul registry requires discovery.consul.addr")
+		}
+		reg, err := newConsulFromConfig(conf, appInfo, checker, logger)
+		if err != nil {
+			// 与之前一致，Consul 不可用时降级为不注册
+			logger.Warn("Failed to initialize Consul registry, service discovery disabled", zap.Error(err))
+			return NewNoopRegistry(logger), nil
+		}
+		return reg, nil
+	case BackendNoop:
+		logger.Info("Service registry disabled")
+		return NewNoopRegistry(logger), nil
+	case BackendStatic:
+		return NewStaticRegistry(discovery.GetStatic().GetServices(), logger), nil
+	case BackendFile:
+		return NewFileRegistry(discovery.GetFile().GetPath(), discovery.GetFile().GetPollInterval(), logger)
+	case BackendKubernetes:
+		return NewKubernetesRegistry(discovery.GetKubernetes(), logger)
+	default:
+		return nil, fmt.Errorf("unsupported registry backend %q", backend)
+	}
+}
+
+// servicePort 从 server.http.addr 解析本实例的端口
+func servicePort(conf *confv1.Bootstrap) (int, error) {
+	_, portStr, err := net.SplitHostPort(conf.GetServer().GetHttp().GetAddr())
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse service address: %w", err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse service port: %w", err)
+	}
+	return port, nil
+}
+
+// NoopRegistry 不注册也不发现任何实例，用于没有注册中心的本地开发
+type NoopRegistry struct {
+	logger *zap.Logger
+}
+
+func NewNoopRegistry(logger *zap.Logger) *NoopRegistry {
+	return &NoopRegistry{logger: logger}
+}
+
+func (r *NoopRegistry) Register(ctx context.Context) error {
+	return nil
+}
+
+func (r *NoopRegistry) Deregister(ctx context.Context) error {
+	return nil
+}
+
+// Watch 返回没有实例的 Watcher
+func (r *NoopRegistry) Watch(name, version string) (Watcher, error) {
+	return newStaticWatcher(nil), nil
+}
+
+// staticWatcher 实例列表固定的 Watcher
+type staticWatcher struct {
+	instances []Instance
+}
+
+func newStaticWatcher(instances []Instance) *staticWatcher {
+	return &staticWatcher{instances: instances}
+}
+
+func (w *staticWatcher) Instances() []Instance {
+	return w.instances
+}
+
+func (w *staticWatcher) WaitReady(ctx context.Context) error {
+	return nil
+}
+
+func (w *staticWatcher) Stop(ctx context.Context) error {
+	return nil
+}
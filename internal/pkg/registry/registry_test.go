@@ -1,94 +1,289 @@
 package registry
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	confv1 "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/health"
+	"connect-go-example/internal/pkg/meta"
+
+	"github.com/hashicorp/consul/api"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
 )
 
-// MockConsulClient 是 Consul 客户端的模拟实现
-type MockConsulClient struct {
-	mock.Mock
+// fakeConsulAgent 模拟 Consul Agent 的注册、注销、查询和 TTL 更新接口
+type fakeConsulAgent struct {
+	mu         sync.Mutex
+	calls      []string
+	status     int  // 非 0 时所有请求返回该状态码
+	registered bool // 模拟 Agent 是否持有服务，丢失后检查更新和服务查询返回 404
+	server     *httptest.Server
 }
 
-func (m *MockConsulClient) Register(service interface{}) error {
-	args := m.Called(service)
-	return args.Error(0)
+func newFakeConsulAgent() *fakeConsulAgent {
+	a := &fakeConsulAgent{}
+	a.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.calls = append(a.calls, r.Method+" "+r.URL.Path)
+
+		if a.status != 0 {
+			http.Error(w, "agent unavailable", a.status)
+			return
+		}
+		switch {
+		case r.URL.Path == "/v1/agent/service/register":
+			a.registered = true
+		case strings.HasPrefix(r.URL.Path, "/v1/agent/service/deregister/"):
+			a.registered = false
+		case strings.HasPrefix(r.URL.Path, "/v1/agent/check/update/"):
+			if !a.registered {
+				http.Error(w, `Unknown check ID "service:instance-1"`, http.StatusNotFound)
+			}
+		case strings.HasPrefix(r.URL.Path, "/v1/agent/service/"):
+			if !a.registered {
+				http.Error(w, "unknown service ID: instance-1", http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"ID": "instance-1", "Service": "user-v1"}`))
+		}
+	}))
+	return a
 }
 
-func (m *MockConsulClient) Deregister(serviceID string) error {
-	args := m.Called(serviceID)
-	return args.Error(0)
+// lose 模拟 Agent 重启后丢失注册
+func (a *fakeConsulAgent) lose() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.registered = false
 }
 
-func (m *MockConsulClient) HealthCheck(serviceID string) error {
-	args := m.Called(serviceID)
-	return args.Error(0)
+func (a *fakeConsulAgent) addr() string {
+	return strings.TrimPrefix(a.server.URL, "http://")
+}
+
+func (a *fakeConsulAgent) setStatus(status int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.status = status
+}
+
+func (a *fakeConsulAgent) count(call string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	n := 0
+	for _, c := range a.calls {
+		if c == call {
+			n++
+		}
+	}
+	return n
 }
 
 // RegistryTestSuite 是 Registry 的测试套件
 type RegistryTestSuite struct {
 	suite.Suite
-	mockClient *MockConsulClient
-	registry   *ConsulRegistry
+	agent    *fakeConsulAgent
+	registry *ConsulRegistry
 }
 
 func (suite *RegistryTestSuite) SetupTest() {
-	suite.mockClient = new(MockConsulClient)
-	// 由于 ConsulRegistry 使用 *api.Client 而不是 MockConsulClient，跳过相关测试
-	suite.registry = nil
+	suite.agent = newFakeConsulAgent()
+
+	reg, err := NewConsulRegistry(suite.agent.addr(), "instance-1", "user", "v1", 8080, "http", "127.0.0.1",
+		CheckSettings{Mode: CheckModeTTL, Interval: 10 * time.Millisecond, DeregisterAfter: time.Minute}, nil, zap.NewNop())
+	require.NoError(suite.T(), err)
+	reg.minBackoff = 10 * time.Millisecond
+	reg.maxBackoff = 40 * time.Millisecond
+	suite.registry = reg
+}
+
+func (suite *RegistryTestSuite) TearDownTest() {
+	_ = suite.registry.Deregister(context.Background())
+	suite.agent.server.Close()
+}
+
+func (suite *RegistryTestSuite) newBootstrap(discovery *confv1.Discovery) *confv1.Bootstrap {
+	return &confv1.Bootstrap{
+		Server:    &confv1.Server{Http: &confv1.Server_HTTP{Addr: ":8080"}},
+		Discovery: discovery,
+	}
 }
 
 func (suite *RegistryTestSuite) TestNewRegistry_WithValidConfig() {
-	// NewRegistry 函数不存在，跳过此测试
-	suite.T().Skip("NewRegistry 函数不存在，跳过此测试")
+	reg, err := newRegistry(suite.newBootstrap(&confv1.Discovery{
+		Consul: &confv1.Discovery_Consul{Addr: suite.agent.addr(), Scheme: "http"},
+	}), meta.AppInfo{ID: "instance-1", Name: "user", Version: "v1"}, nil, zap.NewNop())
+
+	require.NoError(suite.T(), err)
+	assert.IsType(suite.T(), &ConsulRegistry{}, reg)
 }
 
 func (suite *RegistryTestSuite) TestNewRegistry_WithNilConfig() {
-	// NewRegistry 函数不存在，跳过此测试
-	suite.T().Skip("NewRegistry 函数不存在，跳过此测试")
+	reg, err := newRegistry(suite.newBootstrap(nil), meta.AppInfo{}, nil, zap.NewNop())
+
+	require.NoError(suite.T(), err)
+	assert.IsType(suite.T(), &NoopRegistry{}, reg)
 }
 
 func (suite *RegistryTestSuite) TestNewRegistry_WithoutRegistryConfig() {
-	// NewRegistry 函数不存在，跳过此测试
-	suite.T().Skip("NewRegistry 函数不存在，跳过此测试")
+	reg, err := newRegistry(suite.newBootstrap(&confv1.Discovery{}), meta.AppInfo{}, nil, zap.NewNop())
+	require.NoError(suite.T(), err)
+	assert.IsType(suite.T(), &NoopRegistry{}, reg)
+
+	_, err = newRegistry(suite.newBootstrap(&confv1.Discovery{Backend: "etcd"}), meta.AppInfo{}, nil, zap.NewNop())
+	assert.Error(suite.T(), err)
+
+	_, err = newRegistry(suite.newBootstrap(&confv1.Discovery{Backend: BackendConsul}), meta.AppInfo{}, nil, zap.NewNop())
+	assert.Error(suite.T(), err)
 }
 
 func (suite *RegistryTestSuite) TestRegister_Success() {
-	// Registry 类型和 Register 方法不存在，跳过此测试
-	suite.T().Skip("Registry 类型和 Register 方法不存在，跳过此测试")
+	err := suite.registry.Register(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, suite.agent.count("PUT /v1/agent/service/register"))
 }
 
 func (suite *RegistryTestSuite) TestRegister_Failure() {
-	// Registry 类型和 Register 方法不存在，跳过此测试
-	suite.T().Skip("Registry 类型和 Register 方法不存在，跳过此测试")
+	suite.agent.setStatus(http.StatusInternalServerError)
+
+	err := suite.registry.Register(context.Background())
+
+	assert.Error(suite.T(), err)
 }
 
 func (suite *RegistryTestSuite) TestDeregister_Success() {
-	// Registry 类型和 Deregister 方法不存在，跳过此测试
-	suite.T().Skip("Registry 类型和 Deregister 方法不存在，跳过此测试")
+	err := suite.registry.Deregister(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, suite.agent.count("PUT /v1/agent/service/deregister/instance-1"))
 }
 
 func (suite *RegistryTestSuite) TestDeregister_Failure() {
-	// Registry 类型和 Deregister 方法不存在，跳过此测试
-	suite.T().Skip("Registry 类型和 Deregister 方法不存在，跳过此测试")
+	suite.agent.setStatus(http.StatusInternalServerError)
+
+	err := suite.registry.Deregister(context.Background())
+
+	assert.Error(suite.T(), err)
 }
 
 func (suite *RegistryTestSuite) TestStartHealthCheck_Success() {
-	// Registry 类型和 StartHealthCheck 方法不存在，跳过此测试
-	suite.T().Skip("Registry 类型和 StartHealthCheck 方法不存在，跳过此测试")
+	require.NoError(suite.T(), suite.registry.Register(context.Background()))
+
+	// TTL 模式注册后自动开始心跳
+	assert.Eventually(suite.T(), func() bool {
+		return suite.agent.count("PUT /v1/agent/check/update/service:instance-1") >= 2
+	}, 5*time.Second, 10*time.Millisecond)
 }
 
 func (suite *RegistryTestSuite) TestStartHealthCheck_Failure() {
-	// Registry 类型和 StartHealthCheck 方法不存在，跳过此测试
-	suite.T().Skip("Registry 类型和 StartHealthCheck 方法不存在，跳过此测试")
+	require.NoError(suite.T(), suite.registry.Register(context.Background()))
+	suite.agent.setStatus(http.StatusInternalServerError)
+
+	// 心跳失败时 Pinger 继续重试
+	n := suite.agent.count("PUT /v1/agent/check/update/service:instance-1")
+	assert.Eventually(suite.T(), func() bool {
+		return suite.agent.count("PUT /v1/agent/check/update/service:instance-1") > n+1
+	}, 5*time.Second, 10*time.Millisecond)
 }
 
 func (suite *RegistryTestSuite) TestStartHealthCheck_WithCanceledContext() {
-	// Registry 类型和 StartHealthCheck 方法不存在，跳过此测试
-	suite.T().Skip("Registry 类型和 StartHealthCheck 方法不存在，跳过此测试")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		suite.registry.KeepAlive(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		suite.T().Fatal("KeepAlive did not stop")
+	}
+}
+
+func (suite *RegistryTestSuite) TestDeregister_StopsHealthCheck() {
+	require.NoError(suite.T(), suite.registry.Register(context.Background()))
+	require.NoError(suite.T(), suite.registry.Deregister(context.Background()))
+
+	n := suite.agent.count("PUT /v1/agent/check/update/service:instance-1")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(suite.T(), n, suite.agent.count("PUT /v1/agent/check/update/service:instance-1"))
+}
+
+func (suite *RegistryTestSuite) TestReregister_WhenCheckUnknown() {
+	require.NoError(suite.T(), suite.registry.Register(context.Background()))
+	require.True(suite.T(), suite.registry.Registered())
+
+	// Agent 丢失注册后心跳返回 404，自动重新注册
+	suite.agent.lose()
+	assert.Eventually(suite.T(), func() bool {
+		return suite.agent.count("PUT /v1/agent/service/register") >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Eventually(suite.T(), suite.registry.Registered, 5*time.Second, 10*time.Millisecond)
+}
+
+func (suite *RegistryTestSuite) TestReregister_WhenServiceUnknown() {
+	suite.registry.check.Mode = CheckModeHTTP
+	require.NoError(suite.T(), suite.registry.Register(context.Background()))
+
+	// http 模式下通过查询服务发现注册丢失
+	suite.agent.lose()
+	assert.Eventually(suite.T(), func() bool {
+		return suite.agent.count("PUT /v1/agent/service/register") >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Zero(suite.T(), suite.agent.count("PUT /v1/agent/check/update/service:instance-1"))
+}
+
+func (suite *RegistryTestSuite) TestReregister_RetriesInitialFailure() {
+	checker := health.NewChecker(nil, nil, time.Hour, time.Second, zap.NewNop())
+	suite.registry.reporter = checker
+	status := func() health.Status {
+		result, _ := checker.Result(registrationHealthService)
+		return result.Status
+	}
+	suite.agent.setStatus(http.StatusServiceUnavailable)
+
+	require.Error(suite.T(), suite.registry.Register(context.Background()))
+	assert.False(suite.T(), suite.registry.Registered())
+	assert.Equal(suite.T(), health.StatusNotServing, status())
+
+	// 退避重试直到 Agent 恢复
+	assert.Eventually(suite.T(), func() bool {
+		return suite.agent.count("PUT /v1/agent/service/register") >= 3
+	}, 5*time.Second, 10*time.Millisecond)
+	suite.agent.setStatus(0)
+	assert.Eventually(suite.T(), suite.registry.Registered, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(suite.T(), health.StatusServing, status())
+}
+
+func (suite *RegistryTestSuite) TestReregister_IgnoresTransientErrors() {
+	require.NoError(suite.T(), suite.registry.Register(context.Background()))
+
+	// 500 不表示注册丢失，不应重新注册
+	suite.agent.setStatus(http.StatusInternalServerError)
+	time.Sleep(50 * time.Millisecond)
+	suite.agent.setStatus(0)
+
+	assert.True(suite.T(), suite.registry.Registered())
+	assert.Equal(suite.T(), 1, suite.agent.count("PUT /v1/agent/service/register"))
 }
 
 func (suite *RegistryTestSuite) TestModuleCreation() {
@@ -102,18 +297,23 @@ func (suite *RegistryTestSuite) TestModuleCreation() {
 }
 
 func (suite *RegistryTestSuite) TestNoopRegistry_Register() {
-	// noopRegistry 类型不存在，跳过此测试
-	suite.T().Skip("noopRegistry 类型不存在，跳过此测试")
+	reg := NewNoopRegistry(zap.NewNop())
+
+	assert.NoError(suite.T(), reg.Register(context.Background()))
 }
 
 func (suite *RegistryTestSuite) TestNoopRegistry_Deregister() {
-	// noopRegistry 类型不存在，跳过此测试
-	suite.T().Skip("noopRegistry 类型不存在，跳过此测试")
+	reg := NewNoopRegistry(zap.NewNop())
+
+	assert.NoError(suite.T(), reg.Deregister(context.Background()))
 }
 
 func (suite *RegistryTestSuite) TestNoopRegistry_HealthCheck() {
-	// noopRegistry 类型不存在，跳过此测试
-	suite.T().Skip("noopRegistry 类型不存在，跳过此测试")
+	w, err := NewNoopRegistry(zap.NewNop()).Watch("order", "v1")
+
+	require.NoError(suite.T(), err)
+	assert.NoError(suite.T(), w.WaitReady(context.Background()))
+	assert.Empty(suite.T(), w.Instances())
 }
 
 // 运行测试套件
@@ -123,11 +323,152 @@ func TestRegistryTestSuite(t *testing.T) {
 
 // 单元测试函数
 func TestNewNoopRegistry(t *testing.T) {
-	// newNoopRegistry 函数不存在，跳过此测试
-	t.Skip("newNoopRegistry 函数不存在，跳过此测试")
+	reg, err := newRegistry(&confv1.Bootstrap{
+		Discovery: &confv1.Discovery{Backend: BackendNoop, Consul: &confv1.Discovery_Consul{Addr: "localhost:8500"}},
+	}, meta.AppInfo{}, nil, zap.NewNop())
+
+	require.NoError(t, err)
+	assert.IsType(t, &NoopRegistry{}, reg)
+}
+
+func TestIsNotRegistered(t *testing.T) {
+	assert.True(t, isNotRegistered(api.StatusError{Code: http.StatusNotFound}))
+	assert.True(t, isNotRegistered(api.StatusError{Code: http.StatusInternalServerError, Body: `CheckID "service:instance-1" does not have associated TTL`}))
+	assert.True(t, isNotRegistered(fmt.Errorf("update: %w", api.StatusError{Code: http.StatusInternalServerError, Body: "Unknown check ID"})))
+	assert.False(t, isNotRegistered(api.StatusError{Code: http.StatusInternalServerError, Body: "agent unavailable"}))
+	assert.False(t, isNotRegistered(errors.New("connection refused")))
 }
 
 func TestRegistryInterface(t *testing.T) {
-	// RegistryInterface 接口不存在，跳过此测试
-	t.Skip("RegistryInterface 接口不存在，跳过此测试")
+	var _ Registry = (*ConsulRegistry)(nil)
+	var _ Registry = (*NoopRegistry)(nil)
+	var _ Registry = (*StaticRegistry)(nil)
+	var _ Registry = (*FileRegistry)(nil)
+	var _ Registry = (*KubernetesRegistry)(nil)
+	var _ Watcher = (*ConsulResolver)(nil)
+}
+
+func TestStaticRegistry(t *testing.T) {
+	reg, err := newRegistry(&confv1.Bootstrap{Discovery: &confv1.Discovery{
+		Backend: BackendStatic,
+		Static: &confv1.Discovery_Static{Services: []*confv1.Discovery_Service{
+			{Name: "order", Version: "v1", Addrs: []string{"10.0.0.1:8080", "invalid", "10.0.0.2:8080"}},
+		}},
+	}}, meta.AppInfo{}, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	w, err := reg.Watch("order", "v1")
+	require.NoError(t, err)
+	instances := w.Instances()
+	require.Len(t, instances, 2)
+	assert.Equal(t, "10.0.0.1:8080", instances[0].Addr())
+	assert.Equal(t, "10.0.0.2:8080", instances[1].Addr())
+
+	w, err = reg.Watch("order", "v2")
+	require.NoError(t, err)
+	assert.Empty(t, w.Instances())
+}
+
+func TestFileRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "services.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+services:
+  - name: order
+    version: v1
+    addrs: ["127.0.0.1:8081"]
+`), 0o644))
+
+	reg, err := NewFileRegistry(path, 0, zap.NewNop())
+	require.NoError(t, err)
+	reg.interval = 10 * time.Millisecond
+
+	w, err := reg.Watch("order", "v1")
+	require.NoError(t, err)
+	defer w.Stop(context.Background())
+	require.Len(t, w.Instances(), 1)
+
+	// JSON 同样可以解析；修改时间变化后重新加载
+	require.NoError(t, os.WriteFile(path, []byte(`{"services": [{"name": "order", "version": "v1", "addrs": ["127.0.0.1:8081", "127.0.0.1:8082"]}]}`), 0o644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	assert.Eventually(t, func() bool { return len(w.Instances()) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	// 文件损坏时保留上一次的结果
+	require.NoError(t, os.WriteFile(path, []byte("services: ["), 0o644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(2*time.Minute)))
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, w.Instances(), 2)
+
+	_, err = NewFileRegistry(filepath.Join(t.TempDir(), "missing.yaml"), 0, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestKubernetesRegistry(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte("sa-token\n"), 0o600))
+	serviceAccountDir = dir
+	t.Cleanup(func() { serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount" })
+
+	var mu sync.Mutex
+	status, body := http.StatusOK, `{"items": [
+		{"addressType": "IPv4", "ports": [{"name": "metrics", "port": 9090}, {"name": "http", "port": 8080}], "endpoints": [
+			{"addresses": ["10.0.0.2"], "conditions": {"ready": true}, "targetRef": {"name": "order-b"}},
+			{"addresses": ["10.0.0.1"], "targetRef": {"name": "order-a"}},
+			{"addresses": ["10.0.0.3"], "conditions": {"ready": false}, "targetRef": {"name": "order-c"}}
+		]},
+		{"addressType": "IPv4", "ports": [{"name": "http", "port": 8080}], "endpoints": [
+			{"addresses": ["10.0.0.1"], "conditions": {"ready": true}, "targetRef": {"name": "order-a"}}
+		]},
+		{"addressType": "FQDN", "ports": [{"name": "http", "port": 8080}], "endpoints": [{"addresses": ["order.example.com"]}]}
+	]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/apis/discovery.k8s.io/v1/namespaces/prod/endpointslices", r.URL.Path)
+		assert.Equal(t, "kubernetes.io/service-name=order-v1", r.URL.Query().Get("labelSelector"))
+		assert.Equal(t, "Bearer sa-token", r.Header.Get("Authorization"))
+		mu.Lock()
+		defer mu.Unlock()
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	reg, err := newRegistry(&confv1.Bootstrap{Discovery: &confv1.Discovery{
+		Backend:    BackendKubernetes,
+		Kubernetes: &confv1.Discovery_Kubernetes{ApiServer: server.URL, Namespace: "prod", PortName: "http"},
+	}}, meta.AppInfo{}, nil, zap.NewNop())
+	require.NoError(t, err)
+	reg.(*KubernetesRegistry).interval = 10 * time.Millisecond
+
+	w, err := reg.Watch("order", "v1")
+	require.NoError(t, err)
+	defer w.Stop(context.Background())
+
+	// 只保留就绪的 IP 端点，重复的端点去重，按 ID 排序
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, w.WaitReady(ctx))
+	instances := w.Instances()
+	require.Len(t, instances, 2)
+	assert.Equal(t, "order-a", instances[0].ID)
+	assert.Equal(t, "10.0.0.1:8080", instances[0].Addr())
+	assert.Equal(t, "10.0.0.2:8080", instances[1].Addr())
+
+	// 查询失败时保留上一次的结果
+	mu.Lock()
+	status, body = http.StatusForbidden, `{"kind": "Status", "reason": "Forbidden"}`
+	mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, w.Instances(), 2)
+
+	mu.Lock()
+	status, body = http.StatusOK, `{"items": [{"addressType": "IPv4", "ports": [{"name": "http", "port": 8080}], "endpoints": [{"addresses": ["10.0.0.4"]}]}]}`
+	mu.Unlock()
+	assert.Eventually(t, func() bool {
+		instances := w.Instances()
+		return len(instances) == 1 && instances[0].Addr() == "10.0.0.4:8080"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// 集群外必须配置 api_server
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	_, err = NewKubernetesRegistry(&confv1.Discovery_Kubernetes{Namespace: "prod"}, zap.NewNop())
+	assert.Error(t, err)
 }
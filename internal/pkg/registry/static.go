@@ -0,0 +1,238 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	confv1 "connect-go-example/internal/conf/v1"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultFilePollInterval = 5 * time.Second
+
+// StaticRegistry 使用配置中固定的实例列表，不注册当前实例
+type StaticRegistry struct {
+	services map[string][]Instance
+	logger   *zap.Logger
+}
+
+func NewStaticRegistry(services []*confv1.Discovery_Service, logger *zap.Logger) *StaticRegistry {
+	logger.Info("Using static service registry", zap.Int("services", len(services)))
+	return &StaticRegistry{
+		services: toServiceInstances(services, logger),
+		logger:   logger,
+	}
+}
+
+func (r *StaticRegistry) Register(ctx context.Context) error {
+	return nil
+}
+
+func (r *StaticRegistry) Deregister(ctx context.Context) error {
+	return nil
+}
+
+func (r *StaticRegistry) Watch(name, version string) (Watcher, error) {
+	return newStaticWatcher(r.services[ServiceName(name, version)]), nil
+}
+
+// toServiceInstances 将配置转换为以 name-version 为键的实例列表，忽略无法解析的地址
+func toServiceInstances(services []*confv1.Discovery_Service, logger *zap.Logger) map[string][]Instance {
+	result := make(map[string][]Instance, len(services))
+	for _, service := range services {
+		key := ServiceName(service.GetName(), service.GetVersion())
+		for _, addr := range service.GetAddrs() {
+			host, portStr, err := net.SplitHostPort(addr)
+			if err != nil {
+				logger.Warn("Ignoring invalid instance address", zap.String("service", key), zap.String("addr", addr), zap.Error(err))
+				continue
+			}
+			port, err := strconv.Atoi(portStr)
+			if err != nil {
+				logger.Warn("Ignoring invalid instance port", zap.String("service", key), zap.String("addr", addr), zap.Error(err))
+				continue
+			}
+			result[key] = append(result[key], Instance{ID: addr, Address: host, Port: port})
+		}
+	}
+	return result
+}
+
+// fileCatalog 实例文件的格式，YAML 是 JSON 的超集，两种格式都可以解析
+//
+//	services:
+//	  - name: order
+//	    version: v1
+//	    addrs: ["127.0.0.1:8081"]
+type fileCatalog struct {
+	Services []struct {
+		Name    string   `yaml:"name"`
+		Version string   `yaml:"version"`
+		Addrs   []string `yaml:"addrs"`
+	} `yaml:"services"`
+}
+
+// FileRegistry 从本地文件读取实例列表，轮询文件的修改时间并在变化时重新加载
+// 适合本地开发：修改文件即可切换依赖服务的地址，无需重启
+type FileRegistry struct {
+	path     string
+	interval time.Duration
+	logger   *zap.Logger
+
+	mu       sync.RWMutex
+	services map[string][]Instance
+	modTime  time.Time
+}
+
+// NewFileRegistry pollInterval 单位为秒，0 使用默认值
+func NewFileRegistry(path string, pollInterval int64, logger *zap.Logger) (*FileRegistry, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file registry requires discovery.file.path")
+	}
+
+	r := &FileRegistry{
+		path:     path,
+		interval: defaultFilePollInterval,
+		logger:   logger.With(zap.String("path", path)),
+	}
+	if pollInterval > 0 {
+		r.interval = time.Duration(pollInterval) * time.Second
+	}
+
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	r.logger.Info("Using file service registry")
+	return r, nil
+}
+
+func (r *FileRegistry) Register(ctx context.Context) error {
+	return nil
+}
+
+func (r *FileRegistry) Deregister(ctx context.Context) error {
+	return nil
+}
+
+// reload 文件修改时间变化时重新读取
+func (r *FileRegistry) reload() error {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return fmt.Errorf("stat registry file failed: %w", err)
+	}
+
+	r.mu.RLock()
+	unchanged := info.ModTime().Equal(r.modTime)
+	r.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	content, err := os.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("read registry file failed: %w", err)
+	}
+	var catalog fileCatalog
+	if err := yaml.Unmarshal(content, &catalog); err != nil {
+		return fmt.Errorf("parse registry file failed: %w", err)
+	}
+
+	services := make([]*confv1.Discovery_Service, 0, len(catalog.Services))
+	for _, s := range catalog.Services {
+		services = append(services, &confv1.Discovery_Service{Name: s.Name, Version: s.Version, Addrs: s.Addrs})
+	}
+
+	r.mu.Lock()
+	r.services = toServiceInstances(services, r.logger)
+	r.modTime = info.ModTime()
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *FileRegistry) instances(service string) []Instance {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.services[service]
+}
+
+// Watch 返回的 Watcher 在文件变化后更新实例列表；文件读取或解析失败时保留上一次的结果
+func (r *FileRegistry) Watch(name, version string) (Watcher, error) {
+	service := ServiceName(name, version)
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &fileWatcher{
+		registry:  r,
+		service:   service,
+		instances: r.instances(service),
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	go w.run(ctx)
+	return w, nil
+}
+
+type fileWatcher struct {
+	registry *FileRegistry
+	service  string
+
+	mu        sync.RWMutex
+	instances []Instance
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (w *fileWatcher) run(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.registry.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := w.registry.reload(); err != nil {
+			w.registry.logger.Warn("Failed to reload registry file, keeping last known instances", zap.Error(err))
+			continue
+		}
+
+		instances := w.registry.instances(w.service)
+		w.mu.Lock()
+		changed := !sameInstances(w.instances, instances)
+		w.instances = instances
+		w.mu.Unlock()
+		if changed {
+			w.registry.logger.Info("Service instances changed", zap.String("service", w.service), zap.Int("count", len(instances)))
+		}
+	}
+}
+
+func (w *fileWatcher) Instances() []Instance {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.instances
+}
+
+func (w *fileWatcher) WaitReady(ctx context.Context) error {
+	return nil
+}
+
+func (w *fileWatcher) Stop(ctx context.Context) error {
+	w.cancel()
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,83 @@
+// Package secretbox 使用 AES-256-GCM 加密需要落盘的敏感数据，例如 TOTP 密钥
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// KeySize 密钥长度（字节）
+const KeySize = 32
+
+// version 密文格式版本，更换算法或密钥时用于区分旧数据
+const version byte = 1
+
+var (
+	ErrInvalidKey        = errors.New("secretbox: key must be 32 bytes")
+	ErrInvalidCiphertext = errors.New("secretbox: invalid ciphertext")
+)
+
+// Box 对称加密器
+// 密文格式为 version || nonce || ciphertext+tag
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New 使用 32 字节的密钥创建加密器
+func New(key []byte) (*Box, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("secretbox: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("secretbox: %w", err)
+	}
+	return &Box{aead: aead}, nil
+}
+
+// DecodeKey 解析 base64 编码的密钥
+func DecodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("secretbox: decode key failed: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+// Seal 加密 plaintext；additionalData 不加密但参与认证，
+// 例如传入记录的主键，使密文无法被挪到其他记录上使用
+func (b *Box) Seal(plaintext, additionalData []byte) ([]byte, error) {
+	nonceSize := b.aead.NonceSize()
+	out := make([]byte, 1+nonceSize, 1+nonceSize+len(plaintext)+b.aead.Overhead())
+	out[0] = version
+	if _, err := rand.Read(out[1:]); err != nil {
+		return nil, fmt.Errorf("secretbox: generate nonce failed: %w", err)
+	}
+	return b.aead.Seal(out, out[1:], plaintext, additionalData), nil
+}
+
+// Open 解密 Seal 生成的密文，密文被篡改或 additionalData 不一致时返回 ErrInvalidCiphertext
+func (b *Box) Open(ciphertext, additionalData []byte) ([]byte, error) {
+	nonceSize := b.aead.NonceSize()
+	if len(ciphertext) < 1+nonceSize+b.aead.Overhead() || ciphertext[0] != version {
+		return nil, ErrInvalidCiphertext
+	}
+
+	plaintext, err := b.aead.Open(nil, ciphertext[1:1+nonceSize], ciphertext[1+nonceSize:], additionalData)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,76 @@
+package secretbox
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBox(t *testing.T, fill byte) *Box {
+	box, err := New(bytes.Repeat([]byte{fill}, KeySize))
+	require.NoError(t, err)
+	return box
+}
+
+func TestSealOpen(t *testing.T) {
+	box := newBox(t, 1)
+
+	sealed, err := box.Seal([]byte("secret"), []byte("user:1"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "secret")
+
+	plaintext, err := box.Open(sealed, []byte("user:1"))
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+
+	// 每次加密使用新的 nonce
+	again, err := box.Seal([]byte("secret"), []byte("user:1"))
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again)
+}
+
+func TestOpen_Invalid(t *testing.T) {
+	box := newBox(t, 1)
+	sealed, err := box.Seal([]byte("secret"), []byte("user:1"))
+	require.NoError(t, err)
+
+	tampered := bytes.Clone(sealed)
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := []struct {
+		name       string
+		box        *Box
+		ciphertext []byte
+		ad         string
+	}{
+		{"tampered", box, tampered, "user:1"},
+		{"other additional data", box, sealed, "user:2"},
+		{"other key", newBox(t, 2), sealed, "user:1"},
+		{"truncated", box, sealed[:10], "user:1"},
+		{"unknown version", box, append([]byte{9}, sealed[1:]...), "user:1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.box.Open(tt.ciphertext, []byte(tt.ad))
+			assert.ErrorIs(t, err, ErrInvalidCiphertext)
+		})
+	}
+}
+
+func TestDecodeKey(t *testing.T) {
+	key, err := DecodeKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, KeySize)))
+	require.NoError(t, err)
+	assert.Len(t, key, KeySize)
+
+	_, err = DecodeKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = DecodeKey("not base64!")
+	assert.Error(t, err)
+
+	_, err = New([]byte("short"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"connect-go-example/api/user/v1/userv1connect"
+	"connect-go-example/internal/biz"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	conf "connect-go-example/internal/conf/v1"
+
+	"connectrpc.com/connect"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"go.uber.org/zap"
+)
+
+// defaultPublicProcedures 不需要认证即可调用的 RPC
+var defaultPublicProcedures = []string{
+	userv1connect.UserServiceSignInProcedure,
+	userv1connect.UserServiceRegisterProcedure,
+	userv1connect.UserServicePasswordSignInProcedure,
+	userv1connect.UserServiceRefreshTokenProcedure,
+	userv1connect.UserServiceVerifyMFAProcedure,
+	userv1connect.UserServiceRequestPasswordResetProcedure,
+	userv1connect.UserServiceResetPasswordProcedure,
+	userv1connect.UserServiceVerifyEmailProcedure,
+}
+
+var (
+	errMissingToken = errors.New("missing bearer token")
+	errInvalidToken = errors.New("invalid or expired token")
+)
+
+// AuthInterceptor 校验 Bearer 令牌并将调用方身份写入 context
+// 令牌可以是 Casdoor 签发的 JWT（使用 conf.Auth.Certificate 验签），也可以是本地账号的不透明会话 ID
+type AuthInterceptor struct {
+	auth     *casdoorsdk.Client
+	sessions biz.SessionRepo
+	public   map[string]struct{}
+	logger   *zap.Logger
+}
+
+func NewAuthInterceptor(cfg *conf.Bootstrap, auth *casdoorsdk.Client, sessions biz.SessionRepo, logger *zap.Logger) *AuthInterceptor {
+	public := make(map[string]struct{})
+	for _, procedure := range defaultPublicProcedures {
+		public[procedure] = struct{}{}
+	}
+	for _, procedure := range cfg.GetAuth().GetPublicProcedures() {
+		public[procedure] = struct{}{}
+	}
+
+	return &AuthInterceptor{
+		auth:     auth,
+		sessions: sessions,
+		public:   public,
+		logger:   logger,
+	}
+}
+
+func (a *AuthInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+
+		ctx, err := a.authenticate(ctx, req.Spec().Procedure, req.Header())
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient 客户端流不做处理，直接透传
+func (a *AuthInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (a *AuthInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := a.authenticate(ctx, conn.Spec().Procedure, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// authenticate 解析请求头中的令牌，公开 RPC 直接放行
+func (a *AuthInterceptor) authenticate(ctx context.Context, procedure string, header http.Header) (context.Context, error) {
+	if _, ok := a.public[procedure]; ok {
+		return ctx, nil
+	}
+
+	token, ok := bearerToken(header)
+	if !ok {
+		return ctx, connect.NewError(connect.CodeUnauthenticated, errMissingToken)
+	}
+
+	principal, err := a.verify(ctx, token)
+	if err != nil {
+		a.logger.Debug("Authentication failed", zap.String("procedure", procedure), zap.Error(err))
+		return ctx, connect.NewError(connect.CodeUnauthenticated, errInvalidToken)
+	}
+
+	return biz.NewPrincipalContext(ctx, principal), nil
+}
+
+// verify JWT 由三段组成，其余令牌按会话 ID 处理
+func (a *AuthInterceptor) verify(ctx context.Context, token string) (*biz.Principal, error) {
+	if strings.Count(token, ".") == 2 {
+		return a.verifyJWT(token)
+	}
+	return a.verifySession(ctx, token)
+}
+
+func (a *AuthInterceptor) verifyJWT(token string) (*biz.Principal, error) {
+	if a.auth == nil {
+		return nil, errors.New("auth client is nil")
+	}
+	claims, err := a.auth.ParseJwtToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	principal := &biz.Principal{
+		Source:   biz.PrincipalSourceJWT,
+		Subject:  claims.Id,
+		Username: claims.Name,
+	}
+	for _, role := range claims.Roles {
+		if role != nil {
+			principal.Roles = append(principal.Roles, role.Name)
+		}
+	}
+	for _, permission := range claims.Permissions {
+		if permission != nil {
+			principal.Permissions = append(principal.Permissions, permission.Name)
+		}
+	}
+	return principal, nil
+}
+
+func (a *AuthInterceptor) verifySession(ctx context.Context, sessionID string) (*biz.Principal, error) {
+	session, err := a.sessions.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &biz.Principal{
+		Source:    biz.PrincipalSourceSession,
+		UserID:    session.UserID,
+		Username:  session.Username,
+		SessionID: session.ID,
+		Roles:     session.Roles,
+	}, nil
+}
+
+// bearerToken 从 Authorization 头中取出 Bearer 令牌
+func bearerToken(header http.Header) (string, bool) {
+	const prefix = "Bearer "
+	value := header.Get("Authorization")
+	if len(value) <= len(prefix) || !strings.EqualFold(value[:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(value[len(prefix):]), true
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "connect-go-example/api/user/v1"
+	"connect-go-example/api/user/v1/userv1connect"
+	"connect-go-example/internal/biz"
+	conf "connect-go-example/internal/conf/v1"
+
+	"connectrpc.com/connect"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+// stubUserService 记录 handler 看到的调用方身份
+type stubUserService struct {
+	userv1connect.UnimplementedUserServiceHandler
+	principal *biz.Principal
+}
+
+func (s *stubUserService) SignIn(ctx context.Context, req *connect.Request[v1.SignInRequest]) (*connect.Response[v1.SignInResponse], error) {
+	s.principal, _ = biz.PrincipalFromContext(ctx)
+	return connect.NewResponse(&v1.SignInResponse{}), nil
+}
+
+func (s *stubUserService) SignOut(ctx context.Context, req *connect.Request[v1.SignOutRequest]) (*connect.Response[v1.SignOutResponse], error) {
+	s.principal, _ = biz.PrincipalFromContext(ctx)
+	return connect.NewResponse(&v1.SignOutResponse{}), nil
+}
+
+// stubSessionRepo 只实现 GetSession
+type stubSessionRepo struct {
+	biz.SessionRepo
+	sessions map[string]*biz.Session
+}
+
+func (r *stubSessionRepo) GetSession(ctx context.Context, sessionID string) (*biz.Session, error) {
+	if s, ok := r.sessions[sessionID]; ok {
+		return s, nil
+	}
+	return nil, biz.ErrSessionNotFound
+}
+
+// AuthInterceptorTestSuite 是 AuthInterceptor 的测试套件
+type AuthInterceptorTestSuite struct {
+	suite.Suite
+	key     *rsa.PrivateKey
+	service *stubUserService
+	client  userv1connect.UserServiceClient
+}
+
+func (suite *AuthInterceptorTestSuite) SetupTest() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(suite.T(), err)
+	suite.key = key
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "casdoor-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(suite.T(), err)
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	sessions := &stubSessionRepo{sessions: map[string]*biz.Session{
+		"valid-session": {ID: "valid-session", UserID: 42, Username: "alice", Roles: []string{"admin"}},
+	}}
+	interceptor := NewAuthInterceptor(
+		&conf.Bootstrap{Auth: &conf.Auth{}},
+		casdoorsdk.NewClient("http://casdoor.invalid", "id", "secret", certificate, "built-in", "app"),
+		sessions,
+		zap.NewNop(),
+	)
+
+	suite.service = &stubUserService{}
+	path, handler := userv1connect.NewUserServiceHandler(suite.service, connect.WithInterceptors(interceptor))
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	server := httptest.NewServer(mux)
+	suite.T().Cleanup(server.Close)
+
+	suite.client = userv1connect.NewUserServiceClient(server.Client(), server.URL)
+}
+
+func (suite *AuthInterceptorTestSuite) signOut(token string) error {
+	req := connect.NewRequest(&v1.SignOutRequest{})
+	if token != "" {
+		req.Header().Set("Authorization", "Bearer "+token)
+	}
+	_, err := suite.client.SignOut(context.Background(), req)
+	return err
+}
+
+func (suite *AuthInterceptorTestSuite) signJWT(expiresAt time.Time) string {
+	claims := casdoorsdk.Claims{
+		User: casdoorsdk.User{
+			Owner: "built-in",
+			Name:  "bob",
+			Id:    "user-uuid",
+			Roles: []*casdoorsdk.Role{{Name: "admin"}},
+		},
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(expiresAt)},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(suite.key)
+	require.NoError(suite.T(), err)
+	return token
+}
+
+func (suite *AuthInterceptorTestSuite) TestPublicProcedure() {
+	_, err := suite.client.SignIn(context.Background(), connect.NewRequest(&v1.SignInRequest{}))
+
+	require.NoError(suite.T(), err)
+	assert.Nil(suite.T(), suite.service.principal)
+}
+
+func (suite *AuthInterceptorTestSuite) TestMissingToken() {
+	err := suite.signOut("")
+
+	assert.Equal(suite.T(), connect.CodeUnauthenticated, connect.CodeOf(err))
+}
+
+func (suite *AuthInterceptorTestSuite) TestSessionToken() {
+	require.NoError(suite.T(), suite.signOut("valid-session"))
+
+	p := suite.service.principal
+	require.NotNil(suite.T(), p)
+	assert.Equal(suite.T(), biz.PrincipalSourceSession, p.Source)
+	assert.Equal(suite.T(), int64(42), p.UserID)
+	assert.Equal(suite.T(), "valid-session", p.SessionID)
+	assert.Equal(suite.T(), []string{"admin"}, p.Roles)
+}
+
+func (suite *AuthInterceptorTestSuite) TestUnknownSession() {
+	err := suite.signOut("unknown-session")
+
+	assert.Equal(suite.T(), connect.CodeUnauthenticated, connect.CodeOf(err))
+}
+
+func (suite *AuthInterceptorTestSuite) TestJWT() {
+	require.NoError(suite.T(), suite.signOut(suite.signJWT(time.Now().Add(time.Hour))))
+
+	p := suite.service.principal
+	require.NotNil(suite.T(), p)
+	assert.Equal(suite.T(), biz.PrincipalSourceJWT, p.Source)
+	assert.Equal(suite.T(), "user-uuid", p.Subject)
+	assert.Equal(suite.T(), "bob", p.Username)
+	assert.Equal(suite.T(), []string{"admin"}, p.Roles)
+}
+
+func (suite *AuthInterceptorTestSuite) TestExpiredJWT() {
+	err := suite.signOut(suite.signJWT(time.Now().Add(-time.Hour)))
+
+	assert.Equal(suite.T(), connect.CodeUnauthenticated, connect.CodeOf(err))
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		token  string
+		ok     bool
+	}{
+		{"Bearer abc", "abc", true},
+		{"bearer abc", "abc", true},
+		{"Basic abc", "", false},
+		{"Bearer ", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		h := http.Header{}
+		h.Set("Authorization", tt.header)
+		token, ok := bearerToken(h)
+		assert.Equal(t, tt.ok, ok, tt.header)
+		assert.Equal(t, tt.token, token, tt.header)
+	}
+}
+
+// 运行测试套件
+func TestAuthInterceptorTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthInterceptorTestSuite))
+}
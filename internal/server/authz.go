@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"connect-go-example/internal/biz"
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/authz"
+	"connect-go-example/internal/pkg/config"
+
+	"connectrpc.com/connect"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+var errPermissionDenied = errors.New("permission denied")
+
+// NewPolicyEvaluator 根据 conf.Authorization 创建策略求值器
+// 配置了 consul_key 时单独监听该 key，否则跟随整体配置的热更新
+func NewPolicyEvaluator(cfg *conf.Bootstrap, logger *zap.Logger) *authz.Evaluator {
+	evaluator := authz.NewEvaluator(cfg.GetAuthorization())
+
+	key := cfg.GetAuthorization().GetConsulKey()
+	if key == "" {
+		config.OnChange(func(c *conf.Bootstrap) {
+			evaluator.Update(c.GetAuthorization())
+			logger.Info("Authorization policies reloaded")
+		})
+		return evaluator
+	}
+
+	err := config.WatchKey(key, func(m map[string]interface{}) {
+		policies := &conf.Authorization{}
+		if err := config.Decode(m, policies); err != nil {
+			logger.Error("Failed to decode authorization policies", zap.String("key", key), zap.Error(err))
+			return
+		}
+		evaluator.Update(policies)
+		logger.Info("Authorization policies reloaded", zap.String("key", key), zap.Int("policies", len(policies.Policies)))
+	})
+	if err != nil {
+		logger.Error("Failed to watch authorization policies, using local config", zap.String("key", key), zap.Error(err))
+	}
+
+	return evaluator
+}
+
+// AuthzInterceptor 根据调用方的角色和权限判断能否调用 procedure
+// 需要放在 AuthInterceptor 之后；没有调用方身份的请求（公开 RPC）直接放行
+type AuthzInterceptor struct {
+	evaluator *authz.Evaluator
+	logger    *zap.Logger
+}
+
+func NewAuthzInterceptor(evaluator *authz.Evaluator, logger *zap.Logger) *AuthzInterceptor {
+	return &AuthzInterceptor{
+		evaluator: evaluator,
+		logger:    logger,
+	}
+}
+
+func (a *AuthzInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+
+		if err := a.authorize(ctx, req.Spec().Procedure); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient 客户端流不做处理，直接透传
+func (a *AuthzInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (a *AuthzInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := a.authorize(ctx, conn.Spec().Procedure); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+func (a *AuthzInterceptor) authorize(ctx context.Context, procedure string) error {
+	principal, ok := biz.PrincipalFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	decision := a.evaluator.Evaluate(procedure, principal.Roles, principal.Permissions)
+	if decision.Allowed {
+		return nil
+	}
+
+	a.logger.Info("Permission denied",
+		zap.String("procedure", procedure),
+		zap.String("username", principal.Username),
+		zap.String("policy", decision.Policy),
+	)
+
+	connectErr := connect.NewError(connect.CodePermissionDenied, errPermissionDenied)
+	detail, err := connect.NewErrorDetail(&errdetails.ErrorInfo{
+		Reason: "PERMISSION_DENIED",
+		Domain: biz.ErrorDomain,
+		Metadata: map[string]string{
+			"procedure":           procedure,
+			"policy":              decision.Policy,
+			"required_roles":      strings.Join(decision.RequiredRoles, ","),
+			"missing_permissions": strings.Join(decision.MissingPermissions, ","),
+		},
+	})
+	if err == nil {
+		connectErr.AddDetail(detail)
+	}
+	return connectErr
+}
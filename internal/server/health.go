@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"connect-go-example/api/user/v1/userv1connect"
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/data"
+	"connect-go-example/internal/pkg/health"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// NewHealthChecker 创建依赖健康检查，Postgres、Redis、Elasticsearch 和 Casdoor 各自作为一个服务上报状态，
+// 业务服务和整体状态只依赖 Postgres 和 Redis
+func NewHealthChecker(lc fx.Lifecycle, cfg *conf.Bootstrap, d *data.Data, logger *zap.Logger) *health.Checker {
+	healthCfg := cfg.GetServer().GetHealth()
+	checker := health.NewChecker(
+		d.HealthProbes(),
+		[]string{userv1connect.UserServiceName},
+		time.Duration(healthCfg.GetInterval())*time.Second,
+		time.Duration(healthCfg.GetTimeout())*time.Second,
+		logger,
+	)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			checker.Start(ctx)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			checker.Shutdown()
+			return checker.Stop(ctx)
+		},
+	})
+
+	return checker
+}
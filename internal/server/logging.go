@@ -32,31 +32,69 @@ func (l *LoggingInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc
 			zap.Duration("duration", duration),
 		}
 
-		if err != nil {
-			fields = append(fields, zap.Error(err))
-
-			// 错误分级逻辑
-			switch code {
-			case connect.CodeNotFound, connect.CodeCanceled, connect.CodeInvalidArgument, connect.CodeAlreadyExists, connect.CodeUnauthenticated:
-				l.logger.Warn("RPC business error", fields...)
-			case connect.CodeDeadlineExceeded:
-				l.logger.Warn("RPC deadline exceeded", fields...)
-			default:
-				// 系统级错误 (Unknown, Internal, DataLoss, etc.)
-				l.logger.Error("RPC system error", fields...)
-			}
-		} else {
-			l.logger.Info("RPC success", fields...)
-		}
+		l.log(code, err, fields)
 
 		return resp, err
 	}
 }
 
+// log 按错误码分级记录
+func (l *LoggingInterceptor) log(code connect.Code, err error, fields []zap.Field) {
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+
+		// 错误分级逻辑
+		switch code {
+		case connect.CodeNotFound, connect.CodeCanceled, connect.CodeInvalidArgument, connect.CodeAlreadyExists, connect.CodeUnauthenticated:
+			l.logger.Warn("RPC business error", fields...)
+		case connect.CodeDeadlineExceeded:
+			l.logger.Warn("RPC deadline exceeded", fields...)
+		default:
+			// 系统级错误 (Unknown, Internal, DataLoss, etc.)
+			l.logger.Error("RPC system error", fields...)
+		}
+	} else {
+		l.logger.Info("RPC success", fields...)
+	}
+}
+
+// streamFields 流结束时记录的字段
+func streamFields(procedure string, code connect.Code, stats *streamStats) []zap.Field {
+	fields := []zap.Field{
+		zap.String("rpc.service", procedure),
+		zap.String("rpc.code", code.String()),
+		zap.Duration("duration", time.Since(stats.start)),
+		zap.Int64("messages_sent", stats.sent.Load()),
+		zap.Int64("messages_received", stats.received.Load()),
+	}
+	if latency, ok := stats.firstMessageLatency(); ok {
+		fields = append(fields, zap.Duration("first_message", latency))
+	}
+	return fields
+}
+
 func (l *LoggingInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
-	return next
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		stats := newStreamStats()
+		return &statsClientConn{
+			StreamingClientConn: next(ctx, spec),
+			stats:               stats,
+			done: func(err error) {
+				code := connect.CodeOf(err)
+				l.log(code, err, streamFields(spec.Procedure, code, stats))
+			},
+		}
+	}
 }
 
 func (l *LoggingInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
-	return next
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		stats := newStreamStats()
+
+		err := next(ctx, &statsHandlerConn{StreamingHandlerConn: conn, stats: stats})
+
+		code := connect.CodeOf(err)
+		l.log(code, err, streamFields(conn.Spec().Procedure, code, stats))
+		return err
+	}
 }
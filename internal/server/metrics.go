@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
 	"connectrpc.com/connect"
@@ -14,6 +15,19 @@ import (
 type MetricsInterceptor struct {
 	requestCounter  metric.Int64Counter
 	requestDuration metric.Float64Histogram
+
+	// 流式 RPC 的指标，服务端和客户端分别统计
+	serverStreams *streamInstruments
+	clientStreams *streamInstruments
+}
+
+// streamInstruments 流式 RPC 的指标，请求数和耗时与一元 RPC 使用相同的名称
+type streamInstruments struct {
+	requests     metric.Int64Counter
+	duration     metric.Float64Histogram
+	sent         metric.Int64Histogram
+	received     metric.Int64Histogram
+	firstMessage metric.Float64Histogram
 }
 
 // NewMetricsInterceptor 初始化并返回拦截器
@@ -41,6 +55,73 @@ func NewMetricsInterceptor() *MetricsInterceptor {
 	return &MetricsInterceptor{
 		requestCounter:  reqCounter,
 		requestDuration: reqDuration,
+		serverStreams:   newStreamInstruments(meter, "rpc.server"),
+		clientStreams:   newStreamInstruments(meter, "rpc.client"),
+	}
+}
+
+func newStreamInstruments(meter metric.Meter, prefix string) *streamInstruments {
+	requests, err := meter.Int64Counter(
+		prefix+".requests_total",
+		metric.WithDescription("Total number of RPC requests handled"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		panic(fmt.Errorf("failed to init counter: %w", err))
+	}
+
+	duration, err := meter.Float64Histogram(
+		prefix+".duration_ms",
+		metric.WithDescription("Duration of RPC requests in milliseconds"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		panic(fmt.Errorf("failed to init histogram: %w", err))
+	}
+
+	sent, err := meter.Int64Histogram(
+		prefix+".stream.messages_sent",
+		metric.WithDescription("Number of messages sent per streaming RPC"),
+		metric.WithUnit("{message}"),
+	)
+	if err != nil {
+		panic(fmt.Errorf("failed to init histogram: %w", err))
+	}
+
+	received, err := meter.Int64Histogram(
+		prefix+".stream.messages_received",
+		metric.WithDescription("Number of messages received per streaming RPC"),
+		metric.WithUnit("{message}"),
+	)
+	if err != nil {
+		panic(fmt.Errorf("failed to init histogram: %w", err))
+	}
+
+	firstMessage, err := meter.Float64Histogram(
+		prefix+".stream.first_message_ms",
+		metric.WithDescription("Latency until the first response message of a streaming RPC in milliseconds"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		panic(fmt.Errorf("failed to init histogram: %w", err))
+	}
+
+	return &streamInstruments{
+		requests:     requests,
+		duration:     duration,
+		sent:         sent,
+		received:     received,
+		firstMessage: firstMessage,
+	}
+}
+
+// rpcAttributes 一元和流式 RPC 共用的指标属性
+func rpcAttributes(procedure, httpMethod string, code connect.Code) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("rpc.system", "connect"),
+		attribute.String("rpc.service", procedure),
+		attribute.String("rpc.method", httpMethod),
+		attribute.String("rpc.connect_status_code", code.String()),
 	}
 }
 
@@ -53,12 +134,7 @@ func (m *MetricsInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc
 		duration := float64(time.Since(startTime).Milliseconds())
 		code := connect.CodeOf(err)
 
-		attrs := []attribute.KeyValue{
-			attribute.String("rpc.system", "connect"),
-			attribute.String("rpc.service", req.Spec().Procedure),
-			attribute.String("rpc.method", req.HTTPMethod()),
-			attribute.String("rpc.connect_status_code", code.String()),
-		}
+		attrs := rpcAttributes(req.Spec().Procedure, req.HTTPMethod(), code)
 
 		m.requestCounter.Add(ctx, 1, metric.WithAttributes(attrs...))
 		m.requestDuration.Record(ctx, duration, metric.WithAttributes(attrs...))
@@ -67,12 +143,41 @@ func (m *MetricsInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc
 	}
 }
 
-// WrapStreamingClient 处理客户端流
+// WrapStreamingClient 处理客户端流，在 CloseResponse 时记录
 func (m *MetricsInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
-	return next // 目前不做处理，直接透传
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		stats := newStreamStats()
+		return &statsClientConn{
+			StreamingClientConn: next(ctx, spec),
+			stats:               stats,
+			done: func(err error) {
+				m.clientStreams.record(ctx, spec.Procedure, stats, err)
+			},
+		}
+	}
 }
 
-// WrapStreamingHandler 处理服务端流
+// WrapStreamingHandler 处理服务端流，在 handler 返回时记录
 func (m *MetricsInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
-	return next // 目前不做处理，直接透传
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		stats := newStreamStats()
+
+		err := next(ctx, &statsHandlerConn{StreamingHandlerConn: conn, stats: stats})
+
+		m.serverStreams.record(ctx, conn.Spec().Procedure, stats, err)
+		return err
+	}
+}
+
+func (s *streamInstruments) record(ctx context.Context, procedure string, stats *streamStats, err error) {
+	// 流式 RPC 总是使用 POST
+	attrs := metric.WithAttributes(rpcAttributes(procedure, http.MethodPost, connect.CodeOf(err))...)
+
+	s.requests.Add(ctx, 1, attrs)
+	s.duration.Record(ctx, float64(time.Since(stats.start).Milliseconds()), attrs)
+	s.sent.Record(ctx, stats.sent.Load(), attrs)
+	s.received.Record(ctx, stats.received.Load(), attrs)
+	if latency, ok := stats.firstMessageLatency(); ok {
+		s.firstMessage.Record(ctx, float64(latency.Milliseconds()), attrs)
+	}
 }
@@ -12,6 +12,12 @@ var MiddlewareModule = fx.Module("server.middleware",
 		// 提供单独地拦截器实例
 		NewMetricsInterceptor,
 		NewLoggingInterceptor,
+		NewRecoveryInterceptor,
+		NewAuthInterceptor,
+		NewRateLimitInterceptor,
+		NewPolicyEvaluator,
+		NewAuthzInterceptor,
+		NewValidationInterceptor,
 
 		// 组装成一个拦截器切片，或者直接返回 Connect Option
 		NewConnectOptions,
@@ -22,6 +28,11 @@ func NewConnectOptions(
 	logger *zap.Logger,
 	metrics *MetricsInterceptor,
 	logging *LoggingInterceptor,
+	recovery *RecoveryInterceptor,
+	auth *AuthInterceptor,
+	rateLimit *RateLimitInterceptor,
+	authz *AuthzInterceptor,
+	validation *ValidationInterceptor,
 ) []connect.HandlerOption {
 
 	otelInterceptor, err := otelconnect.NewInterceptor()
@@ -34,6 +45,14 @@ func NewConnectOptions(
 			otelInterceptor,
 			metrics,
 			logging,
+			// recovery 位于日志和指标之内，panic 转换后的 CodeInternal 会被正常记录
+			recovery,
+			auth,
+			// 限流位于认证之后，可以按用户限流
+			rateLimit,
+			authz,
+			// 校验位于鉴权之后，未授权的调用方无法探测请求格式
+			validation,
 		),
 	}
 }
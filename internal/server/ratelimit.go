@@ -0,0 +1,275 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"connect-go-example/api/user/v1/userv1connect"
+	"connect-go-example/internal/biz"
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/ratelimit"
+
+	"connectrpc.com/connect"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// defaultAPIKeyHeader 默认携带 API key 的请求头
+	defaultAPIKeyHeader = "X-API-Key"
+	// rateLimitKeyPrefix Redis 中限流计数的 key 前缀
+	rateLimitKeyPrefix = "ratelimit:"
+)
+
+// 限流维度
+const (
+	rateLimitByIP     = "ip"
+	rateLimitByUser   = "user"
+	rateLimitByAPIKey = "api_key"
+)
+
+var errRateLimited = errors.New("rate limit exceeded")
+
+// defaultRateLimitRules 内置的限流规则，保护无需登录即可发送邮件、尝试令牌或密码的接口
+// 配置中同名 procedure 的规则覆盖默认规则，limit 为 0 时关闭
+var defaultRateLimitRules = []*conf.RateLimit_Rule{
+	{Procedure: userv1connect.UserServiceRequestPasswordResetProcedure, Limit: 5, Period: 3600},
+	{Procedure: userv1connect.UserServiceResetPasswordProcedure, Limit: 10, Period: 60},
+	{Procedure: userv1connect.UserServiceVerifyEmailProcedure, Limit: 10, Period: 60},
+	// 账户锁定只保护单个账户，按 IP 限流防止同一来源轮换用户名撞库
+	{Procedure: userv1connect.UserServicePasswordSignInProcedure, Limit: 10, Period: 60},
+	{Procedure: userv1connect.UserServiceSignInProcedure, Limit: 20, Period: 60},
+}
+
+// rateLimitRule 编译后的限流规则
+type rateLimitRule struct {
+	procedure string // 配置中的 procedure，服务通配时以 /* 结尾
+	key       string
+	limit     ratelimit.Limit
+}
+
+// RateLimitInterceptor 按 procedure 和调用方限流，超过限制时返回 CodeResourceExhausted
+// 位于认证之后以便按用户限流，因此认证失败的请求不计入限流
+type RateLimitInterceptor struct {
+	limiter           ratelimit.Limiter
+	exact             map[string]rateLimitRule
+	services          map[string]rateLimitRule // key 为 /pkg.Service/
+	apiKeyHeader      string
+	trustForwardedFor bool
+	logger            *zap.Logger
+	rejected          metric.Int64Counter
+}
+
+// NewRateLimitInterceptor 初始化并返回拦截器，计数保存在 Redis 中，Redis 不可用时退化为进程内限流
+func NewRateLimitInterceptor(cfg *conf.Bootstrap, rdb *redis.Client, logger *zap.Logger) *RateLimitInterceptor {
+	limiter := ratelimit.NewFallbackLimiter(ratelimit.NewRedisLimiter(rdb), ratelimit.NewLocalLimiter(), logger)
+	return newRateLimitInterceptor(cfg.GetRateLimit(), limiter, logger)
+}
+
+func newRateLimitInterceptor(cfg *conf.RateLimit, limiter ratelimit.Limiter, logger *zap.Logger) *RateLimitInterceptor {
+	meter := otel.GetMeterProvider().Meter("github.com/sunmery/ecommerce/backend/server")
+
+	rejected, err := meter.Int64Counter(
+		"rpc.server.rate_limited",
+		metric.WithDescription("Number of RPC requests rejected by the rate limiter"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		panic(fmt.Errorf("failed to init counter: %w", err))
+	}
+
+	r := &RateLimitInterceptor{
+		limiter:           limiter,
+		exact:             make(map[string]rateLimitRule),
+		services:          make(map[string]rateLimitRule),
+		apiKeyHeader:      cfg.GetApiKeyHeader(),
+		trustForwardedFor: cfg.GetTrustForwardedFor(),
+		logger:            logger,
+		rejected:          rejected,
+	}
+	if r.apiKeyHeader == "" {
+		r.apiKeyHeader = defaultAPIKeyHeader
+	}
+
+	for _, rule := range slices.Concat(defaultRateLimitRules, cfg.GetRules()) {
+		if rule.GetProcedure() == "" {
+			continue
+		}
+		service, wildcard := strings.CutSuffix(rule.GetProcedure(), "*")
+		if rule.GetLimit() <= 0 {
+			if wildcard {
+				delete(r.services, service)
+			} else {
+				delete(r.exact, rule.GetProcedure())
+			}
+			continue
+		}
+		compiled := rateLimitRule{
+			procedure: rule.GetProcedure(),
+			key:       rule.GetKey(),
+			limit: ratelimit.Limit{
+				Rate:   rule.GetLimit(),
+				Period: time.Duration(rule.GetPeriod()) * time.Second,
+				Burst:  rule.GetBurst(),
+			},
+		}
+		if compiled.key == "" {
+			compiled.key = rateLimitByIP
+		}
+		if compiled.limit.Period <= 0 {
+			compiled.limit.Period = time.Second
+		}
+		if compiled.limit.Burst <= 0 {
+			compiled.limit.Burst = compiled.limit.Rate
+		}
+
+		if wildcard {
+			r.services[service] = compiled
+			continue
+		}
+		r.exact[rule.GetProcedure()] = compiled
+	}
+
+	return r
+}
+
+func (r *RateLimitInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+
+		if err := r.allow(ctx, req.Spec().Procedure, req.Peer(), req.Header()); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (r *RateLimitInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler 流式 RPC 只在建立时计数一次
+func (r *RateLimitInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := r.allow(ctx, conn.Spec().Procedure, conn.Peer(), conn.RequestHeader()); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// allow 检查请求是否超过限制，没有配置规则的 procedure 直接放行
+func (r *RateLimitInterceptor) allow(ctx context.Context, procedure string, peer connect.Peer, header http.Header) error {
+	rule, ok := r.rule(procedure)
+	if !ok {
+		return nil
+	}
+
+	kind, caller := r.caller(ctx, rule.key, peer, header)
+	res, err := r.limiter.Allow(ctx, rateLimitKeyPrefix+procedure+":"+kind+":"+caller, rule.limit)
+	if err != nil {
+		// 限流器只在请求被取消时返回错误，此时放行由后续处理返回取消错误
+		r.logger.Debug("Rate limit check failed", zap.String("procedure", procedure), zap.Error(err))
+		return nil
+	}
+	if res.Allowed {
+		return nil
+	}
+
+	r.rejected.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("rpc.system", "connect"),
+		attribute.String("rpc.service", procedure),
+		attribute.String("ratelimit.key", kind),
+	))
+	return rateLimitedError(rule.procedure, res.RetryAfter)
+}
+
+// rule 精确匹配优先于服务通配
+func (r *RateLimitInterceptor) rule(procedure string) (rateLimitRule, bool) {
+	if rule, ok := r.exact[procedure]; ok {
+		return rule, true
+	}
+	if idx := strings.LastIndex(procedure, "/"); idx >= 0 {
+		rule, ok := r.services[procedure[:idx+1]]
+		return rule, ok
+	}
+	return rateLimitRule{}, false
+}
+
+// caller 返回实际使用的限流维度和调用方标识，user 和 api_key 缺失时按 IP 限流
+func (r *RateLimitInterceptor) caller(ctx context.Context, key string, peer connect.Peer, header http.Header) (string, string) {
+	switch key {
+	case rateLimitByUser:
+		if principal, ok := biz.PrincipalFromContext(ctx); ok {
+			if principal.UserID != 0 {
+				return rateLimitByUser, strconv.FormatInt(principal.UserID, 10)
+			}
+			if principal.Subject != "" {
+				return rateLimitByUser, principal.Subject
+			}
+		}
+	case rateLimitByAPIKey:
+		if apiKey := header.Get(r.apiKeyHeader); apiKey != "" {
+			// 不在 Redis 中保存明文 API key
+			sum := sha256.Sum256([]byte(apiKey))
+			return rateLimitByAPIKey, hex.EncodeToString(sum[:])
+		}
+	}
+	return rateLimitByIP, r.clientIP(peer, header)
+}
+
+// clientIP 只有显式信任反向代理时才读取 X-Forwarded-For，否则调用方可以伪造 IP 绕过限流
+func (r *RateLimitInterceptor) clientIP(peer connect.Peer, header http.Header) string {
+	if r.trustForwardedFor {
+		if forwarded := header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			if ip := strings.TrimSpace(first); ip != "" {
+				return ip
+			}
+		}
+	}
+	if host, _, err := net.SplitHostPort(peer.Addr); err == nil {
+		return host
+	}
+	return peer.Addr
+}
+
+// rateLimitedError 携带 Retry-After 头、RetryInfo 和 ErrorInfo 的 CodeResourceExhausted 错误
+func rateLimitedError(procedure string, retryAfter time.Duration) error {
+	connectErr := connect.NewError(connect.CodeResourceExhausted, errRateLimited)
+	// Retry-After 只支持整秒，向上取整避免客户端过早重试
+	connectErr.Meta().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(retryAfter.Seconds())), 10))
+
+	details := []proto.Message{
+		&errdetails.ErrorInfo{
+			Reason:   "RATE_LIMITED",
+			Domain:   biz.ErrorDomain,
+			Metadata: map[string]string{"procedure": procedure},
+		},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)},
+	}
+	for _, msg := range details {
+		if detail, err := connect.NewErrorDetail(msg); err == nil {
+			connectErr.AddDetail(detail)
+		}
+	}
+	return connectErr
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "connect-go-example/api/user/v1"
+	"connect-go-example/api/user/v1/userv1connect"
+	"connect-go-example/internal/biz"
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/ratelimit"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// RateLimitInterceptorTestSuite 测试限流拦截器，使用进程内限流器
+type RateLimitInterceptorTestSuite struct {
+	suite.Suite
+	interceptor *RateLimitInterceptor
+	server      *httptest.Server
+}
+
+func (suite *RateLimitInterceptorTestSuite) SetupTest() {
+	suite.interceptor = newRateLimitInterceptor(&conf.RateLimit{
+		Rules: []*conf.RateLimit_Rule{
+			{Procedure: userv1connect.UserServiceSignInProcedure, Limit: 2, Period: 60},
+			{Procedure: "/user.v1.UserService/*", Limit: 100, Key: "user"},
+		},
+		TrustForwardedFor: true,
+	}, ratelimit.NewLocalLimiter(), zap.NewNop())
+
+	mux := http.NewServeMux()
+	for _, procedure := range []string{userv1connect.UserServiceSignInProcedure, "/other.v1.OtherService/Call"} {
+		mux.Handle(procedure, connect.NewUnaryHandler(
+			procedure,
+			func(ctx context.Context, req *connect.Request[v1.SignInRequest]) (*connect.Response[v1.SignInResponse], error) {
+				return connect.NewResponse(&v1.SignInResponse{State: req.Msg.GetState()}), nil
+			},
+			connect.WithInterceptors(suite.interceptor),
+		))
+	}
+	suite.server = httptest.NewServer(mux)
+}
+
+func (suite *RateLimitInterceptorTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *RateLimitInterceptorTestSuite) call(procedure, forwardedFor string) error {
+	client := connect.NewClient[v1.SignInRequest, v1.SignInResponse](http.DefaultClient, suite.server.URL+procedure)
+	req := connect.NewRequest(&v1.SignInRequest{Code: "code", State: "state"})
+	if forwardedFor != "" {
+		req.Header().Set("X-Forwarded-For", forwardedFor)
+	}
+	_, err := client.CallUnary(context.Background(), req)
+	return err
+}
+
+func (suite *RateLimitInterceptorTestSuite) TestRejectsOverLimit() {
+	signIn := userv1connect.UserServiceSignInProcedure
+	require.NoError(suite.T(), suite.call(signIn, "10.0.0.1"))
+	require.NoError(suite.T(), suite.call(signIn, "10.0.0.1, 10.0.0.254"))
+
+	err := suite.call(signIn, "10.0.0.1")
+	require.Error(suite.T(), err)
+	assert.Equal(suite.T(), connect.CodeResourceExhausted, connect.CodeOf(err))
+
+	var connectErr *connect.Error
+	require.ErrorAs(suite.T(), err, &connectErr)
+	assert.Equal(suite.T(), "30", connectErr.Meta().Get("Retry-After"))
+
+	var info *errdetails.ErrorInfo
+	var retry *errdetails.RetryInfo
+	for _, detail := range connectErr.Details() {
+		value, err := detail.Value()
+		require.NoError(suite.T(), err)
+		switch msg := value.(type) {
+		case *errdetails.ErrorInfo:
+			info = msg
+		case *errdetails.RetryInfo:
+			retry = msg
+		}
+	}
+	require.NotNil(suite.T(), info)
+	assert.Equal(suite.T(), v1.ErrorReason_ERROR_REASON_RATE_LIMITED.String(), "ERROR_REASON_"+info.GetReason())
+	assert.Equal(suite.T(), signIn, info.GetMetadata()["procedure"])
+	require.NotNil(suite.T(), retry)
+	assert.InDelta(suite.T(), 30*time.Second, retry.GetRetryDelay().AsDuration(), float64(time.Second))
+
+	// 其他 IP 不受影响
+	assert.NoError(suite.T(), suite.call(signIn, "10.0.0.2"))
+}
+
+func (suite *RateLimitInterceptorTestSuite) TestNoRule() {
+	for range 5 {
+		require.NoError(suite.T(), suite.call("/other.v1.OtherService/Call", ""))
+	}
+}
+
+func (suite *RateLimitInterceptorTestSuite) TestRuleMatching() {
+	rule, ok := suite.interceptor.rule(userv1connect.UserServiceSignInProcedure)
+	require.True(suite.T(), ok)
+	assert.Equal(suite.T(), int64(2), rule.limit.Burst, "burst defaults to limit")
+	assert.Equal(suite.T(), rateLimitByIP, rule.key)
+
+	rule, ok = suite.interceptor.rule(userv1connect.UserServiceGetUserProcedure)
+	require.True(suite.T(), ok)
+	assert.Equal(suite.T(), time.Second, rule.limit.Period, "period defaults to one second")
+	assert.Equal(suite.T(), rateLimitByUser, rule.key)
+
+	_, ok = suite.interceptor.rule("/other.v1.OtherService/Call")
+	assert.False(suite.T(), ok)
+}
+
+func (suite *RateLimitInterceptorTestSuite) TestDefaultRules() {
+	rule, ok := suite.interceptor.rule(userv1connect.UserServiceRequestPasswordResetProcedure)
+	require.True(suite.T(), ok)
+	assert.Equal(suite.T(), int64(5), rule.limit.Rate)
+	assert.Equal(suite.T(), time.Hour, rule.limit.Period)
+	assert.Equal(suite.T(), rateLimitByIP, rule.key)
+
+	rule, ok = suite.interceptor.rule(userv1connect.UserServicePasswordSignInProcedure)
+	require.True(suite.T(), ok)
+	assert.Equal(suite.T(), int64(10), rule.limit.Rate)
+	assert.Equal(suite.T(), time.Minute, rule.limit.Period)
+
+	// 配置中同名的规则覆盖默认规则，limit 为 0 时关闭
+	interceptor := newRateLimitInterceptor(&conf.RateLimit{
+		Rules: []*conf.RateLimit_Rule{
+			{Procedure: userv1connect.UserServiceResetPasswordProcedure, Limit: 3, Period: 60},
+			{Procedure: userv1connect.UserServiceVerifyEmailProcedure, Limit: 0},
+			{Procedure: userv1connect.UserServiceSignInProcedure, Limit: 0},
+		},
+	}, ratelimit.NewLocalLimiter(), zap.NewNop())
+
+	rule, ok = interceptor.rule(userv1connect.UserServiceResetPasswordProcedure)
+	require.True(suite.T(), ok)
+	assert.Equal(suite.T(), int64(3), rule.limit.Rate)
+	_, ok = interceptor.rule(userv1connect.UserServiceVerifyEmailProcedure)
+	assert.False(suite.T(), ok)
+	_, ok = interceptor.rule(userv1connect.UserServiceSignInProcedure)
+	assert.False(suite.T(), ok)
+	_, ok = interceptor.rule(userv1connect.UserServiceRequestPasswordResetProcedure)
+	assert.True(suite.T(), ok)
+}
+
+func (suite *RateLimitInterceptorTestSuite) TestCaller() {
+	peer := connect.Peer{Addr: "192.0.2.1:54321"}
+	header := http.Header{}
+	header.Set("X-API-Key", "secret")
+
+	ctx := biz.NewPrincipalContext(context.Background(), &biz.Principal{UserID: 42})
+	kind, caller := suite.interceptor.caller(ctx, rateLimitByUser, peer, http.Header{})
+	assert.Equal(suite.T(), rateLimitByUser, kind)
+	assert.Equal(suite.T(), "42", caller)
+
+	kind, caller = suite.interceptor.caller(context.Background(), rateLimitByAPIKey, peer, header)
+	assert.Equal(suite.T(), rateLimitByAPIKey, kind)
+	assert.NotContains(suite.T(), caller, "secret")
+
+	// 缺少用户或 API key 时按 IP 限流
+	kind, caller = suite.interceptor.caller(context.Background(), rateLimitByUser, peer, http.Header{})
+	assert.Equal(suite.T(), rateLimitByIP, kind)
+	assert.Equal(suite.T(), "192.0.2.1", caller)
+
+	// 不信任代理时忽略 X-Forwarded-For
+	suite.interceptor.trustForwardedFor = false
+	header.Set("X-Forwarded-For", "10.0.0.1")
+	_, caller = suite.interceptor.caller(context.Background(), rateLimitByIP, peer, header)
+	assert.Equal(suite.T(), "192.0.2.1", caller)
+}
+
+// 运行测试套件
+func TestRateLimitInterceptorTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitInterceptorTestSuite))
+}
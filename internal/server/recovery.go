@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"connectrpc.com/connect"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// errPanic 返回给客户端的错误，不暴露 panic 的内容
+var errPanic = errors.New("internal server error")
+
+// RecoveryInterceptor 将 handler 中的 panic 转换为 CodeInternal，
+// 记录堆栈并计数，避免单个请求的 panic 中断连接
+type RecoveryInterceptor struct {
+	logger *zap.Logger
+	panics metric.Int64Counter
+}
+
+// NewRecoveryInterceptor 初始化并返回拦截器
+func NewRecoveryInterceptor(logger *zap.Logger) *RecoveryInterceptor {
+	meter := otel.GetMeterProvider().Meter("github.com/sunmery/ecommerce/backend/server")
+
+	panics, err := meter.Int64Counter(
+		"rpc.server.panics",
+		metric.WithDescription("Number of panics recovered in RPC handlers"),
+		metric.WithUnit("{panic}"),
+	)
+	if err != nil {
+		panic(fmt.Errorf("failed to init counter: %w", err))
+	}
+
+	return &RecoveryInterceptor{logger: logger, panics: panics}
+}
+
+func (r *RecoveryInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (resp connect.AnyResponse, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = r.handle(ctx, req.Spec().Procedure, p)
+			}
+		}()
+
+		return next(ctx, req)
+	}
+}
+
+func (r *RecoveryInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (r *RecoveryInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = r.handle(ctx, conn.Spec().Procedure, p)
+			}
+		}()
+
+		return next(ctx, conn)
+	}
+}
+
+// handle 记录 panic 并返回安全的错误
+func (r *RecoveryInterceptor) handle(ctx context.Context, procedure string, p any) error {
+	// http.ErrAbortHandler 用于主动中断响应，保持其原有语义
+	if err, ok := p.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+		panic(p)
+	}
+
+	fields := []zap.Field{
+		zap.String("rpc.service", procedure),
+		zap.Any("panic", p),
+		zap.ByteString("stack", debug.Stack()),
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		fields = append(fields, zap.String("trace_id", sc.TraceID().String()))
+	}
+	r.logger.Error("RPC handler panic recovered", fields...)
+
+	r.panics.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("rpc.system", "connect"),
+		attribute.String("rpc.service", procedure),
+	))
+
+	return connect.NewError(connect.CodeInternal, errPanic)
+}
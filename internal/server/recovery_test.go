@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "connect-go-example/api/user/v1"
+	"connect-go-example/api/user/v1/userv1connect"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	healthv1 "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const panicStreamProcedure = "/test.v1.TestService/Stream"
+
+// panicUserService 在 SignIn 中 panic
+type panicUserService struct {
+	userv1connect.UnimplementedUserServiceHandler
+}
+
+func (s *panicUserService) SignIn(ctx context.Context, req *connect.Request[v1.SignInRequest]) (*connect.Response[v1.SignInResponse], error) {
+	panic("database exploded: password=secret")
+}
+
+// RecoveryInterceptorTestSuite 是 RecoveryInterceptor 的测试套件
+type RecoveryInterceptorTestSuite struct {
+	suite.Suite
+	logs        *observer.ObservedLogs
+	interceptor *RecoveryInterceptor
+	server      *httptest.Server
+}
+
+func (suite *RecoveryInterceptorTestSuite) SetupTest() {
+	core, logs := observer.New(zapcore.ErrorLevel)
+	suite.logs = logs
+	suite.interceptor = NewRecoveryInterceptor(zap.New(core))
+
+	mux := http.NewServeMux()
+	mux.Handle(userv1connect.NewUserServiceHandler(&panicUserService{}, connect.WithInterceptors(suite.interceptor)))
+	mux.Handle(panicStreamProcedure, connect.NewServerStreamHandler(
+		panicStreamProcedure,
+		func(ctx context.Context, req *connect.Request[healthv1.HealthCheckRequest], stream *connect.ServerStream[healthv1.HealthCheckResponse]) error {
+			if err := stream.Send(&healthv1.HealthCheckResponse{}); err != nil {
+				return err
+			}
+			panic("stream exploded")
+		},
+		connect.WithInterceptors(suite.interceptor),
+	))
+	suite.server = httptest.NewServer(mux)
+}
+
+func (suite *RecoveryInterceptorTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *RecoveryInterceptorTestSuite) TestUnaryPanic() {
+	client := userv1connect.NewUserServiceClient(http.DefaultClient, suite.server.URL)
+
+	_, err := client.SignIn(context.Background(), connect.NewRequest(&v1.SignInRequest{}))
+
+	require.Error(suite.T(), err)
+	assert.Equal(suite.T(), connect.CodeInternal, connect.CodeOf(err))
+	// 不向客户端暴露 panic 的内容
+	assert.NotContains(suite.T(), err.Error(), "secret")
+
+	entries := suite.logs.FilterMessage("RPC handler panic recovered").All()
+	require.Len(suite.T(), entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(suite.T(), userv1connect.UserServiceSignInProcedure, fields["rpc.service"])
+	assert.Contains(suite.T(), fields["stack"], "panicUserService")
+}
+
+func (suite *RecoveryInterceptorTestSuite) TestStreamingPanic() {
+	client := connect.NewClient[healthv1.HealthCheckRequest, healthv1.HealthCheckResponse](
+		http.DefaultClient, suite.server.URL+panicStreamProcedure,
+	)
+	stream, err := client.CallServerStream(context.Background(), connect.NewRequest(&healthv1.HealthCheckRequest{}))
+	require.NoError(suite.T(), err)
+	defer stream.Close()
+
+	// 已发送的消息正常收到，之后以 CodeInternal 结束
+	assert.True(suite.T(), stream.Receive())
+	assert.False(suite.T(), stream.Receive())
+	assert.Equal(suite.T(), connect.CodeInternal, connect.CodeOf(stream.Err()))
+	assert.Equal(suite.T(), 1, suite.logs.FilterMessage("RPC handler panic recovered").Len())
+}
+
+func (suite *RecoveryInterceptorTestSuite) TestLogsTraceID() {
+	traceID := trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+	}))
+
+	err := suite.interceptor.handle(ctx, "/user.v1.UserService/SignIn", "boom")
+
+	assert.Equal(suite.T(), connect.CodeInternal, connect.CodeOf(err))
+	entries := suite.logs.All()
+	require.Len(suite.T(), entries, 1)
+	assert.Equal(suite.T(), traceID.String(), entries[0].ContextMap()["trace_id"])
+}
+
+func (suite *RecoveryInterceptorTestSuite) TestAbortHandlerIsRepanicked() {
+	assert.PanicsWithValue(suite.T(), http.ErrAbortHandler, func() {
+		_ = suite.interceptor.handle(context.Background(), "/user.v1.UserService/SignIn", http.ErrAbortHandler)
+	})
+}
+
+// 运行测试套件
+func TestRecoveryInterceptorTestSuite(t *testing.T) {
+	suite.Run(t, new(RecoveryInterceptorTestSuite))
+}
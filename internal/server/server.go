@@ -2,17 +2,16 @@ package server
 
 import (
 	"connect-go-example/api/user/v1/userv1connect"
-	"context"
 	"net/http"
 	"time"
 
 	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/health"
 
 	"connectrpc.com/connect"
 	connectcors "connectrpc.com/cors"
 	"github.com/rs/cors"
 	"go.uber.org/fx"
-	"go.uber.org/zap"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 )
@@ -20,15 +19,16 @@ import (
 var Module = fx.Module("server",
 	fx.Provide(
 		NewHTTPServer,
+		NewHealthChecker,
+		NewDrainer,
 	),
+	fx.Invoke(RunHTTPServer),
 )
 
 func NewHTTPServer(
-	lc fx.Lifecycle,
 	cfg *conf.Bootstrap,
 	userv1Service userv1connect.UserServiceHandler,
-
-	logger *zap.Logger,
+	checker *health.Checker,
 	connectOptions []connect.HandlerOption,
 ) *http.Server {
 	// 将拦截器传递给 Service Handler
@@ -40,6 +40,11 @@ func NewHTTPServer(
 	mux := http.NewServeMux()
 	mux.Handle(userv1connectPath, userv1connectHandler)
 
+	// 健康检查不经过认证等拦截器，供负载均衡和 Kubernetes 探针调用
+	mux.Handle(health.NewHandler(checker))
+	mux.Handle("GET /healthz", health.LivenessHandler())
+	mux.Handle("GET /readyz", health.ReadinessHandler(checker))
+
 	// 创建处理器链：监控中间件 -> CORS -> HTTP/2
 	handlerChain := withCORS(mux)
 
@@ -56,17 +61,7 @@ func NewHTTPServer(
 		Protocols:    p,
 	}
 
-	// 注册生命周期钩子
-	lc.Append(fx.Hook{
-		OnStart: func(ctx context.Context) error {
-			logger.Info("HTTP server starting", zap.String("addr", cfg.Server.Http.Addr))
-			return nil
-		},
-		OnStop: func(ctx context.Context) error {
-			logger.Info("HTTP server shutting down...")
-			return server.Shutdown(ctx)
-		},
-	})
+	// 启动和关闭由 RunHTTPServer 负责
 
 	return server
 }
@@ -76,7 +71,7 @@ func withCORS(h http.Handler) http.Handler {
 	middleware := cors.New(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:3000"},
 		AllowedMethods:   connectcors.AllowedMethods(),
-		AllowedHeaders:   connectcors.AllowedHeaders(),
+		AllowedHeaders:   append(connectcors.AllowedHeaders(), "Authorization"),
 		ExposedHeaders:   connectcors.ExposedHeaders(),
 		AllowCredentials: true,
 	})
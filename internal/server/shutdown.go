@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/health"
+	"connect-go-example/internal/pkg/meta"
+	"connect-go-example/internal/pkg/registry"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// 优雅关闭的默认配置
+const (
+	defaultPropagationDelay = 5 * time.Second
+	defaultDrainTimeout     = 15 * time.Second
+)
+
+// Drainer 协调关闭顺序，保证在停止服务前先把流量从本实例摘除：
+//  1. 健康检查全部标记为 NOT_SERVING，/readyz 返回 503
+//  2. 从注册中心注销，Consul 的消费方不再选中本实例
+//  3. 等待传播延迟，让 Consul 阻塞查询、Kubernetes Endpoints 和负载均衡感知变化
+//  4. 停止接受新请求，等待进行中的请求完成，超过期限后强制关闭连接
+//
+// 数据客户端和 OTel 在之后由各自模块的 OnStop 钩子关闭（fx 按注册的逆序执行 OnStop）
+type Drainer struct {
+	server   *http.Server
+	checker  *health.Checker
+	registry registry.Registry
+	l        *zap.Logger
+
+	propagationDelay time.Duration
+	drainTimeout     time.Duration
+}
+
+func NewDrainer(cfg *conf.Bootstrap, server *http.Server, checker *health.Checker, reg registry.Registry, logger *zap.Logger) *Drainer {
+	d := &Drainer{
+		server:           server,
+		checker:          checker,
+		registry:         reg,
+		l:                logger,
+		propagationDelay: defaultPropagationDelay,
+		drainTimeout:     defaultDrainTimeout,
+	}
+
+	shutdownCfg := cfg.GetServer().GetShutdown()
+	if v := shutdownCfg.GetPropagationDelay(); v > 0 {
+		d.propagationDelay = time.Duration(v) * time.Second
+	}
+	if v := shutdownCfg.GetDrainTimeout(); v > 0 {
+		d.drainTimeout = time.Duration(v) * time.Second
+	}
+	return d
+}
+
+// Drain 执行关闭流程，ctx 结束时跳过剩余的等待并立即关闭连接
+func (d *Drainer) Drain(ctx context.Context) error {
+	start := time.Now()
+	d.l.Info("Draining before shutdown",
+		zap.Duration("propagation_delay", d.propagationDelay),
+		zap.Duration("drain_timeout", d.drainTimeout),
+	)
+
+	d.checker.Shutdown()
+	if err := d.registry.Deregister(ctx); err != nil {
+		// 注销失败时 Consul 仍会通过健康检查（/readyz 或 TTL）发现实例不可用
+		d.l.Warn("Failed to deregister service", zap.Error(err))
+	}
+
+	select {
+	case <-time.After(d.propagationDelay):
+	case <-ctx.Done():
+		d.l.Warn("Shutdown deadline reached during propagation delay", zap.Error(ctx.Err()))
+	}
+
+	d.l.Info("HTTP server shutting down...")
+	drainCtx, cancel := context.WithTimeout(ctx, d.drainTimeout)
+	defer cancel()
+	if err := d.server.Shutdown(drainCtx); err != nil {
+		// 仍未结束的请求（例如 Watch 等长连接）直接断开
+		d.l.Warn("In-flight requests did not finish before deadline, closing connections", zap.Error(err))
+		if err := d.server.Close(); err != nil {
+			return err
+		}
+	}
+
+	d.l.Info("HTTP server drained", zap.Duration("elapsed", time.Since(start)))
+	return nil
+}
+
+// RunHTTPServer 随应用启动 HTTP 服务，停止时执行 Drain
+func RunHTTPServer(lc fx.Lifecycle, server *http.Server, drainer *Drainer, appInfo meta.AppInfo, logger *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			// 同步监听，端口被占用时启动直接失败
+			ln, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return err
+			}
+			logger.Info("Starting HTTP server",
+				zap.String("addr", ln.Addr().String()),
+				zap.String("version", appInfo.Version),
+				zap.String("environment", appInfo.Environment),
+			)
+			go func() {
+				if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Fatal("Failed to start HTTP server", zap.Error(err))
+				}
+			}()
+			return nil
+		},
+		OnStop: drainer.Drain,
+	})
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	conf "connect-go-example/internal/conf/v1"
+	"connect-go-example/internal/pkg/health"
+	"connect-go-example/internal/pkg/registry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+// recordingRegistry 记录 Deregister 的调用
+type recordingRegistry struct {
+	registry.Registry
+	deregistered atomic.Bool
+}
+
+func (r *recordingRegistry) Deregister(ctx context.Context) error {
+	r.deregistered.Store(true)
+	return nil
+}
+
+// DrainerTestSuite 是优雅关闭流程的测试套件
+type DrainerTestSuite struct {
+	suite.Suite
+	checker  *health.Checker
+	registry *recordingRegistry
+	server   *http.Server
+	addr     string
+	entered  chan struct{}
+	release  chan struct{}
+	drainer  *Drainer
+}
+
+func (suite *DrainerTestSuite) SetupTest() {
+	suite.checker = health.NewChecker(nil, nil, time.Hour, time.Second, zap.NewNop())
+	suite.checker.CheckNow(context.Background())
+	suite.registry = &recordingRegistry{Registry: registry.NewNoopRegistry(zap.NewNop())}
+	entered, release := make(chan struct{}, 1), make(chan struct{})
+	suite.entered, suite.release = entered, release
+
+	// 处理器阻塞直到 release 关闭，模拟进行中的请求
+	// 后台 goroutine 只引用局部变量，不会与下一个测试对 suite 字段的赋值产生竞争
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(suite.T(), err)
+	go func() { _ = srv.Serve(ln) }()
+	suite.server = srv
+	suite.addr = ln.Addr().String()
+
+	drainer := &Drainer{
+		server:           srv,
+		checker:          suite.checker,
+		registry:         suite.registry,
+		l:                zap.NewNop(),
+		propagationDelay: 50 * time.Millisecond,
+		drainTimeout:     5 * time.Second,
+	}
+	suite.drainer = drainer
+}
+
+func (suite *DrainerTestSuite) TearDownTest() {
+	select {
+	case <-suite.release:
+	default:
+		close(suite.release)
+	}
+	_ = suite.server.Close()
+}
+
+// inflight 发起一个阻塞的请求，返回其结果
+func (suite *DrainerTestSuite) inflight() <-chan error {
+	result := make(chan error, 1)
+	addr := suite.addr
+	go func() {
+		resp, err := http.Get("http://" + addr)
+		if err == nil {
+			resp.Body.Close()
+		}
+		result <- err
+	}()
+	<-suite.entered
+	return result
+}
+
+func (suite *DrainerTestSuite) TestDrainWaitsForInflightRequests() {
+	result := suite.inflight()
+
+	drained := make(chan error, 1)
+	drainer := suite.drainer
+	go func() { drained <- drainer.Drain(context.Background()) }()
+
+	// 先摘除流量：健康检查和注册中心立即感知
+	assert.Eventually(suite.T(), suite.registry.deregistered.Load, time.Second, 5*time.Millisecond)
+	status, _ := suite.checker.Result("")
+	assert.Equal(suite.T(), health.StatusNotServing, status.Status)
+
+	// 进行中的请求完成前不会结束
+	select {
+	case <-drained:
+		suite.T().Fatal("Drain returned before in-flight request finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(suite.release)
+	require.NoError(suite.T(), <-result)
+	require.NoError(suite.T(), <-drained)
+
+	// 关闭后不再接受新连接
+	_, err := http.Get("http://" + suite.addr)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *DrainerTestSuite) TestDrainClosesAfterDeadline() {
+	suite.drainer.drainTimeout = 50 * time.Millisecond
+	result := suite.inflight()
+
+	start := time.Now()
+	require.NoError(suite.T(), suite.drainer.Drain(context.Background()))
+
+	// 超过期限后强制断开仍未结束的请求
+	assert.Error(suite.T(), <-result)
+	assert.Less(suite.T(), time.Since(start), 2*time.Second)
+}
+
+func (suite *DrainerTestSuite) TestDrainSkipsDelayWhenContextDone() {
+	suite.drainer.propagationDelay = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan error, 1)
+	drainer := suite.drainer
+	go func() { done <- drainer.Drain(ctx) }()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		suite.T().Fatal("Drain did not honor context deadline")
+	}
+	assert.True(suite.T(), suite.registry.deregistered.Load())
+}
+
+// 运行测试套件
+func TestDrainerTestSuite(t *testing.T) {
+	suite.Run(t, new(DrainerTestSuite))
+}
+
+func TestNewDrainer_Config(t *testing.T) {
+	tests := []struct {
+		name             string
+		cfg              *conf.Server_Shutdown
+		propagationDelay time.Duration
+		drainTimeout     time.Duration
+	}{
+		{"defaults", nil, defaultPropagationDelay, defaultDrainTimeout},
+		{"seconds", &conf.Server_Shutdown{PropagationDelay: 2, DrainTimeout: 30}, 2 * time.Second, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &conf.Bootstrap{Server: &conf.Server{Shutdown: tt.cfg}}
+			d := NewDrainer(cfg, &http.Server{}, nil, nil, zap.NewNop())
+			assert.Equal(t, tt.propagationDelay, d.propagationDelay)
+			assert.Equal(t, tt.drainTimeout, d.drainTimeout)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// streamStats 统计一个流的消息数和首条消息延迟，供日志和指标拦截器使用
+type streamStats struct {
+	start    time.Time
+	sent     atomic.Int64
+	received atomic.Int64
+	// firstMessage 首条消息距开始的纳秒数，0 表示还没有消息
+	firstMessage atomic.Int64
+}
+
+func newStreamStats() *streamStats {
+	return &streamStats{start: time.Now()}
+}
+
+func (s *streamStats) markFirstMessage() {
+	s.firstMessage.CompareAndSwap(0, max(int64(time.Since(s.start)), 1))
+}
+
+// firstMessageLatency 服务端为第一次 Send 的延迟，客户端为第一次收到响应的延迟
+func (s *streamStats) firstMessageLatency() (time.Duration, bool) {
+	v := s.firstMessage.Load()
+	return time.Duration(v), v > 0
+}
+
+// statsHandlerConn 统计服务端流的收发
+type statsHandlerConn struct {
+	connect.StreamingHandlerConn
+	stats *streamStats
+}
+
+func (c *statsHandlerConn) Send(msg any) error {
+	err := c.StreamingHandlerConn.Send(msg)
+	if err == nil {
+		c.stats.sent.Add(1)
+		c.stats.markFirstMessage()
+	}
+	return err
+}
+
+func (c *statsHandlerConn) Receive(msg any) error {
+	err := c.StreamingHandlerConn.Receive(msg)
+	if err == nil {
+		c.stats.received.Add(1)
+	}
+	return err
+}
+
+// statsClientConn 统计客户端流的收发，并在 CloseResponse 时以流的最终错误调用 done
+type statsClientConn struct {
+	connect.StreamingClientConn
+	stats *streamStats
+	done  func(err error)
+
+	mu   sync.Mutex
+	err  error
+	once sync.Once
+}
+
+func (c *statsClientConn) Send(msg any) error {
+	err := c.StreamingClientConn.Send(msg)
+	if err == nil {
+		c.stats.sent.Add(1)
+	}
+	return err
+}
+
+func (c *statsClientConn) Receive(msg any) error {
+	err := c.StreamingClientConn.Receive(msg)
+	switch {
+	case err == nil:
+		c.stats.received.Add(1)
+		c.stats.markFirstMessage()
+	case !errors.Is(err, io.EOF):
+		c.mu.Lock()
+		c.err = err
+		c.mu.Unlock()
+	}
+	return err
+}
+
+func (c *statsClientConn) CloseResponse() error {
+	err := c.StreamingClientConn.CloseResponse()
+	c.once.Do(func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.done(c.err)
+	})
+	return err
+}
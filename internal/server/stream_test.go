@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	healthv1 "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const watchProcedure = "/test.v1.TestService/Watch"
+
+// StreamInterceptorTestSuite 测试日志和指标拦截器对流式 RPC 的统计
+type StreamInterceptorTestSuite struct {
+	suite.Suite
+	reader   *sdkmetric.ManualReader
+	provider *sdkmetric.MeterProvider
+	logs     *observer.ObservedLogs
+	server   *httptest.Server
+	client   *connect.Client[healthv1.HealthCheckRequest, healthv1.HealthCheckResponse]
+}
+
+func (suite *StreamInterceptorTestSuite) SetupTest() {
+	suite.reader = sdkmetric.NewManualReader()
+	suite.provider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(suite.reader))
+	otel.SetMeterProvider(suite.provider)
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	suite.logs = logs
+	interceptors := connect.WithInterceptors(NewMetricsInterceptor(), NewLoggingInterceptor(zap.New(core)))
+
+	// 服务名为 missing 时返回 NotFound，否则发送三条消息
+	suite.server = httptest.NewServer(connect.NewServerStreamHandler(
+		watchProcedure,
+		func(ctx context.Context, req *connect.Request[healthv1.HealthCheckRequest], stream *connect.ServerStream[healthv1.HealthCheckResponse]) error {
+			if req.Msg.GetService() == "missing" {
+				return connect.NewError(connect.CodeNotFound, errors.New("unknown service"))
+			}
+			for range 3 {
+				if err := stream.Send(&healthv1.HealthCheckResponse{Status: healthv1.HealthCheckResponse_SERVING}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		interceptors,
+	))
+	suite.client = connect.NewClient[healthv1.HealthCheckRequest, healthv1.HealthCheckResponse](
+		http.DefaultClient, suite.server.URL+watchProcedure, interceptors,
+	)
+}
+
+func (suite *StreamInterceptorTestSuite) TearDownTest() {
+	suite.server.Close()
+	_ = suite.provider.Shutdown(context.Background())
+}
+
+// watch 读完整个流并关闭
+func (suite *StreamInterceptorTestSuite) watch(service string) (int, error) {
+	stream, err := suite.client.CallServerStream(context.Background(), connect.NewRequest(&healthv1.HealthCheckRequest{Service: service}))
+	require.NoError(suite.T(), err)
+
+	n := 0
+	for stream.Receive() {
+		n++
+	}
+	err = stream.Err()
+	require.NoError(suite.T(), stream.Close())
+	return n, err
+}
+
+// collect 返回指定指标的所有数据点（按属性集合区分）
+func (suite *StreamInterceptorTestSuite) collect(name string) metricdata.Aggregation {
+	var rm metricdata.ResourceMetrics
+	require.NoError(suite.T(), suite.reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	suite.T().Fatalf("metric %s not recorded", name)
+	return nil
+}
+
+func (suite *StreamInterceptorTestSuite) TestLogsStream() {
+	n, err := suite.watch("")
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 3, n)
+
+	entries := suite.logs.FilterMessage("RPC success").All()
+	require.Len(suite.T(), entries, 2)
+
+	// 服务端在 handler 返回时记录，客户端在关闭响应时记录
+	byDirection := map[int64]map[string]any{}
+	for _, entry := range entries {
+		fields := entry.ContextMap()
+		assert.Equal(suite.T(), watchProcedure, fields["rpc.service"])
+		assert.Contains(suite.T(), fields, "first_message")
+		byDirection[fields["messages_sent"].(int64)] = fields
+	}
+	assert.Equal(suite.T(), int64(1), byDirection[3]["messages_received"], "server")
+	assert.Equal(suite.T(), int64(3), byDirection[1]["messages_received"], "client")
+}
+
+func (suite *StreamInterceptorTestSuite) TestLogsStreamError() {
+	_, err := suite.watch("missing")
+	assert.Equal(suite.T(), connect.CodeNotFound, connect.CodeOf(err))
+
+	entries := suite.logs.FilterMessage("RPC business error").All()
+	require.Len(suite.T(), entries, 2)
+	for _, entry := range entries {
+		assert.Equal(suite.T(), connect.CodeNotFound.String(), entry.ContextMap()["rpc.code"])
+		assert.NotContains(suite.T(), entry.ContextMap(), "first_message")
+	}
+}
+
+func (suite *StreamInterceptorTestSuite) TestRecordsStreamMetrics() {
+	_, err := suite.watch("")
+	require.NoError(suite.T(), err)
+
+	// 与一元 RPC 使用相同的指标名称和属性
+	requests := suite.collect("rpc.server.requests_total").(metricdata.Sum[int64])
+	require.Len(suite.T(), requests.DataPoints, 1)
+	point := requests.DataPoints[0]
+	assert.Equal(suite.T(), int64(1), point.Value)
+	service, _ := point.Attributes.Value(attribute.Key("rpc.service"))
+	assert.Equal(suite.T(), watchProcedure, service.AsString())
+	method, _ := point.Attributes.Value(attribute.Key("rpc.method"))
+	assert.Equal(suite.T(), http.MethodPost, method.AsString())
+
+	sent := suite.collect("rpc.server.stream.messages_sent").(metricdata.Histogram[int64])
+	require.Len(suite.T(), sent.DataPoints, 1)
+	assert.Equal(suite.T(), int64(3), sent.DataPoints[0].Sum)
+
+	received := suite.collect("rpc.client.stream.messages_received").(metricdata.Histogram[int64])
+	require.Len(suite.T(), received.DataPoints, 1)
+	assert.Equal(suite.T(), int64(3), received.DataPoints[0].Sum)
+
+	firstMessage := suite.collect("rpc.server.stream.first_message_ms").(metricdata.Histogram[float64])
+	require.Len(suite.T(), firstMessage.DataPoints, 1)
+	assert.Equal(suite.T(), uint64(1), firstMessage.DataPoints[0].Count)
+
+	suite.collect("rpc.server.duration_ms")
+	suite.collect("rpc.client.duration_ms")
+}
+
+// 运行测试套件
+func TestStreamInterceptorTestSuite(t *testing.T) {
+	suite.Run(t, new(StreamInterceptorTestSuite))
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	v1 "connect-go-example/api/user/v1"
+	"connect-go-example/internal/biz"
+
+	"buf.build/go/protovalidate"
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ValidationInterceptor 按 proto 中的 buf.validate 注解校验请求消息，
+// 不合法的请求直接返回 CodeInvalidArgument，不会进入 handler
+type ValidationInterceptor struct {
+	validator protovalidate.Validator
+}
+
+// NewValidationInterceptor 初始化并返回拦截器，启动时预先编译所有服务请求消息的规则，
+// 规则有误时返回错误，由 fx 终止启动
+func NewValidationInterceptor() (*ValidationInterceptor, error) {
+	var messages []protoreflect.MessageDescriptor
+	services := v1.File_api_user_v1_user_proto.Services()
+	for i := 0; i < services.Len(); i++ {
+		methods := services.Get(i).Methods()
+		for j := 0; j < methods.Len(); j++ {
+			messages = append(messages, methods.Get(j).Input())
+		}
+	}
+
+	validator, err := protovalidate.New(protovalidate.WithMessageDescriptors(messages...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile validation rules: %w", err)
+	}
+	return &ValidationInterceptor{validator: validator}, nil
+}
+
+func (v *ValidationInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		// 客户端不校验，由服务端统一返回错误
+		if !req.Spec().IsClient {
+			if err := v.validate(req.Any()); err != nil {
+				return nil, err
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+func (v *ValidationInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (v *ValidationInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return next(ctx, &validatingHandlerConn{StreamingHandlerConn: conn, validate: v.validate})
+	}
+}
+
+// validate 校验请求消息，失败时返回带 BadRequest 详情的 Connect 错误
+func (v *ValidationInterceptor) validate(msg any) error {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	err := v.validator.Validate(m)
+	if err == nil {
+		return nil
+	}
+	var validationErr *protovalidate.ValidationError
+	if !errors.As(err, &validationErr) {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+
+	connectErr := connect.NewError(connect.CodeInvalidArgument, err)
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(validationErr.Violations))
+	for _, violation := range validationErr.Violations {
+		v := violation.Proto
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       protovalidate.FieldPathString(v.GetField()),
+			Description: v.GetMessage(),
+			Reason:      v.GetRuleId(),
+		})
+	}
+	details := []proto.Message{
+		&errdetails.ErrorInfo{
+			Reason: "INVALID_ARGUMENT",
+			Domain: biz.ErrorDomain,
+		},
+		&errdetails.BadRequest{FieldViolations: violations},
+	}
+	for _, msg := range details {
+		if detail, err := connect.NewErrorDetail(msg); err == nil {
+			connectErr.AddDetail(detail)
+		}
+	}
+	return connectErr
+}
+
+// validatingHandlerConn 校验流式 RPC 中客户端发送的每一条消息
+type validatingHandlerConn struct {
+	connect.StreamingHandlerConn
+	validate func(msg any) error
+}
+
+func (c *validatingHandlerConn) Receive(msg any) error {
+	if err := c.StreamingHandlerConn.Receive(msg); err != nil {
+		return err
+	}
+	return c.validate(msg)
+}
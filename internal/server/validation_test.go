@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "connect-go-example/api/user/v1"
+	"connect-go-example/api/user/v1/userv1connect"
+	"connect-go-example/internal/biz"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// ValidationInterceptorTestSuite 测试请求校验拦截器
+type ValidationInterceptorTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+	client *connect.Client[v1.RegisterRequest, v1.RegisterResponse]
+	calls  int
+}
+
+func (suite *ValidationInterceptorTestSuite) SetupTest() {
+	suite.calls = 0
+	interceptor, err := NewValidationInterceptor()
+	require.NoError(suite.T(), err)
+	suite.server = httptest.NewServer(connect.NewUnaryHandler(
+		userv1connect.UserServiceRegisterProcedure,
+		func(ctx context.Context, req *connect.Request[v1.RegisterRequest]) (*connect.Response[v1.RegisterResponse], error) {
+			suite.calls++
+			return connect.NewResponse(&v1.RegisterResponse{Id: 1, Username: req.Msg.GetUsername()}), nil
+		},
+		connect.WithInterceptors(interceptor),
+	))
+	suite.client = connect.NewClient[v1.RegisterRequest, v1.RegisterResponse](
+		http.DefaultClient, suite.server.URL+userv1connect.UserServiceRegisterProcedure,
+	)
+}
+
+func (suite *ValidationInterceptorTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *ValidationInterceptorTestSuite) TestValidRequest() {
+	resp, err := suite.client.CallUnary(context.Background(), connect.NewRequest(&v1.RegisterRequest{
+		Username: "alice",
+		Password: "password123",
+	}))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "alice", resp.Msg.GetUsername())
+	assert.Equal(suite.T(), 1, suite.calls)
+}
+
+func (suite *ValidationInterceptorTestSuite) TestInvalidRequest() {
+	_, err := suite.client.CallUnary(context.Background(), connect.NewRequest(&v1.RegisterRequest{
+		Username: "alice",
+		Password: "short",
+	}))
+	require.Error(suite.T(), err)
+	assert.Equal(suite.T(), connect.CodeInvalidArgument, connect.CodeOf(err))
+	// 不合法的请求不会进入 handler
+	assert.Equal(suite.T(), 0, suite.calls)
+
+	var connectErr *connect.Error
+	require.ErrorAs(suite.T(), err, &connectErr)
+
+	var info *errdetails.ErrorInfo
+	var badRequest *errdetails.BadRequest
+	for _, detail := range connectErr.Details() {
+		value, err := detail.Value()
+		require.NoError(suite.T(), err)
+		switch msg := value.(type) {
+		case *errdetails.ErrorInfo:
+			info = msg
+		case *errdetails.BadRequest:
+			badRequest = msg
+		}
+	}
+
+	require.NotNil(suite.T(), info)
+	assert.Equal(suite.T(), "INVALID_ARGUMENT", info.GetReason())
+	assert.Equal(suite.T(), biz.ErrorDomain, info.GetDomain())
+
+	require.NotNil(suite.T(), badRequest)
+	require.Len(suite.T(), badRequest.GetFieldViolations(), 1)
+	violation := badRequest.GetFieldViolations()[0]
+	assert.Equal(suite.T(), "password", violation.GetField())
+	assert.Equal(suite.T(), "string.min_len", violation.GetReason())
+	assert.Equal(suite.T(), "value length must be at least 8 characters", violation.GetDescription())
+}
+
+// 运行测试套件
+func TestValidationInterceptorTestSuite(t *testing.T) {
+	suite.Run(t, new(ValidationInterceptorTestSuite))
+}
+
+func TestValidationInterceptor_Rules(t *testing.T) {
+	interceptor, err := NewValidationInterceptor()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		msg       proto.Message
+		wantField string
+		wantRule  string
+	}{
+		{"valid update", &v1.UpdateUserRequest{User: &v1.User{Id: 1, Nickname: "alice"}, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"nickname"}}}, "", ""},
+		{"update without id", &v1.UpdateUserRequest{User: &v1.User{Nickname: "alice"}, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"nickname"}}}, "user.id", "int64.gt"},
+		{"empty update mask", &v1.UpdateUserRequest{User: &v1.User{Id: 1}, UpdateMask: &fieldmaskpb.FieldMask{}}, "update_mask", "update_mask.paths"},
+		{"immutable field in mask", &v1.UpdateUserRequest{User: &v1.User{Id: 1}, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"username"}}}, "update_mask", "update_mask.paths"},
+		{"mfa without credential", &v1.VerifyMFARequest{ChallengeToken: "token"}, "credential", "required"},
+		{"negative page size", &v1.ListUsersRequest{PageSize: -1}, "page_size", "int32.gte"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := interceptor.validate(tt.msg)
+			if tt.wantField == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+
+			var connectErr *connect.Error
+			require.ErrorAs(t, err, &connectErr)
+			var badRequest *errdetails.BadRequest
+			for _, detail := range connectErr.Details() {
+				if value, err := detail.Value(); err == nil {
+					if msg, ok := value.(*errdetails.BadRequest); ok {
+						badRequest = msg
+					}
+				}
+			}
+			require.NotNil(t, badRequest)
+			require.Len(t, badRequest.GetFieldViolations(), 1)
+			assert.Equal(t, tt.wantField, badRequest.GetFieldViolations()[0].GetField())
+			assert.Equal(t, tt.wantRule, badRequest.GetFieldViolations()[0].GetReason())
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"connect-go-example/internal/biz"
+
+	"connectrpc.com/connect"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+)
+
+// errorLocale LocalizedMessage 的语言，当前业务错误的说明均为英文
+const errorLocale = "en-US"
+
+// errInternal 返回给客户端的内部错误，具体原因只记录在服务端日志中
+var errInternal = errors.New("internal error")
+
+// kindCodes 业务错误类别到 Connect 错误码的映射
+var kindCodes = map[biz.Kind]connect.Code{
+	biz.KindInvalidArgument:    connect.CodeInvalidArgument,
+	biz.KindNotFound:           connect.CodeNotFound,
+	biz.KindAlreadyExists:      connect.CodeAlreadyExists,
+	biz.KindUnauthenticated:    connect.CodeUnauthenticated,
+	biz.KindPermissionDenied:   connect.CodePermissionDenied,
+	biz.KindFailedPrecondition: connect.CodeFailedPrecondition,
+	biz.KindResourceExhausted:  connect.CodeResourceExhausted,
+	biz.KindUnavailable:        connect.CodeUnavailable,
+}
+
+// toConnectError 将 handler 返回的错误转换为 Connect 错误：
+// 业务错误转换为带 errdetails 的错误，Connect 错误和 context 的取消、超时保留原有语义；
+// 其他错误（数据库、Redis 等）只在服务端记录日志，对客户端统一返回 CodeInternal，避免泄露内部细节
+func (s *UserService) toConnectError(err error) error {
+	var bizErr *biz.Error
+	if errors.As(err, &bizErr) {
+		return newBizError(err, bizErr)
+	}
+
+	var connectErr *connect.Error
+	switch {
+	case errors.As(err, &connectErr):
+		return err
+	case errors.Is(err, context.Canceled):
+		return connect.NewError(connect.CodeCanceled, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return connect.NewError(connect.CodeDeadlineExceeded, err)
+	}
+
+	s.l.Error("Internal error", zap.Error(err))
+	return connect.NewError(connect.CodeInternal, errInternal)
+}
+
+// newBizError 业务错误的 ErrorInfo 携带原因和元数据，LocalizedMessage 携带可展示的说明，字段错误额外携带 BadRequest
+func newBizError(err error, bizErr *biz.Error) *connect.Error {
+	code, ok := kindCodes[bizErr.Kind]
+	if !ok {
+		code = connect.CodeInternal
+	}
+	connectErr := connect.NewError(code, err)
+
+	addDetail(connectErr, &errdetails.ErrorInfo{
+		Reason:   bizErr.Reason,
+		Domain:   biz.ErrorDomain,
+		Metadata: bizErr.Metadata,
+	})
+	if bizErr.Field != nil {
+		addDetail(connectErr, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+				Field:       bizErr.Field.Field,
+				Description: bizErr.Field.Description,
+			}},
+		})
+	}
+	addDetail(connectErr, &errdetails.LocalizedMessage{
+		Locale:  errorLocale,
+		Message: bizErr.Message,
+	})
+	return connectErr
+}
+
+// invalidArgument 请求字段校验失败
+func invalidArgument(field, description string) error {
+	bizErr := biz.InvalidArgument(field, description)
+	return newBizError(bizErr, bizErr)
+}
+
+func addDetail(err *connect.Error, msg proto.Message) {
+	// 只有在消息无法序列化时才会失败，errdetails 的消息不会出现这种情况
+	if detail, detailErr := connect.NewErrorDetail(msg); detailErr == nil {
+		err.AddDetail(detail)
+	}
+}
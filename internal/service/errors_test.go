@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	v1 "connect-go-example/api/user/v1"
+	"connect-go-example/internal/biz"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// details 解码 Connect 错误中的 errdetails
+func details(t *testing.T, err error) (*errdetails.ErrorInfo, *errdetails.BadRequest, *errdetails.LocalizedMessage) {
+	var connectErr *connect.Error
+	require.True(t, errors.As(err, &connectErr))
+
+	var (
+		info      *errdetails.ErrorInfo
+		badReq    *errdetails.BadRequest
+		localized *errdetails.LocalizedMessage
+	)
+	for _, detail := range connectErr.Details() {
+		msg, err := detail.Value()
+		require.NoError(t, err)
+		switch m := msg.(type) {
+		case *errdetails.ErrorInfo:
+			info = m
+		case *errdetails.BadRequest:
+			badReq = m
+		case *errdetails.LocalizedMessage:
+			localized = m
+		}
+	}
+	return info, badReq, localized
+}
+
+// newTestService 只用于测试错误转换，不需要用例
+func newTestService() *UserService {
+	return &UserService{l: zap.NewNop()}
+}
+
+func TestToConnectError(t *testing.T) {
+	err := newTestService().toConnectError(fmt.Errorf("create user: %w", biz.ErrUserAlreadyExists.WithMetadata("username", "alice")))
+
+	assert.Equal(t, connect.CodeAlreadyExists, connect.CodeOf(err))
+	info, badReq, localized := details(t, err)
+	require.NotNil(t, info)
+	assert.Equal(t, biz.ReasonUserAlreadyExists, info.Reason)
+	assert.Equal(t, biz.ErrorDomain, info.Domain)
+	assert.Equal(t, map[string]string{"username": "alice"}, info.Metadata)
+	assert.Nil(t, badReq)
+	require.NotNil(t, localized)
+	assert.Equal(t, "user Already Exists", localized.Message)
+}
+
+func TestToConnectError_FieldViolation(t *testing.T) {
+	err := newTestService().toConnectError(biz.ErrInvalidPageToken)
+
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	_, badReq, _ := details(t, err)
+	require.NotNil(t, badReq)
+	require.Len(t, badReq.FieldViolations, 1)
+	assert.Equal(t, "page_token", badReq.FieldViolations[0].Field)
+}
+
+func TestToConnectError_OtherErrors(t *testing.T) {
+	unavailable := connect.NewError(connect.CodeUnavailable, errors.New("search index is not ready"))
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    connect.Code
+		wantMessage string
+	}{
+		{"internal", fmt.Errorf("get user failed: %w", errors.New("dial tcp 10.0.0.5:5432: connection refused")), connect.CodeInternal, "internal error"},
+		{"connect error", unavailable, connect.CodeUnavailable, "search index is not ready"},
+		{"canceled", fmt.Errorf("list users failed: %w", context.Canceled), connect.CodeCanceled, "list users failed: context canceled"},
+		{"deadline exceeded", context.DeadlineExceeded, connect.CodeDeadlineExceeded, "context deadline exceeded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTestService().toConnectError(tt.err)
+
+			var connectErr *connect.Error
+			require.True(t, errors.As(err, &connectErr))
+			assert.Equal(t, tt.wantCode, connectErr.Code())
+			assert.Equal(t, tt.wantMessage, connectErr.Message())
+		})
+	}
+}
+
+// TestToConnectError_LogsInternalErrors 内部错误的原因只出现在服务端日志中
+func TestToConnectError_LogsInternalErrors(t *testing.T) {
+	core, logs := observer.New(zap.ErrorLevel)
+	s := &UserService{l: zap.New(core)}
+
+	err := s.toConnectError(errors.New("dial tcp 10.0.0.5:5432: connection refused"))
+
+	assert.NotContains(t, err.Error(), "10.0.0.5")
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "dial tcp 10.0.0.5:5432: connection refused", logs.All()[0].ContextMap()["error"])
+}
+
+// TestReasonsMatchProto 业务错误的原因必须是 user.v1.ErrorReason 中定义的值，TS 客户端据此解析
+func TestReasonsMatchProto(t *testing.T) {
+	for _, err := range []*biz.Error{
+		biz.ErrUserAlreadyExists,
+		biz.ErrUserNotFound,
+		biz.ErrInvalidCredentials,
+		biz.ErrInvalidPageToken,
+		biz.ErrPermissionDenied,
+		biz.ErrSessionNotFound,
+		biz.ErrInvalidRefreshToken,
+		biz.ErrRefreshTokenReused,
+		biz.ErrEmptySearchQuery,
+		biz.ErrSearchUnavailable,
+		biz.ErrAccountLocked,
+		biz.ErrMFAUnavailable,
+		biz.ErrMFANotEnrolled,
+		biz.ErrMFAAlreadyEnabled,
+		biz.ErrInvalidMFACode,
+		biz.ErrInvalidMFAChallenge,
+		biz.ErrInvalidToken,
+		biz.ErrEmailNotSet,
+		biz.ErrEmailAlreadyVerified,
+		biz.InvalidArgument("id", "id is required"),
+	} {
+		_, ok := v1.ErrorReason_value["ERROR_REASON_"+err.Reason]
+		assert.True(t, ok, err.Reason)
+		_, ok = kindCodes[err.Kind]
+		assert.True(t, ok, err.Reason)
+	}
+}
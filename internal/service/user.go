@@ -3,10 +3,330 @@ package service
 import (
 	"connect-go-example/internal/biz"
 	"context"
+	"net"
+	"time"
 
 	v1 "connect-go-example/api/user/v1"
 	"connect-go-example/api/user/v1/userv1connect"
 
 	"connectrpc.com/connect"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// UserService 实现 Connect 服务
+type UserService struct {
+	uc      *biz.UserUseCase
+	search  *biz.SearchUseCase
+	account *biz.AccountUseCase
+	l       *zap.Logger
+}
+
+// 显式接口检查
+var _ userv1connect.UserServiceHandler = (*UserService)(nil)
+
+func NewUserService(uc *biz.UserUseCase, search *biz.SearchUseCase, account *biz.AccountUseCase, logger *zap.Logger) userv1connect.UserServiceHandler {
+	return &UserService{
+		uc:      uc,
+		search:  search,
+		account: account,
+		l:       logger,
+	}
+}
+
+func (s *UserService) SignIn(ctx context.Context, c *connect.Request[v1.SignInRequest]) (*connect.Response[v1.SignInResponse], error) {
+	res, err := s.uc.SignIn(
+		ctx,
+		biz.SignInRequest{
+			Code:  c.Msg.Code,
+			State: c.Msg.State,
+		},
+	)
+	if err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	response := &v1.SignInResponse{
+		State: res.State,
+		Data:  res.Data,
+	}
+
+	return connect.NewResponse(response), nil
+}
+
+func (s *UserService) Register(ctx context.Context, c *connect.Request[v1.RegisterRequest]) (*connect.Response[v1.RegisterResponse], error) {
+	user, err := s.uc.Register(ctx, biz.RegisterRequest{
+		Username: c.Msg.Username,
+		Password: c.Msg.Password,
+	})
+	if err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	return connect.NewResponse(&v1.RegisterResponse{
+		Id:       user.ID,
+		Username: user.Username,
+	}), nil
+}
+
+func (s *UserService) PasswordSignIn(ctx context.Context, c *connect.Request[v1.PasswordSignInRequest]) (*connect.Response[v1.PasswordSignInResponse], error) {
+	res, err := s.uc.PasswordSignIn(ctx, biz.PasswordSignInRequest{
+		Username:     c.Msg.Username,
+		Password:     c.Msg.Password,
+		IP:           peerIP(c.Peer()),
+		ForwardedFor: c.Header().Get("X-Forwarded-For"),
+		UserAgent:    c.Header().Get("User-Agent"),
+	})
+	if err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	if challenge := res.Challenge; challenge != nil {
+		return connect.NewResponse(&v1.PasswordSignInResponse{
+			Id:                challenge.UserID,
+			Username:          challenge.Username,
+			MfaRequired:       true,
+			MfaChallengeToken: challenge.Token,
+		}), nil
+	}
+
+	session := res.Session
+	return connect.NewResponse(&v1.PasswordSignInResponse{
+		Id:           session.UserID,
+		Username:     session.Username,
+		SessionId:    session.ID,
+		RefreshToken: session.RefreshToken,
+		ExpiresIn:    int64(time.Until(session.ExpiresAt).Seconds()),
+	}), nil
+}
+
+func (s *UserService) VerifyMFA(ctx context.Context, c *connect.Request[v1.VerifyMFARequest]) (*connect.Response[v1.VerifyMFAResponse], error) {
+	session, err := s.uc.VerifyMFA(ctx, biz.VerifyMFARequest{
+		ChallengeToken: c.Msg.ChallengeToken,
+		Code:           c.Msg.GetCode(),
+		RecoveryCode:   c.Msg.GetRecoveryCode(),
+		IP:             peerIP(c.Peer()),
+		ForwardedFor:   c.Header().Get("X-Forwarded-For"),
+		UserAgent:      c.Header().Get("User-Agent"),
+	})
+	if err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	return connect.NewResponse(&v1.VerifyMFAResponse{
+		Id:           session.UserID,
+		Username:     session.Username,
+		SessionId:    session.ID,
+		RefreshToken: session.RefreshToken,
+		ExpiresIn:    int64(time.Until(session.ExpiresAt).Seconds()),
+	}), nil
+}
+
+func (s *UserService) EnrollTOTP(ctx context.Context, c *connect.Request[v1.EnrollTOTPRequest]) (*connect.Response[v1.EnrollTOTPResponse], error) {
+	enrollment, err := s.uc.EnrollTOTP(ctx)
+	if err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	return connect.NewResponse(&v1.EnrollTOTPResponse{
+		Secret:     enrollment.Secret,
+		OtpauthUri: enrollment.URI,
+	}), nil
+}
+
+func (s *UserService) ConfirmTOTP(ctx context.Context, c *connect.Request[v1.ConfirmTOTPRequest]) (*connect.Response[v1.ConfirmTOTPResponse], error) {
+	codes, err := s.uc.ConfirmTOTP(ctx, c.Msg.Code)
+	if err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	return connect.NewResponse(&v1.ConfirmTOTPResponse{RecoveryCodes: codes}), nil
+}
+
+func (s *UserService) RegenerateRecoveryCodes(ctx context.Context, c *connect.Request[v1.RegenerateRecoveryCodesRequest]) (*connect.Response[v1.RegenerateRecoveryCodesResponse], error) {
+	codes, err := s.uc.RegenerateRecoveryCodes(ctx, c.Msg.Code)
+	if err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	return connect.NewResponse(&v1.RegenerateRecoveryCodesResponse{RecoveryCodes: codes}), nil
+}
+
+func (s *UserService) RequestPasswordReset(ctx context.Context, c *connect.Request[v1.RequestPasswordResetRequest]) (*connect.Response[v1.RequestPasswordResetResponse], error) {
+	if err := s.account.RequestPasswordReset(ctx, c.Msg.Email); err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	return connect.NewResponse(&v1.RequestPasswordResetResponse{}), nil
+}
+
+func (s *UserService) ResetPassword(ctx context.Context, c *connect.Request[v1.ResetPasswordRequest]) (*connect.Response[v1.ResetPasswordResponse], error) {
+	if err := s.account.ResetPassword(ctx, c.Msg.Token, c.Msg.NewPassword); err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	return connect.NewResponse(&v1.ResetPasswordResponse{}), nil
+}
+
+func (s *UserService) SendVerificationEmail(ctx context.Context, c *connect.Request[v1.SendVerificationEmailRequest]) (*connect.Response[v1.SendVerificationEmailResponse], error) {
+	if err := s.account.SendVerificationEmail(ctx); err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	return connect.NewResponse(&v1.SendVerificationEmailResponse{}), nil
+}
+
+func (s *UserService) VerifyEmail(ctx context.Context, c *connect.Request[v1.VerifyEmailRequest]) (*connect.Response[v1.VerifyEmailResponse], error) {
+	if err := s.account.VerifyEmail(ctx, c.Msg.Token); err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	return connect.NewResponse(&v1.VerifyEmailResponse{}), nil
+}
+
+func (s *UserService) RefreshToken(ctx context.Context, c *connect.Request[v1.RefreshTokenRequest]) (*connect.Response[v1.RefreshTokenResponse], error) {
+	session, err := s.uc.RefreshToken(ctx, c.Msg.RefreshToken)
+	if err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	return connect.NewResponse(&v1.RefreshTokenResponse{
+		SessionId:    session.ID,
+		RefreshToken: session.RefreshToken,
+		ExpiresIn:    int64(time.Until(session.ExpiresAt).Seconds()),
+	}), nil
+}
+
+func (s *UserService) SignOut(ctx context.Context, c *connect.Request[v1.SignOutRequest]) (*connect.Response[v1.SignOutResponse], error) {
+	// 未显式指定会话时，退出当前调用方所使用的会话
+	sessionID := c.Msg.SessionId
+	if principal, ok := biz.PrincipalFromContext(ctx); ok && sessionID == "" {
+		sessionID = principal.SessionID
+	}
+	if sessionID == "" {
+		return nil, invalidArgument("session_id", "session id is required")
+	}
+
+	if err := s.uc.SignOut(ctx, sessionID, c.Msg.Everywhere); err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	return connect.NewResponse(&v1.SignOutResponse{}), nil
+}
+
+func (s *UserService) GetUser(ctx context.Context, c *connect.Request[v1.GetUserRequest]) (*connect.Response[v1.GetUserResponse], error) {
+	user, err := s.uc.GetUser(ctx, c.Msg.Id)
+	if err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	return connect.NewResponse(&v1.GetUserResponse{User: toProtoUser(user)}), nil
+}
+
+func (s *UserService) UpdateUser(ctx context.Context, c *connect.Request[v1.UpdateUserRequest]) (*connect.Response[v1.UpdateUserResponse], error) {
+	user := c.Msg.User
+	req := biz.UpdateUserRequest{ID: user.Id}
+	// update_mask 只能包含以下字段，已由 ValidationInterceptor 校验
+	for _, path := range c.Msg.UpdateMask.Paths {
+		switch path {
+		case "nickname":
+			req.Nickname = &user.Nickname
+		case "email":
+			req.Email = &user.Email
+		case "avatar":
+			req.Avatar = &user.Avatar
+		}
+	}
+
+	updated, err := s.uc.UpdateUser(ctx, req)
+	if err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	return connect.NewResponse(&v1.UpdateUserResponse{User: toProtoUser(updated)}), nil
+}
+
+func (s *UserService) DeleteUser(ctx context.Context, c *connect.Request[v1.DeleteUserRequest]) (*connect.Response[v1.DeleteUserResponse], error) {
+	if err := s.uc.DeleteUser(ctx, c.Msg.Id); err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	return connect.NewResponse(&v1.DeleteUserResponse{}), nil
+}
+
+func (s *UserService) UnlockUser(ctx context.Context, c *connect.Request[v1.UnlockUserRequest]) (*connect.Response[v1.UnlockUserResponse], error) {
+	if err := s.uc.UnlockUser(ctx, c.Msg.Id); err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	return connect.NewResponse(&v1.UnlockUserResponse{}), nil
+}
+
+func (s *UserService) ListUsers(ctx context.Context, c *connect.Request[v1.ListUsersRequest]) (*connect.Response[v1.ListUsersResponse], error) {
+	res, err := s.uc.ListUsers(ctx, biz.ListUsersRequest{
+		PageSize:  int(c.Msg.PageSize),
+		PageToken: c.Msg.PageToken,
+	})
+	if err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	users := make([]*v1.User, 0, len(res.Users))
+	for _, user := range res.Users {
+		users = append(users, toProtoUser(user))
+	}
+	return connect.NewResponse(&v1.ListUsersResponse{
+		Users:         users,
+		NextPageToken: res.NextPageToken,
+	}), nil
+}
+
+func (s *UserService) SearchUsers(ctx context.Context, c *connect.Request[v1.SearchUsersRequest]) (*connect.Response[v1.SearchUsersResponse], error) {
+	res, err := s.search.SearchUsers(ctx, biz.SearchUsersRequest{
+		Query:     c.Msg.Query,
+		PageSize:  int(c.Msg.PageSize),
+		PageToken: c.Msg.PageToken,
+	})
+	if err != nil {
+		return nil, s.toConnectError(err)
+	}
+
+	hits := make([]*v1.SearchUsersResponse_Hit, 0, len(res.Hits))
+	for _, hit := range res.Hits {
+		highlights := make(map[string]*v1.SearchUsersResponse_Highlight, len(hit.Highlights))
+		for field, fragments := range hit.Highlights {
+			highlights[field] = &v1.SearchUsersResponse_Highlight{Fragments: fragments}
+		}
+		hits = append(hits, &v1.SearchUsersResponse_Hit{
+			User:       toProtoUser(hit.User),
+			Score:      float32(hit.Score),
+			Highlights: highlights,
+		})
+	}
+	return connect.NewResponse(&v1.SearchUsersResponse{
+		Hits:          hits,
+		Total:         res.Total,
+		NextPageToken: res.NextPageToken,
+	}), nil
+}
+
+func toProtoUser(user *biz.UserInfo) *v1.User {
+	return &v1.User{
+		Id:            user.ID,
+		Username:      user.Username,
+		Nickname:      user.Nickname,
+		Email:         user.Email,
+		Avatar:        user.Avatar,
+		CreateTime:    timestamppb.New(user.CreatedAt),
+		UpdateTime:    timestamppb.New(user.UpdatedAt),
+		EmailVerified: user.EmailVerified,
+	}
+}
+
+// peerIP 返回对端地址中的 IP，解析失败时原样返回
+func peerIP(peer connect.Peer) string {
+	if host, _, err := net.SplitHostPort(peer.Addr); err == nil {
+		return host
+	}
+	return peer.Addr
+}